@@ -22,6 +22,7 @@ import (
 	"fmt"
 
 	contourv1beta1 "github.com/projectcontour/contour/apis/generated/clientset/versioned/typed/contour/v1beta1"
+	networkingv1alpha1 "github.com/projectcontour/contour/apis/generated/clientset/versioned/typed/networking/v1alpha1"
 	projectcontourv1 "github.com/projectcontour/contour/apis/generated/clientset/versioned/typed/projectcontour/v1"
 	discovery "k8s.io/client-go/discovery"
 	rest "k8s.io/client-go/rest"
@@ -31,6 +32,7 @@ import (
 type Interface interface {
 	Discovery() discovery.DiscoveryInterface
 	ContourV1beta1() contourv1beta1.ContourV1beta1Interface
+	NetworkingV1alpha1() networkingv1alpha1.NetworkingV1alpha1Interface
 	ProjectcontourV1() projectcontourv1.ProjectcontourV1Interface
 }
 
@@ -38,8 +40,9 @@ type Interface interface {
 // version included in a Clientset.
 type Clientset struct {
 	*discovery.DiscoveryClient
-	contourV1beta1   *contourv1beta1.ContourV1beta1Client
-	projectcontourV1 *projectcontourv1.ProjectcontourV1Client
+	contourV1beta1     *contourv1beta1.ContourV1beta1Client
+	networkingV1alpha1 *networkingv1alpha1.NetworkingV1alpha1Client
+	projectcontourV1   *projectcontourv1.ProjectcontourV1Client
 }
 
 // ContourV1beta1 retrieves the ContourV1beta1Client
@@ -47,6 +50,11 @@ func (c *Clientset) ContourV1beta1() contourv1beta1.ContourV1beta1Interface {
 	return c.contourV1beta1
 }
 
+// NetworkingV1alpha1 retrieves the NetworkingV1alpha1Client
+func (c *Clientset) NetworkingV1alpha1() networkingv1alpha1.NetworkingV1alpha1Interface {
+	return c.networkingV1alpha1
+}
+
 // ProjectcontourV1 retrieves the ProjectcontourV1Client
 func (c *Clientset) ProjectcontourV1() projectcontourv1.ProjectcontourV1Interface {
 	return c.projectcontourV1
@@ -77,6 +85,10 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 	if err != nil {
 		return nil, err
 	}
+	cs.networkingV1alpha1, err = networkingv1alpha1.NewForConfig(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
 	cs.projectcontourV1, err = projectcontourv1.NewForConfig(&configShallowCopy)
 	if err != nil {
 		return nil, err
@@ -94,6 +106,7 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 func NewForConfigOrDie(c *rest.Config) *Clientset {
 	var cs Clientset
 	cs.contourV1beta1 = contourv1beta1.NewForConfigOrDie(c)
+	cs.networkingV1alpha1 = networkingv1alpha1.NewForConfigOrDie(c)
 	cs.projectcontourV1 = projectcontourv1.NewForConfigOrDie(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClientForConfigOrDie(c)
@@ -104,6 +117,7 @@ func NewForConfigOrDie(c *rest.Config) *Clientset {
 func New(c rest.Interface) *Clientset {
 	var cs Clientset
 	cs.contourV1beta1 = contourv1beta1.New(c)
+	cs.networkingV1alpha1 = networkingv1alpha1.New(c)
 	cs.projectcontourV1 = projectcontourv1.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
@@ -22,6 +22,8 @@ import (
 	clientset "github.com/projectcontour/contour/apis/generated/clientset/versioned"
 	contourv1beta1 "github.com/projectcontour/contour/apis/generated/clientset/versioned/typed/contour/v1beta1"
 	fakecontourv1beta1 "github.com/projectcontour/contour/apis/generated/clientset/versioned/typed/contour/v1beta1/fake"
+	networkingv1alpha1 "github.com/projectcontour/contour/apis/generated/clientset/versioned/typed/networking/v1alpha1"
+	fakenetworkingv1alpha1 "github.com/projectcontour/contour/apis/generated/clientset/versioned/typed/networking/v1alpha1/fake"
 	projectcontourv1 "github.com/projectcontour/contour/apis/generated/clientset/versioned/typed/projectcontour/v1"
 	fakeprojectcontourv1 "github.com/projectcontour/contour/apis/generated/clientset/versioned/typed/projectcontour/v1/fake"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -83,6 +85,11 @@ func (c *Clientset) ContourV1beta1() contourv1beta1.ContourV1beta1Interface {
 	return &fakecontourv1beta1.FakeContourV1beta1{Fake: &c.Fake}
 }
 
+// NetworkingV1alpha1 retrieves the NetworkingV1alpha1Client
+func (c *Clientset) NetworkingV1alpha1() networkingv1alpha1.NetworkingV1alpha1Interface {
+	return &fakenetworkingv1alpha1.FakeNetworkingV1alpha1{Fake: &c.Fake}
+}
+
 // ProjectcontourV1 retrieves the ProjectcontourV1Client
 func (c *Clientset) ProjectcontourV1() projectcontourv1.ProjectcontourV1Interface {
 	return &fakeprojectcontourv1.FakeProjectcontourV1{Fake: &c.Fake}
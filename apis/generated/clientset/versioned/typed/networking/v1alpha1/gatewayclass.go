@@ -0,0 +1,180 @@
+/*
+Copyright 2019 VMware
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"time"
+
+	scheme "github.com/projectcontour/contour/apis/generated/clientset/versioned/scheme"
+	v1alpha1 "github.com/projectcontour/contour/apis/networking/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// GatewayClassesGetter has a method to return a GatewayClassInterface.
+// A group's client should implement this interface.
+type GatewayClassesGetter interface {
+	GatewayClasses() GatewayClassInterface
+}
+
+// GatewayClassInterface has methods to work with GatewayClass resources.
+type GatewayClassInterface interface {
+	Create(*v1alpha1.GatewayClass) (*v1alpha1.GatewayClass, error)
+	Update(*v1alpha1.GatewayClass) (*v1alpha1.GatewayClass, error)
+	UpdateStatus(*v1alpha1.GatewayClass) (*v1alpha1.GatewayClass, error)
+	Delete(name string, options *v1.DeleteOptions) error
+	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
+	Get(name string, options v1.GetOptions) (*v1alpha1.GatewayClass, error)
+	List(opts v1.ListOptions) (*v1alpha1.GatewayClassList, error)
+	Watch(opts v1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.GatewayClass, err error)
+	GatewayClassExpansion
+}
+
+// gatewayClasses implements GatewayClassInterface
+type gatewayClasses struct {
+	client rest.Interface
+}
+
+// newGatewayClasses returns a GatewayClasses
+func newGatewayClasses(c *NetworkingV1alpha1Client) *gatewayClasses {
+	return &gatewayClasses{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the gatewayClass, and returns the corresponding gatewayClass object, and an error if there is any.
+func (c *gatewayClasses) Get(name string, options v1.GetOptions) (result *v1alpha1.GatewayClass, err error) {
+	result = &v1alpha1.GatewayClass{}
+	err = c.client.Get().
+		Resource("gatewayclasses").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of GatewayClasses that match those selectors.
+func (c *gatewayClasses) List(opts v1.ListOptions) (result *v1alpha1.GatewayClassList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.GatewayClassList{}
+	err = c.client.Get().
+		Resource("gatewayclasses").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested gatewayClasses.
+func (c *gatewayClasses) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("gatewayclasses").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch()
+}
+
+// Create takes the representation of a gatewayClass and creates it.  Returns the server's representation of the gatewayClass, and an error, if there is any.
+func (c *gatewayClasses) Create(gatewayClass *v1alpha1.GatewayClass) (result *v1alpha1.GatewayClass, err error) {
+	result = &v1alpha1.GatewayClass{}
+	err = c.client.Post().
+		Resource("gatewayclasses").
+		Body(gatewayClass).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a gatewayClass and updates it. Returns the server's representation of the gatewayClass, and an error, if there is any.
+func (c *gatewayClasses) Update(gatewayClass *v1alpha1.GatewayClass) (result *v1alpha1.GatewayClass, err error) {
+	result = &v1alpha1.GatewayClass{}
+	err = c.client.Put().
+		Resource("gatewayclasses").
+		Name(gatewayClass.Name).
+		Body(gatewayClass).
+		Do().
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+
+func (c *gatewayClasses) UpdateStatus(gatewayClass *v1alpha1.GatewayClass) (result *v1alpha1.GatewayClass, err error) {
+	result = &v1alpha1.GatewayClass{}
+	err = c.client.Put().
+		Resource("gatewayclasses").
+		Name(gatewayClass.Name).
+		SubResource("status").
+		Body(gatewayClass).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the gatewayClass and deletes it. Returns an error if one occurs.
+func (c *gatewayClasses) Delete(name string, options *v1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("gatewayclasses").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *gatewayClasses) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	var timeout time.Duration
+	if listOptions.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("gatewayclasses").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched gatewayClass.
+func (c *gatewayClasses) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.GatewayClass, err error) {
+	result = &v1alpha1.GatewayClass{}
+	err = c.client.Patch(pt).
+		Resource("gatewayclasses").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
@@ -32,10 +32,18 @@ func (c *FakeProjectcontourV1) HTTPProxies(namespace string) v1.HTTPProxyInterfa
 	return &FakeHTTPProxies{c, namespace}
 }
 
+func (c *FakeProjectcontourV1) ServiceDelegations(namespace string) v1.ServiceDelegationInterface {
+	return &FakeServiceDelegations{c, namespace}
+}
+
 func (c *FakeProjectcontourV1) TLSCertificateDelegations(namespace string) v1.TLSCertificateDelegationInterface {
 	return &FakeTLSCertificateDelegations{c, namespace}
 }
 
+func (c *FakeProjectcontourV1) Upstreams(namespace string) v1.UpstreamInterface {
+	return &FakeUpstreams{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeProjectcontourV1) RESTClient() rest.Interface {
@@ -20,4 +20,8 @@ package v1
 
 type HTTPProxyExpansion interface{}
 
+type ServiceDelegationExpansion interface{}
+
 type TLSCertificateDelegationExpansion interface{}
+
+type UpstreamExpansion interface{}
@@ -27,7 +27,9 @@ import (
 type ProjectcontourV1Interface interface {
 	RESTClient() rest.Interface
 	HTTPProxiesGetter
+	ServiceDelegationsGetter
 	TLSCertificateDelegationsGetter
+	UpstreamsGetter
 }
 
 // ProjectcontourV1Client is used to interact with features provided by the projectcontour.io group.
@@ -39,10 +41,18 @@ func (c *ProjectcontourV1Client) HTTPProxies(namespace string) HTTPProxyInterfac
 	return newHTTPProxies(c, namespace)
 }
 
+func (c *ProjectcontourV1Client) ServiceDelegations(namespace string) ServiceDelegationInterface {
+	return newServiceDelegations(c, namespace)
+}
+
 func (c *ProjectcontourV1Client) TLSCertificateDelegations(namespace string) TLSCertificateDelegationInterface {
 	return newTLSCertificateDelegations(c, namespace)
 }
 
+func (c *ProjectcontourV1Client) Upstreams(namespace string) UpstreamInterface {
+	return newUpstreams(c, namespace)
+}
+
 // NewForConfig creates a new ProjectcontourV1Client for the given config.
 func NewForConfig(c *rest.Config) (*ProjectcontourV1Client, error) {
 	config := *c
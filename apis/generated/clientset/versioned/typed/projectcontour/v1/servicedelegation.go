@@ -0,0 +1,174 @@
+/*
+Copyright 2019 VMware
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"time"
+
+	scheme "github.com/projectcontour/contour/apis/generated/clientset/versioned/scheme"
+	v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// ServiceDelegationsGetter has a method to return a ServiceDelegationInterface.
+// A group's client should implement this interface.
+type ServiceDelegationsGetter interface {
+	ServiceDelegations(namespace string) ServiceDelegationInterface
+}
+
+// ServiceDelegationInterface has methods to work with ServiceDelegation resources.
+type ServiceDelegationInterface interface {
+	Create(*v1.ServiceDelegation) (*v1.ServiceDelegation, error)
+	Update(*v1.ServiceDelegation) (*v1.ServiceDelegation, error)
+	Delete(name string, options *metav1.DeleteOptions) error
+	DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error
+	Get(name string, options metav1.GetOptions) (*v1.ServiceDelegation, error)
+	List(opts metav1.ListOptions) (*v1.ServiceDelegationList, error)
+	Watch(opts metav1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.ServiceDelegation, err error)
+	ServiceDelegationExpansion
+}
+
+// serviceDelegations implements ServiceDelegationInterface
+type serviceDelegations struct {
+	client rest.Interface
+	ns     string
+}
+
+// newServiceDelegations returns a ServiceDelegations
+func newServiceDelegations(c *ProjectcontourV1Client, namespace string) *serviceDelegations {
+	return &serviceDelegations{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the serviceDelegation, and returns the corresponding serviceDelegation object, and an error if there is any.
+func (c *serviceDelegations) Get(name string, options metav1.GetOptions) (result *v1.ServiceDelegation, err error) {
+	result = &v1.ServiceDelegation{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("servicedelegations").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of ServiceDelegations that match those selectors.
+func (c *serviceDelegations) List(opts metav1.ListOptions) (result *v1.ServiceDelegationList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.ServiceDelegationList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("servicedelegations").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested serviceDelegations.
+func (c *serviceDelegations) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("servicedelegations").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch()
+}
+
+// Create takes the representation of a serviceDelegation and creates it.  Returns the server's representation of the serviceDelegation, and an error, if there is any.
+func (c *serviceDelegations) Create(serviceDelegation *v1.ServiceDelegation) (result *v1.ServiceDelegation, err error) {
+	result = &v1.ServiceDelegation{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("servicedelegations").
+		Body(serviceDelegation).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a serviceDelegation and updates it. Returns the server's representation of the serviceDelegation, and an error, if there is any.
+func (c *serviceDelegations) Update(serviceDelegation *v1.ServiceDelegation) (result *v1.ServiceDelegation, err error) {
+	result = &v1.ServiceDelegation{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("servicedelegations").
+		Name(serviceDelegation.Name).
+		Body(serviceDelegation).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the serviceDelegation and deletes it. Returns an error if one occurs.
+func (c *serviceDelegations) Delete(name string, options *metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("servicedelegations").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *serviceDelegations) DeleteCollection(options *metav1.DeleteOptions, listOptions metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOptions.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("servicedelegations").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched serviceDelegation.
+func (c *serviceDelegations) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1.ServiceDelegation, err error) {
+	result = &v1.ServiceDelegation{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("servicedelegations").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
@@ -26,6 +26,7 @@ import (
 	versioned "github.com/projectcontour/contour/apis/generated/clientset/versioned"
 	contour "github.com/projectcontour/contour/apis/generated/informers/externalversions/contour"
 	internalinterfaces "github.com/projectcontour/contour/apis/generated/informers/externalversions/internalinterfaces"
+	networking "github.com/projectcontour/contour/apis/generated/informers/externalversions/networking"
 	projectcontour "github.com/projectcontour/contour/apis/generated/informers/externalversions/projectcontour"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
@@ -174,6 +175,7 @@ type SharedInformerFactory interface {
 	WaitForCacheSync(stopCh <-chan struct{}) map[reflect.Type]bool
 
 	Contour() contour.Interface
+	Networking() networking.Interface
 	Projectcontour() projectcontour.Interface
 }
 
@@ -181,6 +183,10 @@ func (f *sharedInformerFactory) Contour() contour.Interface {
 	return contour.New(f, f.namespace, f.tweakListOptions)
 }
 
+func (f *sharedInformerFactory) Networking() networking.Interface {
+	return networking.New(f, f.namespace, f.tweakListOptions)
+}
+
 func (f *sharedInformerFactory) Projectcontour() projectcontour.Interface {
 	return projectcontour.New(f, f.namespace, f.tweakListOptions)
 }
@@ -22,6 +22,7 @@ import (
 	"fmt"
 
 	v1beta1 "github.com/projectcontour/contour/apis/contour/v1beta1"
+	v1alpha1 "github.com/projectcontour/contour/apis/networking/v1alpha1"
 	v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
@@ -59,11 +60,23 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	case v1beta1.SchemeGroupVersion.WithResource("tlscertificatedelegations"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Contour().V1beta1().TLSCertificateDelegations().Informer()}, nil
 
+		// Group=networking.x-k8s.io, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithResource("gateways"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Networking().V1alpha1().Gateways().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("gatewayclasses"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Networking().V1alpha1().GatewayClasses().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("httproutes"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Networking().V1alpha1().HTTPRoutes().Informer()}, nil
+
 		// Group=projectcontour.io, Version=v1
 	case v1.SchemeGroupVersion.WithResource("httpproxies"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Projectcontour().V1().HTTPProxies().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("servicedelegations"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Projectcontour().V1().ServiceDelegations().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("tlscertificatedelegations"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Projectcontour().V1().TLSCertificateDelegations().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("upstreams"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Projectcontour().V1().Upstreams().Informer()}, nil
 
 	}
 
@@ -26,8 +26,12 @@ import (
 type Interface interface {
 	// HTTPProxies returns a HTTPProxyInformer.
 	HTTPProxies() HTTPProxyInformer
+	// ServiceDelegations returns a ServiceDelegationInformer.
+	ServiceDelegations() ServiceDelegationInformer
 	// TLSCertificateDelegations returns a TLSCertificateDelegationInformer.
 	TLSCertificateDelegations() TLSCertificateDelegationInformer
+	// Upstreams returns a UpstreamInformer.
+	Upstreams() UpstreamInformer
 }
 
 type version struct {
@@ -46,7 +50,17 @@ func (v *version) HTTPProxies() HTTPProxyInformer {
 	return &hTTPProxyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// ServiceDelegations returns a ServiceDelegationInformer.
+func (v *version) ServiceDelegations() ServiceDelegationInformer {
+	return &serviceDelegationInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // TLSCertificateDelegations returns a TLSCertificateDelegationInformer.
 func (v *version) TLSCertificateDelegations() TLSCertificateDelegationInformer {
 	return &tLSCertificateDelegationInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// Upstreams returns a UpstreamInformer.
+func (v *version) Upstreams() UpstreamInformer {
+	return &upstreamInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -26,6 +26,14 @@ type HTTPProxyListerExpansion interface{}
 // HTTPProxyNamespaceLister.
 type HTTPProxyNamespaceListerExpansion interface{}
 
+// ServiceDelegationListerExpansion allows custom methods to be added to
+// ServiceDelegationLister.
+type ServiceDelegationListerExpansion interface{}
+
+// ServiceDelegationNamespaceListerExpansion allows custom methods to be added to
+// ServiceDelegationNamespaceLister.
+type ServiceDelegationNamespaceListerExpansion interface{}
+
 // TLSCertificateDelegationListerExpansion allows custom methods to be added to
 // TLSCertificateDelegationLister.
 type TLSCertificateDelegationListerExpansion interface{}
@@ -33,3 +41,11 @@ type TLSCertificateDelegationListerExpansion interface{}
 // TLSCertificateDelegationNamespaceListerExpansion allows custom methods to be added to
 // TLSCertificateDelegationNamespaceLister.
 type TLSCertificateDelegationNamespaceListerExpansion interface{}
+
+// UpstreamListerExpansion allows custom methods to be added to
+// UpstreamLister.
+type UpstreamListerExpansion interface{}
+
+// UpstreamNamespaceListerExpansion allows custom methods to be added to
+// UpstreamNamespaceLister.
+type UpstreamNamespaceListerExpansion interface{}
@@ -0,0 +1,94 @@
+/*
+Copyright 2019 VMware
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ServiceDelegationLister helps list ServiceDelegations.
+type ServiceDelegationLister interface {
+	// List lists all ServiceDelegations in the indexer.
+	List(selector labels.Selector) (ret []*v1.ServiceDelegation, err error)
+	// ServiceDelegations returns an object that can list and get ServiceDelegations.
+	ServiceDelegations(namespace string) ServiceDelegationNamespaceLister
+	ServiceDelegationListerExpansion
+}
+
+// serviceDelegationLister implements the ServiceDelegationLister interface.
+type serviceDelegationLister struct {
+	indexer cache.Indexer
+}
+
+// NewServiceDelegationLister returns a new ServiceDelegationLister.
+func NewServiceDelegationLister(indexer cache.Indexer) ServiceDelegationLister {
+	return &serviceDelegationLister{indexer: indexer}
+}
+
+// List lists all ServiceDelegations in the indexer.
+func (s *serviceDelegationLister) List(selector labels.Selector) (ret []*v1.ServiceDelegation, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.ServiceDelegation))
+	})
+	return ret, err
+}
+
+// ServiceDelegations returns an object that can list and get ServiceDelegations.
+func (s *serviceDelegationLister) ServiceDelegations(namespace string) ServiceDelegationNamespaceLister {
+	return serviceDelegationNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// ServiceDelegationNamespaceLister helps list and get ServiceDelegations.
+type ServiceDelegationNamespaceLister interface {
+	// List lists all ServiceDelegations in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1.ServiceDelegation, err error)
+	// Get retrieves the ServiceDelegation from the indexer for a given namespace and name.
+	Get(name string) (*v1.ServiceDelegation, error)
+	ServiceDelegationNamespaceListerExpansion
+}
+
+// serviceDelegationNamespaceLister implements the ServiceDelegationNamespaceLister
+// interface.
+type serviceDelegationNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all ServiceDelegations in the indexer for a given namespace.
+func (s serviceDelegationNamespaceLister) List(selector labels.Selector) (ret []*v1.ServiceDelegation, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.ServiceDelegation))
+	})
+	return ret, err
+}
+
+// Get retrieves the ServiceDelegation from the indexer for a given namespace and name.
+func (s serviceDelegationNamespaceLister) Get(name string) (*v1.ServiceDelegation, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("servicedelegation"), name)
+	}
+	return obj.(*v1.ServiceDelegation), nil
+}
@@ -0,0 +1,140 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GatewaySpec describes the listeners of a Gateway and
+// the routes bound to them.
+type GatewaySpec struct {
+	// GatewayClassName is the name of the GatewayClass of this Gateway.
+	// +kubebuilder:validation:MinLength=1
+	GatewayClassName string `json:"gatewayClassName"`
+	// Listeners accept traffic for the Gateway's routes.
+	// +kubebuilder:validation:MinItems=1
+	Listeners []Listener `json:"listeners"`
+}
+
+// ProtocolType is the protocol accepted by a Listener.
+type ProtocolType string
+
+const (
+	HTTPProtocolType  ProtocolType = "HTTP"
+	HTTPSProtocolType ProtocolType = "HTTPS"
+)
+
+// Listener accepts traffic for the routes bound to it.
+type Listener struct {
+	// Hostname restricts the listener to requests for this host.
+	// Requests for any host are accepted if it is not set.
+	// +optional
+	Hostname string `json:"hostname,omitempty"`
+	// Port is the network port the listener accepts traffic on.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+	// Protocol is the protocol accepted by the listener, one of HTTP or HTTPS.
+	// +kubebuilder:validation:Enum=HTTP;HTTPS
+	Protocol ProtocolType `json:"protocol"`
+	// TLS configures the listener's TLS termination.
+	// Required if the protocol is HTTPS.
+	// +optional
+	TLS *GatewayTLSConfig `json:"tls,omitempty"`
+	// Routes selects the routes bound to the listener.
+	Routes RouteBindingSelector `json:"routes"`
+}
+
+// GatewayTLSConfig configures a Listener's TLS termination.
+type GatewayTLSConfig struct {
+	// CertificateRef refers to a kubernetes.io/tls Secret in the
+	// Gateway's namespace holding the listener's certificate.
+	CertificateRef LocalObjectReference `json:"certificateRef"`
+}
+
+// LocalObjectReference refers to an object in the same namespace.
+type LocalObjectReference struct {
+	// Group of the referent. Defaults to the core API group.
+	// +optional
+	Group string `json:"group,omitempty"`
+	// Kind of the referent. Defaults to Secret.
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// Name of the referent.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// RouteSelectType selects the namespaces routes may be bound from.
+type RouteSelectType string
+
+const (
+	// RouteSelectAll binds routes from every namespace.
+	RouteSelectAll RouteSelectType = "All"
+	// RouteSelectSame binds routes from the Gateway's namespace.
+	RouteSelectSame RouteSelectType = "Same"
+)
+
+// RouteBindingSelector selects the routes bound to a Listener.
+type RouteBindingSelector struct {
+	// Namespaces selects the namespaces routes are bound from.
+	// +optional
+	Namespaces RouteNamespaces `json:"namespaces,omitempty"`
+	// Selector selects routes by their labels.
+	// Every route is selected if it is not set.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// Kind of the routes to bind. Defaults to HTTPRoute, the only kind supported.
+	// +optional
+	Kind string `json:"kind,omitempty"`
+}
+
+// RouteNamespaces selects the namespaces routes are bound from.
+type RouteNamespaces struct {
+	// From is one of All or Same. Defaults to Same.
+	// +optional
+	// +kubebuilder:validation:Enum=All;Same
+	From RouteSelectType `json:"from,omitempty"`
+}
+
+// GatewayStatus is the status of a Gateway.
+type GatewayStatus struct {
+	// Conditions describe the state of the Gateway.
+	// +optional
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Gateway is a request for traffic to be routed to the routes bound to its listeners.
+// +kubebuilder:resource:path=gateways,shortName=gtw,singular=gateway
+type Gateway struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec GatewaySpec `json:"spec"`
+	// +optional
+	Status GatewayStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GatewayList is a list of Gateways.
+type GatewayList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []Gateway `json:"items"`
+}
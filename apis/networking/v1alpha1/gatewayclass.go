@@ -0,0 +1,97 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GatewayClassSpec describes the controller which implements a class of Gateways.
+type GatewayClassSpec struct {
+	// Controller is the name of the controller managing Gateways of
+	// this class, for example projectcontour.io/contour.
+	// +kubebuilder:validation:MinLength=1
+	Controller string `json:"controller"`
+}
+
+// GatewayClassStatus is the status of a GatewayClass.
+type GatewayClassStatus struct {
+	// Conditions describe the state of the GatewayClass.
+	// +optional
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// ConditionStatus is the status of a Condition.
+type ConditionStatus string
+
+const (
+	ConditionTrue    ConditionStatus = "True"
+	ConditionFalse   ConditionStatus = "False"
+	ConditionUnknown ConditionStatus = "Unknown"
+)
+
+const (
+	// AdmittedCondition is true when a GatewayClass or HTTPRoute
+	// has been accepted by its controller.
+	AdmittedCondition = "Admitted"
+
+	// ReadyCondition is true when each of a Gateway's listeners
+	// has been configured.
+	ReadyCondition = "Ready"
+)
+
+// Condition is an observation of the state of a Gateway API object.
+type Condition struct {
+	// Type of the condition, for example Ready.
+	Type string `json:"type"`
+	// Status of the condition, one of True, False, or Unknown.
+	Status ConditionStatus `json:"status"`
+	// ObservedGeneration is the generation of the object the
+	// condition was computed from.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastTransitionTime is the last time the condition's status changed.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Reason is a CamelCase reason for the condition's status.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Message is a human readable summary of the condition.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GatewayClass describes a class of Gateways and the controller which implements them.
+// +kubebuilder:resource:path=gatewayclasses,scope=Cluster,singular=gatewayclass
+type GatewayClass struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec GatewayClassSpec `json:"spec"`
+	// +optional
+	Status GatewayClassStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GatewayClassList is a list of GatewayClasses.
+type GatewayClassList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []GatewayClass `json:"items"`
+}
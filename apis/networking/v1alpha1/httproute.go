@@ -0,0 +1,131 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HTTPRouteSpec describes how HTTP requests are routed to Services.
+type HTTPRouteSpec struct {
+	// Hostnames are the hosts the route matches. If empty, the
+	// route matches the hostname of each Listener it is bound to.
+	// +optional
+	Hostnames []string `json:"hostnames,omitempty"`
+	// Rules match requests and forward them to Services.
+	// +kubebuilder:validation:MinItems=1
+	Rules []HTTPRouteRule `json:"rules"`
+}
+
+// HTTPRouteRule forwards requests which match any of its
+// matches to its Services.
+type HTTPRouteRule struct {
+	// Matches are the conditions a request must meet. If empty,
+	// every request matches.
+	// +optional
+	Matches []HTTPRouteMatch `json:"matches,omitempty"`
+	// ForwardTo are the Services matching requests are forwarded to.
+	// +kubebuilder:validation:MinItems=1
+	ForwardTo []HTTPRouteForwardTo `json:"forwardTo"`
+}
+
+// HTTPRouteMatch is a set of conditions a request must meet.
+type HTTPRouteMatch struct {
+	// Path matches the request's path.
+	// +optional
+	Path *HTTPPathMatch `json:"path,omitempty"`
+	// Headers matches the request's headers.
+	// +optional
+	Headers *HTTPHeaderMatch `json:"headers,omitempty"`
+}
+
+// PathMatchType is how a path is matched.
+type PathMatchType string
+
+const (
+	PathMatchExact  PathMatchType = "Exact"
+	PathMatchPrefix PathMatchType = "Prefix"
+)
+
+// HTTPPathMatch matches a request's path.
+type HTTPPathMatch struct {
+	// Type is one of Exact or Prefix. Defaults to Prefix.
+	// +optional
+	// +kubebuilder:validation:Enum=Exact;Prefix
+	Type PathMatchType `json:"type,omitempty"`
+	// Value is the path to match.
+	Value string `json:"value"`
+}
+
+// HeaderMatchType is how headers are matched.
+type HeaderMatchType string
+
+const (
+	HeaderMatchExact HeaderMatchType = "Exact"
+)
+
+// HTTPHeaderMatch matches a request's headers.
+type HTTPHeaderMatch struct {
+	// Type is Exact, the only type supported.
+	// +optional
+	// +kubebuilder:validation:Enum=Exact
+	Type HeaderMatchType `json:"type,omitempty"`
+	// Values maps each header name to the value it must have.
+	Values map[string]string `json:"values"`
+}
+
+// HTTPRouteForwardTo is a Service requests are forwarded to.
+type HTTPRouteForwardTo struct {
+	// ServiceName is the name of a Service in the route's namespace.
+	// +kubebuilder:validation:MinLength=1
+	ServiceName string `json:"serviceName"`
+	// Port is the port of the Service.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+	// Weight is the proportion of requests forwarded to the Service.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Weight int32 `json:"weight,omitempty"`
+}
+
+// HTTPRouteStatus is the status of an HTTPRoute.
+type HTTPRouteStatus struct {
+	// Conditions describe the state of the HTTPRoute.
+	// +optional
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// HTTPRoute routes HTTP requests to Services.
+// +kubebuilder:resource:path=httproutes,singular=httproute
+type HTTPRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec HTTPRouteSpec `json:"spec"`
+	// +optional
+	Status HTTPRouteStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// HTTPRouteList is a list of HTTPRoutes.
+type HTTPRouteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []HTTPRoute `json:"items"`
+}
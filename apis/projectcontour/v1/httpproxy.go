@@ -147,6 +147,14 @@ type Route struct {
 	// The load balancing policy for this route.
 	// +optional
 	LoadBalancerPolicy *LoadBalancerPolicy `json:"loadBalancerPolicy,omitempty"`
+	// The outlier detection policy for the services of this route.
+	// A service's own policy takes precedence.
+	// +optional
+	OutlierDetection *OutlierDetection `json:"outlierDetection,omitempty"`
+	// The timeouts of Envoy's connections to the services of this route.
+	// A service's own policy takes precedence.
+	// +optional
+	ConnectionTimeoutPolicy *ConnectionTimeoutPolicy `json:"connectionTimeoutPolicy,omitempty"`
 	// The policy for rewriting the path of the request URL
 	// after the request has been routed to a Service.
 	//
@@ -169,6 +177,9 @@ type TCPProxy struct {
 	LoadBalancerPolicy *LoadBalancerPolicy `json:"loadBalancerPolicy,omitempty"`
 	// Services are the services to proxy traffic
 	Services []Service `json:"services,omitempty"`
+	// The health check policy for the backend services.
+	// +optional
+	HealthCheckPolicy *TCPHealthCheckPolicy `json:"healthCheckPolicy,omitempty"`
 
 	// Include specifies that this tcpproxy should be delegated to another HTTPProxy.
 	// +optional
@@ -189,22 +200,158 @@ type Service struct {
 	// Name is the name of Kubernetes service to proxy traffic.
 	// Names defined here will be used to look up corresponding endpoints which contain the ips to route.
 	Name string `json:"name"`
+	// Kind is the kind of object Name refers to, a Kubernetes Service,
+	// the default, or an Upstream in the HTTPProxy's namespace.
+	// +kubebuilder:validation:Enum=Service;Upstream
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// Namespace is the namespace of the Kubernetes service. If left empty,
+	// the service is looked up in the HTTPProxy's namespace. A service in
+	// another namespace must be delegated to the HTTPProxy's namespace by
+	// a ServiceDelegation.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
 	// Port (defined as Integer) to proxy traffic to since a service can have multiple defined.
 	Port int `json:"port"`
 	// Weight defines percentage of traffic to balance traffic
 	// +optional
 	Weight uint32 `json:"weight,omitempty"`
+	// Protocol is the protocol Envoy uses to connect to the service.
+	// h1 is HTTP/1.1, h2c is cleartext HTTP/2, h2 is HTTP/2 over TLS,
+	// and tls is HTTP/1.1 over TLS. If present, the service's
+	// upstream-protocol annotations are ignored. Only h1 and tls apply
+	// to tcpproxy services.
+	// +kubebuilder:validation:Enum=h1;h2;h2c;tls
+	// +optional
+	Protocol string `json:"protocol,omitempty"`
+	// HTTP2 tunes Envoy's HTTP/2 connections to the service. It
+	// requires the h2 or h2c protocol.
+	// +optional
+	HTTP2 *HTTP2Settings `json:"http2,omitempty"`
 	// UpstreamValidation defines how to verify the backend service's certificate
 	// +optional
 	UpstreamValidation *UpstreamValidation `json:"validation,omitempty"`
 	// If Mirror is true the Service will receive a read only mirror of the traffic for this route.
 	Mirror bool `json:"mirror,omitempty"`
+	// OutlierDetection defines how to eject failing endpoints of this service
+	// +optional
+	OutlierDetection *OutlierDetection `json:"outlierDetection,omitempty"`
+	// CircuitBreakerPolicy defines the circuit breaking limits Envoy applies
+	// to this service. If present, the limits set by the service's annotations
+	// are ignored.
+	// +optional
+	CircuitBreakerPolicy *CircuitBreakerPolicy `json:"circuitBreakerPolicy,omitempty"`
+	// ConnectionTimeoutPolicy defines the timeouts of Envoy's connections
+	// to this service.
+	// +optional
+	ConnectionTimeoutPolicy *ConnectionTimeoutPolicy `json:"connectionTimeoutPolicy,omitempty"`
+}
+
+// HTTP2Settings defines the HTTP/2 settings of Envoy's connections to
+// the upstream service. Settings which are not supplied use Envoy's defaults.
+type HTTP2Settings struct {
+	// The maximum number of concurrent streams on a connection.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=2147483647
+	// +optional
+	MaxConcurrentStreams uint32 `json:"maxConcurrentStreams,omitempty"`
+	// The initial flow control window, in bytes, of each stream.
+	// +kubebuilder:validation:Minimum=65535
+	// +kubebuilder:validation:Maximum=2147483647
+	// +optional
+	InitialStreamWindowSize uint32 `json:"initialStreamWindowSize,omitempty"`
+	// The initial flow control window, in bytes, of each connection.
+	// +kubebuilder:validation:Minimum=65535
+	// +kubebuilder:validation:Maximum=2147483647
+	// +optional
+	InitialConnectionWindowSize uint32 `json:"initialConnectionWindowSize,omitempty"`
+}
+
+// ConnectionTimeoutPolicy defines the timeouts of Envoy's connections to
+// the upstream service. Durations are expressed in the format specified in
+// the ParseDuration documentation, e.g. "5s". Timeouts which are not
+// supplied use Contour's configured defaults.
+type ConnectionTimeoutPolicy struct {
+	// The time allowed to establish a connection to the service.
+	// If not supplied, Contour's configured default, or 250ms, is used.
+	// +optional
+	ConnectTimeout string `json:"connectTimeout,omitempty"`
+	// The time after which a connection to the service with no active
+	// requests is closed. If not supplied, Contour's configured default,
+	// or Envoy's default of 1h, is used.
+	// Does not apply to tcpproxy services.
+	// +optional
+	IdleConnectionTimeout string `json:"idleConnectionTimeout,omitempty"`
+	// The maximum time a connection to the service is kept open, after
+	// which it is drained and closed. If not supplied, Contour's configured
+	// default is used; otherwise connections are not closed for their age.
+	// Does not apply to tcpproxy services.
+	// +optional
+	MaxConnectionDuration string `json:"maxConnectionDuration,omitempty"`
+}
+
+// CircuitBreakerPolicy defines the circuit breaking limits Envoy applies
+// to the upstream service, for each routing priority.
+type CircuitBreakerPolicy struct {
+	// The limits for requests with the default routing priority.
+	// +optional
+	Default *CircuitBreakerThresholds `json:"default,omitempty"`
+	// The limits for requests with the high routing priority.
+	// +optional
+	High *CircuitBreakerThresholds `json:"high,omitempty"`
+}
+
+// CircuitBreakerThresholds defines the circuit breaking limits for a
+// routing priority. Limits which are not supplied use Envoy's default of 1024.
+type CircuitBreakerThresholds struct {
+	// The maximum number of connections that a single Envoy instance
+	// allows to the upstream service.
+	// +optional
+	MaxConnections uint32 `json:"maxConnections,omitempty"`
+	// The maximum number of pending requests that a single Envoy
+	// instance allows to the upstream service.
+	// +optional
+	MaxPendingRequests uint32 `json:"maxPendingRequests,omitempty"`
+	// The maximum number of parallel requests that a single Envoy
+	// instance allows to the upstream service.
+	// +optional
+	MaxRequests uint32 `json:"maxRequests,omitempty"`
+	// The maximum number of parallel retries that a single Envoy
+	// instance allows to the upstream service.
+	// +optional
+	MaxRetries uint32 `json:"maxRetries,omitempty"`
+}
+
+// OutlierDetection defines passive health checking of the upstream service.
+// Endpoints which repeatedly fail requests are ejected from the load
+// balancing set for a period of time.
+type OutlierDetection struct {
+	// The number of consecutive 5xx responses before an endpoint is ejected.
+	// If not supplied, Envoy's default of 5 is used.
+	// +optional
+	Consecutive5xxErrors uint32 `json:"consecutive5xxErrors,omitempty"`
+	// The number of consecutive 502, 503 or 504 responses before an endpoint
+	// is ejected. If not supplied, endpoints are not ejected for gateway errors alone.
+	// +optional
+	ConsecutiveGatewayErrors uint32 `json:"consecutiveGatewayErrors,omitempty"`
+	// The time an endpoint is ejected for, multiplied by the number of times it
+	// has been ejected. Expressed in the format specified in the ParseDuration
+	// documentation, e.g. "30s". If not supplied, Envoy's default of 30s is used.
+	// +optional
+	BaseEjectionTime string `json:"baseEjectionTime,omitempty"`
+	// The maximum percentage of the service's endpoints which can be ejected
+	// at once. If not supplied, Envoy's default of 10% is used.
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxEjectionPercent uint32 `json:"maxEjectionPercent,omitempty"`
 }
 
 // HTTPHealthCheckPolicy defines health checks on the upstream service.
 type HTTPHealthCheckPolicy struct {
-	// HTTP endpoint used to perform health checks on upstream service
-	Path string `json:"path"`
+	// HTTP endpoint used to perform health checks on upstream service.
+	// Required unless GRPC is set.
+	// +optional
+	Path string `json:"path,omitempty"`
 	// The value of the host header in the HTTP health check request.
 	// If left empty (default value), the name "contour-envoy-healthcheck"
 	// will be used.
@@ -221,6 +368,45 @@ type HTTPHealthCheckPolicy struct {
 	// The number of healthy health checks required before a host is marked healthy
 	// +optional
 	HealthyThresholdCount uint32 `json:"healthyThresholdCount"`
+	// If GRPC is set, the upstream service is checked with the standard
+	// gRPC health checking service, grpc.health.v1.Health, rather than
+	// an HTTP request, and Path is ignored. The upstream service must use
+	// the h2 or h2c protocol.
+	// +optional
+	GRPC *GRPCHealthCheck `json:"grpc,omitempty"`
+}
+
+// GRPCHealthCheck defines a gRPC health check on the upstream service.
+type GRPCHealthCheck struct {
+	// The name of the gRPC service to check. If left empty, the overall
+	// health of the upstream server is checked.
+	// +optional
+	ServiceName string `json:"serviceName,omitempty"`
+}
+
+// TCPHealthCheckPolicy defines health checks on the upstream services
+// of a TCPProxy.
+type TCPHealthCheckPolicy struct {
+	// The interval (seconds) between health checks
+	// +optional
+	IntervalSeconds int64 `json:"intervalSeconds"`
+	// The time to wait (seconds) for a health check response
+	// +optional
+	TimeoutSeconds int64 `json:"timeoutSeconds"`
+	// The number of unhealthy health checks required before a host is marked unhealthy
+	// +optional
+	UnhealthyThresholdCount uint32 `json:"unhealthyThresholdCount"`
+	// The number of healthy health checks required before a host is marked healthy
+	// +optional
+	HealthyThresholdCount uint32 `json:"healthyThresholdCount"`
+	// The payload sent to the upstream host once connected. If Send and
+	// Receive are left empty, the health check only connects to the host.
+	// +optional
+	Send string `json:"send,omitempty"`
+	// The payload which must be found in the upstream host's response
+	// for the host to be healthy.
+	// +optional
+	Receive string `json:"receive,omitempty"`
 }
 
 // TimeoutPolicy defines the attributes associated with timeout.
@@ -292,6 +478,13 @@ type PathRewritePolicy struct {
 // LoadBalancerPolicy defines the load balancing policy.
 type LoadBalancerPolicy struct {
 	Strategy string `json:"strategy,omitempty"`
+	// LocalityStrategy controls how Envoy balances traffic between
+	// the zones of a service's endpoints. ZoneAware prefers endpoints
+	// in Envoy's own zone, LocalityWeighted spreads traffic across
+	// zones by their number of endpoints.
+	// +kubebuilder:validation:Enum=ZoneAware;LocalityWeighted
+	// +optional
+	LocalityStrategy string `json:"localityStrategy,omitempty"`
 }
 
 // UpstreamValidation defines how to verify the backend service's certificate
@@ -308,6 +501,50 @@ type Status struct {
 	CurrentStatus string `json:"currentStatus,omitempty"`
 	// +optional
 	Description string `json:"description,omitempty"`
+	// Conditions are the current observations of the object's state.
+	// +optional
+	Conditions []DetailedCondition `json:"conditions,omitempty"`
+}
+
+// ValidCondition is the type of the condition reporting whether
+// Contour considers the object valid.
+const ValidCondition = "Valid"
+
+// ConditionStatus is the status of a condition.
+type ConditionStatus string
+
+const (
+	ConditionTrue    ConditionStatus = "True"
+	ConditionFalse   ConditionStatus = "False"
+	ConditionUnknown ConditionStatus = "Unknown"
+)
+
+// DetailedCondition is an observation of the object's state, along
+// with each of the errors and warnings which led to it.
+type DetailedCondition struct {
+	// Type of the condition, for example Valid.
+	Type string `json:"type"`
+	// Status of the condition, one of True, False, or Unknown.
+	Status ConditionStatus `json:"status"`
+	// ObservedGeneration is the generation of the object the
+	// condition was computed from.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastTransitionTime is the last time the condition's status changed.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Reason is a CamelCase reason for the condition's status.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Message is a human readable summary of the condition.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Errors are the problems which make the object invalid.
+	// +optional
+	Errors []string `json:"errors,omitempty"`
+	// Warnings are problems which do not make the object invalid.
+	// +optional
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // +genclient
@@ -0,0 +1,61 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ServiceDelegationSpec defines the spec of the CRD
+type ServiceDelegationSpec struct {
+	Delegations []DelegatedService `json:"delegations"`
+}
+
+// DelegatedService maps the authority to reference a service
+// in the current namespace to a set of namespaces.
+type DelegatedService struct {
+
+	// required, the name of a service in the current namespace.
+	ServiceName string `json:"serviceName"`
+
+	// required, the namespaces the authority to reference the
+	// service will be delegated to.
+	// If TargetNamespaces is nil or empty, the DelegatedService
+	// is ignored. If the TargetNamespace list contains the character, "*"
+	// the service will be delegated to all namespaces.
+	TargetNamespaces []string `json:"targetNamespaces"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceDelegation permits HTTPProxies in other namespaces to route
+// traffic to Services in the ServiceDelegation's namespace.
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=servicedelegations,shortName=svcdelegation,singular=servicedelegation
+type ServiceDelegation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec ServiceDelegationSpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ServiceDelegationList is a list of ServiceDelegations.
+type ServiceDelegationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []ServiceDelegation `json:"items"`
+}
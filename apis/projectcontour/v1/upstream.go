@@ -0,0 +1,79 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// UpstreamSpec defines the spec of the CRD
+type UpstreamSpec struct {
+	// Endpoints are the addresses Envoy connects to. They must be
+	// either all IP addresses, or all DNS names which Envoy resolves
+	// and keeps resolving.
+	// +kubebuilder:validation:MinItems=1
+	Endpoints []UpstreamEndpoint `json:"endpoints"`
+	// TLS makes Envoy connect to the endpoints over TLS.
+	// +optional
+	TLS *UpstreamTLS `json:"tls,omitempty"`
+}
+
+// UpstreamEndpoint is an address of an Upstream.
+type UpstreamEndpoint struct {
+	// Address is an IP address or DNS name.
+	Address string `json:"address"`
+	// Port is the port of the endpoint. If not supplied, the port of
+	// the HTTPProxy service referring to the Upstream is used.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int `json:"port,omitempty"`
+}
+
+// UpstreamTLS defines how Envoy connects to the endpoints of an
+// Upstream over TLS.
+type UpstreamTLS struct {
+	// SNI is the server name Envoy sends to the endpoints. If not
+	// supplied, and the endpoints are DNS names, the address of the
+	// first endpoint is used.
+	// +optional
+	SNI string `json:"sni,omitempty"`
+	// Validation defines how to verify the endpoints' certificates.
+	// +optional
+	Validation *UpstreamValidation `json:"validation,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Upstream is a set of endpoints outside of Kubernetes, such as legacy
+// hosts, which the services of HTTPProxies in its namespace can route
+// traffic to.
+// +k8s:openapi-gen=true
+// +kubebuilder:resource:path=upstreams,singular=upstream
+type Upstream struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec UpstreamSpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// UpstreamList is a list of Upstreams.
+type UpstreamList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []Upstream `json:"items"`
+}
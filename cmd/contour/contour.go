@@ -21,6 +21,7 @@ import (
 	clientset "github.com/projectcontour/contour/apis/generated/clientset/versioned"
 	"github.com/sirupsen/logrus"
 	kingpin "gopkg.in/alecthomas/kingpin.v2"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	coordinationv1 "k8s.io/client-go/kubernetes/typed/coordination/v1"
 	"k8s.io/client-go/rest"
@@ -65,6 +66,10 @@ func main() {
 
 	serve, serveCtx := registerServe(app)
 
+	validate, validateCtx := registerValidate(app)
+
+	render, renderCtx := registerRender(app)
+
 	args := os.Args[1:]
 	switch kingpin.MustParse(app.Parse(args)) {
 	case bootstrap.FullCommand():
@@ -92,14 +97,18 @@ func main() {
 		_, err := app.Parse(args)
 		check(err)
 		log.Infof("args: %v", args)
-		doServe(log, serveCtx)
+		check(doServe(log, serveCtx))
+	case validate.FullCommand():
+		check(doValidate(validateCtx, os.Stdout))
+	case render.FullCommand():
+		check(doRender(renderCtx, os.Stdout))
 	default:
 		app.Usage(args)
 		os.Exit(2)
 	}
 }
 
-func newClient(kubeconfig string, inCluster bool) (*kubernetes.Clientset, *clientset.Clientset, *coordinationv1.CoordinationV1Client) {
+func newClient(kubeconfig string, inCluster bool) (*kubernetes.Clientset, *clientset.Clientset, *coordinationv1.CoordinationV1Client, dynamic.Interface) {
 	var err error
 	var config *rest.Config
 	if kubeconfig != "" && !inCluster {
@@ -116,8 +125,10 @@ func newClient(kubeconfig string, inCluster bool) (*kubernetes.Clientset, *clien
 	check(err)
 	coordinationClient, err := coordinationv1.NewForConfig(config)
 	check(err)
+	dynamicClient, err := dynamic.NewForConfig(config)
+	check(err)
 
-	return client, contourClient, coordinationClient
+	return client, contourClient, coordinationClient, dynamicClient
 }
 
 func check(err error) {
@@ -0,0 +1,147 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	envoy_api_v2_auth "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
+	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/projectcontour/contour/internal/contour"
+	"github.com/projectcontour/contour/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+	"sigs.k8s.io/yaml"
+)
+
+// redacted replaces the private key of each rendered Secret.
+const redacted = "[redacted]"
+
+// registerRender registers the render subcommand and flags
+// with the Application provided.
+func registerRender(app *kingpin.Application) (*kingpin.CmdClause, *renderContext) {
+	var ctx renderContext
+
+	render := app.Command("render", "Print the Envoy configuration generated from Kubernetes manifests without a cluster.")
+	registerManifestFlags(render, &ctx.validateContext)
+	render.Flag("output", "Output format").Short('o').Default("yaml").EnumVar(&ctx.format, "yaml", "json")
+	return render, &ctx
+}
+
+type renderContext struct {
+	validateContext
+	format string
+}
+
+// renderedConfig is the Envoy configuration printed by doRender.
+type renderedConfig struct {
+	Listeners []json.RawMessage `json:"listeners"`
+	Routes    []json.RawMessage `json:"routes"`
+	Clusters  []json.RawMessage `json:"clusters"`
+	Secrets   []json.RawMessage `json:"secrets"`
+}
+
+// doRender prints the Listeners, Routes, Clusters, and Secrets Contour
+// would send to Envoy for the objects in ctx's manifests. The private
+// key of each Secret is redacted.
+func doRender(ctx *renderContext, out io.Writer) error {
+	// problems found while loading objects are reported by contour validate.
+	log := logrus.New()
+	log.SetOutput(ioutil.Discard)
+
+	builder, err := ctx.builder(log)
+	if err != nil {
+		return err
+	}
+
+	ch := contour.CacheHandler{
+		Metrics:     metrics.NewMetrics(prometheus.NewRegistry()),
+		FieldLogger: log,
+	}
+	ch.OnChange(builder.Build())
+
+	var config renderedConfig
+	if config.Listeners, err = marshalAll(ch.ListenerCache.Contents()); err != nil {
+		return err
+	}
+	if config.Routes, err = marshalAll(ch.RouteCache.Contents()); err != nil {
+		return err
+	}
+	if config.Clusters, err = marshalAll(ch.ClusterCache.Contents()); err != nil {
+		return err
+	}
+	if config.Secrets, err = marshalAll(redactSecrets(ch.SecretCache.Contents())); err != nil {
+		return err
+	}
+
+	buf, err := json.Marshal(&config)
+	if err != nil {
+		return err
+	}
+
+	switch ctx.format {
+	case "json":
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, buf, "", "  "); err != nil {
+			return err
+		}
+		indented.WriteByte('\n')
+		buf = indented.Bytes()
+	default:
+		if buf, err = yaml.JSONToYAML(buf); err != nil {
+			return err
+		}
+	}
+	_, err = out.Write(buf)
+	return err
+}
+
+// marshalAll returns the JSON encoding of each message, using
+// the field names from Envoy's proto definitions.
+func marshalAll(messages []proto.Message) ([]json.RawMessage, error) {
+	m := &jsonpb.Marshaler{OrigName: true}
+	result := []json.RawMessage{}
+	for _, msg := range messages {
+		s, err := m.MarshalToString(msg)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling %T: %v", msg, err)
+		}
+		result = append(result, json.RawMessage(s))
+	}
+	return result, nil
+}
+
+// redactSecrets returns copies of secrets with their private keys replaced.
+func redactSecrets(secrets []proto.Message) []proto.Message {
+	var result []proto.Message
+	for _, msg := range secrets {
+		secret := proto.Clone(msg).(*envoy_api_v2_auth.Secret)
+		if cert := secret.GetTlsCertificate(); cert != nil && cert.PrivateKey != nil {
+			cert.PrivateKey = &envoy_api_v2_core.DataSource{
+				Specifier: &envoy_api_v2_core.DataSource_InlineString{
+					InlineString: redacted,
+				},
+			}
+		}
+		result = append(result, secret)
+	}
+	return result
+}
@@ -0,0 +1,145 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/projectcontour/contour/internal/assert"
+	"github.com/projectcontour/contour/internal/certgen"
+)
+
+func TestDoRender(t *testing.T) {
+	cert, key, err := certgen.NewCA("example.com", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manifest := fmt.Sprintf(`apiVersion: v1
+kind: Service
+metadata:
+  name: kuard
+spec:
+  ports:
+  - port: 80
+---
+apiVersion: v1
+kind: Secret
+type: kubernetes.io/tls
+metadata:
+  name: tls
+data:
+  tls.crt: %s
+  tls.key: %s
+---
+apiVersion: projectcontour.io/v1
+kind: HTTPProxy
+metadata:
+  name: root
+spec:
+  virtualhost:
+    fqdn: example.com
+    tls:
+      secretName: tls
+  routes:
+  - services:
+    - name: kuard
+      port: 80
+`, base64.StdEncoding.EncodeToString(cert), base64.StdEncoding.EncodeToString(key))
+
+	dir, err := ioutil.TempDir("", "render")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "manifest.yaml"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	render := func(format string) string {
+		var out bytes.Buffer
+		err := doRender(&renderContext{
+			validateContext: validateContext{
+				paths:     []string{dir},
+				namespace: "default",
+			},
+			format: format,
+		}, &out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out.String()
+	}
+
+	var got struct {
+		Listeners []struct {
+			Name string `json:"name"`
+		} `json:"listeners"`
+		Routes []struct {
+			Name string `json:"name"`
+		} `json:"routes"`
+		Clusters []struct {
+			Name string `json:"name"`
+		} `json:"clusters"`
+		Secrets []struct {
+			Name           string `json:"name"`
+			TLSCertificate struct {
+				PrivateKey map[string]string `json:"private_key"`
+			} `json:"tls_certificate"`
+		} `json:"secrets"`
+	}
+	out := render("json")
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("%v: %s", err, out)
+	}
+
+	var listeners, routes, clusters []string
+	for _, l := range got.Listeners {
+		listeners = append(listeners, l.Name)
+	}
+	for _, r := range got.Routes {
+		routes = append(routes, r.Name)
+	}
+	for _, c := range got.Clusters {
+		clusters = append(clusters, c.Name)
+	}
+	assert.Equal(t, []string{"ingress_http", "ingress_https"}, listeners)
+	assert.Equal(t, []string{"ingress_http", "ingress_https"}, routes)
+	assert.Equal(t, []string{"default/kuard/80/da39a3ee5e"}, clusters)
+	if len(got.Secrets) != 1 {
+		t.Fatalf("expected 1 secret, got %d", len(got.Secrets))
+	}
+	if pk := got.Secrets[0].TLSCertificate.PrivateKey; pk["inline_string"] != redacted {
+		t.Errorf("expected private key to be redacted, got %v", pk)
+	}
+
+	for _, format := range []string{"json", "yaml"} {
+		out := render(format)
+		if strings.Contains(out, base64.StdEncoding.EncodeToString(key)) {
+			t.Errorf("%s output contains the private key", format)
+		}
+	}
+	if out := render("yaml"); !strings.Contains(out, "name: default/kuard/80/da39a3ee5e") {
+		t.Errorf("expected yaml output to contain cluster, got:\n%s", out)
+	}
+}
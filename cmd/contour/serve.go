@@ -19,13 +19,13 @@ import (
 	"net"
 	"os"
 	"os/signal"
-	"reflect"
 	"strconv"
 	"syscall"
 	"time"
 
 	"k8s.io/client-go/tools/cache"
 
+	clientset "github.com/projectcontour/contour/apis/generated/clientset/versioned"
 	contourinformers "github.com/projectcontour/contour/apis/generated/informers/externalversions"
 	"github.com/projectcontour/contour/internal/contour"
 	"github.com/projectcontour/contour/internal/dag"
@@ -34,12 +34,16 @@ import (
 	"github.com/projectcontour/contour/internal/httpsvc"
 	"github.com/projectcontour/contour/internal/k8s"
 	"github.com/projectcontour/contour/internal/metrics"
+	"github.com/projectcontour/contour/internal/webhook"
 	"github.com/projectcontour/contour/internal/workgroup"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 	"gopkg.in/alecthomas/kingpin.v2"
 	"gopkg.in/yaml.v2"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	coreinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/leaderelection"
 )
 
@@ -94,15 +98,25 @@ func registerServe(app *kingpin.Application) (*kingpin.CmdClause, *serveContext)
 	serve.Flag("http-address", "address the metrics http endpoint will bind to").StringVar(&ctx.metricsAddr)
 	serve.Flag("http-port", "port the metrics http endpoint will bind to").IntVar(&ctx.metricsPort)
 
+	serve.Flag("webhook-address", "address the validating admission webhook will bind to").StringVar(&ctx.webhookAddr)
+	serve.Flag("webhook-port", "port the validating admission webhook will bind to, zero disables the webhook").IntVar(&ctx.webhookPort)
+	serve.Flag("webhook-cert-file", "certificate file name for serving the validating admission webhook").StringVar(&ctx.webhookCert)
+	serve.Flag("webhook-key-file", "key file name for serving the validating admission webhook").StringVar(&ctx.webhookKey)
+
 	serve.Flag("contour-cafile", "CA bundle file name for serving gRPC with TLS").Envar("CONTOUR_CAFILE").StringVar(&ctx.caFile)
 	serve.Flag("contour-cert-file", "Contour certificate file name for serving gRPC over TLS").Envar("CONTOUR_CERT_FILE").StringVar(&ctx.contourCert)
 	serve.Flag("contour-key-file", "Contour key file name for serving gRPC over TLS").Envar("CONTOUR_KEY_FILE").StringVar(&ctx.contourKey)
 	serve.Flag("insecure", "Allow serving without TLS secured gRPC").BoolVar(&ctx.PermitInsecureGRPC)
+	serve.Flag("xds-allowed-identity", "Client certificate identity, a URI or DNS SAN, permitted to use the xDS API. May be repeated").StringsVar(&ctx.XDSAllowedIdentities)
+	serve.Flag("xds-log-unauthorized", "Log, rather than reject, xDS clients without an allowed identity").BoolVar(&ctx.XDSLogUnauthorized)
+	serve.Flag("grpc-max-connection-age", "Maximum age of an xDS connection before Envoy is asked to reconnect").DurationVar(&ctx.GRPCMaxConnectionAge)
+	serve.Flag("grpc-drain-timeout", "Period over which xDS streams are drained on shutdown").DurationVar(&ctx.GRPCDrainTimeout)
 	// TODO(sas) Deprecate `ingressroute-root-namespaces` in v1.0
 	serve.Flag("ingressroute-root-namespaces", "DEPRECATED (Use 'root-namespaces'): Restrict contour to searching these namespaces for root ingress routes").StringVar(&ctx.rootNamespaces)
 	serve.Flag("root-namespaces", "Restrict contour to searching these namespaces for root ingress routes").StringVar(&ctx.rootNamespaces)
+	serve.Flag("watch-namespaces", "Restrict contour to watching resources in these namespaces").StringVar(&ctx.watchNamespaces)
 
-	serve.Flag("ingress-class-name", "Contour IngressClass name").StringVar(&ctx.ingressClass)
+	serve.Flag("ingress-class-name", "Contour IngressClass name. May be repeated").StringsVar(&ctx.ingressClasses)
 
 	serve.Flag("envoy-http-access-log", "Envoy HTTP access log").StringVar(&ctx.httpAccessLog)
 	serve.Flag("envoy-https-access-log", "Envoy HTTPS access log").StringVar(&ctx.httpsAccessLog)
@@ -110,33 +124,54 @@ func registerServe(app *kingpin.Application) (*kingpin.CmdClause, *serveContext)
 	serve.Flag("envoy-service-https-address", "Kubernetes Service address for HTTPS requests").StringVar(&ctx.httpsAddr)
 	serve.Flag("envoy-service-http-port", "Kubernetes Service port for HTTP requests").IntVar(&ctx.httpPort)
 	serve.Flag("envoy-service-https-port", "Kubernetes Service port for HTTPS requests").IntVar(&ctx.httpsPort)
+	serve.Flag("envoy-service-name", "Name of the Envoy Service whose load balancer address is reported on Ingress status").StringVar(&ctx.EnvoyServiceName)
+	serve.Flag("envoy-service-namespace", "Namespace of the Envoy Service whose load balancer address is reported on Ingress status").StringVar(&ctx.EnvoyServiceNamespace)
+	serve.Flag("gateway-controller-name", "Controller name of the Gateway API GatewayClasses to implement").StringVar(&ctx.GatewayControllerName)
+	serve.Flag("connect-timeout", "Default timeout for new connections to upstream services").DurationVar(&ctx.ConnectTimeout)
+	serve.Flag("idle-connection-timeout", "Default period after which idle upstream connections are closed").DurationVar(&ctx.IdleConnectionTimeout)
+	serve.Flag("max-connection-duration", "Default maximum lifetime of upstream connections").DurationVar(&ctx.MaxConnectionDuration)
 	serve.Flag("use-proxy-protocol", "Use PROXY protocol for all listeners").BoolVar(&ctx.useProxyProto)
 
 	serve.Flag("accesslog-format", "Format for Envoy access logs").StringVar(&ctx.AccessLogFormat)
 	serve.Flag("disable-leader-election", "Disable leader election mechanism").BoolVar(&ctx.DisableLeaderElection)
 
+	serve.Flag("use-endpoint-slices", "Translate EndpointSlices, rather than Endpoints, into EDS").BoolVar(&ctx.UseEndpointSlices)
 	serve.Flag("use-extensions-v1beta1-ingress", "Subscribe to the deprecated extensions/v1beta1.Ingress type").BoolVar(&ctx.UseExtensionsV1beta1Ingress)
 	return serve, ctx
 }
 
 // doServe runs the contour serve subcommand.
 func doServe(log logrus.FieldLogger, ctx *serveContext) error {
+	if err := ctx.verifyWatchNamespaces(); err != nil {
+		return err
+	}
+	if err := ctx.verifyIngressClassListeners(); err != nil {
+		return err
+	}
 
 	// step 1. establish k8s client connection
-	client, contourClient, coordinationClient := newClient(ctx.Kubeconfig, ctx.InCluster)
+	client, contourClient, coordinationClient, dynamicClient := newClient(ctx.Kubeconfig, ctx.InCluster)
 
 	// step 2. create informers
 	// note: 0 means resync timers are disabled
-	coreInformers := coreinformers.NewSharedInformerFactory(client, 0)
-	contourInformers := contourinformers.NewSharedInformerFactory(contourClient, 0)
+	// Create a set of SharedInformerFactories for each watched namespace (if defined)
+	// otherwise a single set for the whole cluster.
+	factories := newInformerFactories(client, contourClient, dynamicClient, ctx.watchedNamespaces())
+
+	// GatewayClasses and Nodes are cluster scoped, so they are always watched cluster-wide.
+	clusterInformers := contourinformers.NewSharedInformerFactory(contourClient, 0)
+	clusterCoreInformers := coreinformers.NewSharedInformerFactory(client, 0)
 
 	// Create a set of SharedInformerFactories for each root-ingressroute namespace (if defined)
-	var namespacedInformers []coreinformers.SharedInformerFactory
-	for _, namespace := range ctx.ingressRouteRootNamespaces() {
-		inf := coreinformers.NewSharedInformerFactoryWithOptions(client, 0, coreinformers.WithNamespace(namespace))
-		namespacedInformers = append(namespacedInformers, inf)
+	secretFactories := factories
+	if roots := ctx.ingressRouteRootNamespaces(); len(roots) > 0 {
+		secretFactories = newInformerFactories(client, contourClient, dynamicClient, roots)
 	}
 
+	// versions is shared by the CacheHandler and the EndpointsTranslator
+	// so every xDS resource type is published on one version sequence.
+	versions := new(contour.Versions)
+
 	// step 3. build our mammoth Kubernetes event handler.
 	eh := &contour.EventHandler{
 		CacheHandler: &contour.CacheHandler{
@@ -152,8 +187,10 @@ func doServe(log logrus.FieldLogger, ctx *serveContext) error {
 				AccessLogFields:        ctx.AccessLogFields,
 				MinimumProtocolVersion: dag.MinProtoVersion(ctx.TLSConfig.MinimumProtocolVersion),
 				RequestTimeout:         ctx.RequestTimeout,
+				IngressClassListeners:  ctx.ingressClassListeners(),
 			},
 			ListenerCache: contour.NewListenerCache(ctx.statsAddr, ctx.statsPort),
+			Versions:      versions,
 			FieldLogger:   log.WithField("context", "CacheHandler"),
 		},
 		HoldoffDelay:    100 * time.Millisecond,
@@ -161,62 +198,136 @@ func doServe(log logrus.FieldLogger, ctx *serveContext) error {
 		StatusClient: &k8s.StatusWriter{
 			Client: contourClient,
 		},
+		EventRecorder: k8s.NewEventRecorder(client, log.WithField("context", "events")),
 		Builder: dag.Builder{
 			Source: dag.KubernetesCache{
-				RootNamespaces: ctx.ingressRouteRootNamespaces(),
-				IngressClass:   ctx.ingressClass,
-				FieldLogger:    log.WithField("context", "KubernetesCache"),
+				RootNamespaces:    ctx.ingressRouteRootNamespaces(),
+				IngressClasses:    ctx.servedIngressClasses(),
+				GatewayController: ctx.GatewayControllerName,
+				FieldLogger:       log.WithField("context", "KubernetesCache"),
 			},
 			DisablePermitInsecure: ctx.DisablePermitInsecure,
+			ListenerClasses:       ctx.listenerClasses(),
+			ConnectionTimeouts: dag.ConnectionTimeoutPolicy{
+				ConnectTimeout:        ctx.ConnectTimeout,
+				IdleConnectionTimeout: ctx.IdleConnectionTimeout,
+				MaxConnectionDuration: ctx.MaxConnectionDuration,
+			},
 		},
 		FieldLogger: log.WithField("context", "contourEventHandler"),
 	}
 
 	// step 4. register our resource event handler with the k8s informers.
 	var informers []cache.SharedIndexInformer
-	informers = registerEventHandler(informers, coreInformers.Core().V1().Services().Informer(), eh)
-	informers = registerEventHandler(informers, contourInformers.Contour().V1beta1().IngressRoutes().Informer(), eh)
-	informers = registerEventHandler(informers, contourInformers.Contour().V1beta1().TLSCertificateDelegations().Informer(), eh)
-	informers = registerEventHandler(informers, contourInformers.Projectcontour().V1().HTTPProxies().Informer(), eh)
-	informers = registerEventHandler(informers, contourInformers.Projectcontour().V1().TLSCertificateDelegations().Informer(), eh)
-
-	// After K8s 1.13 the API server will automatically translate extensions/v1beta1.Ingress objects
-	// to networking/v1beta1.Ingress objects so we should only listen for one type or the other.
-	// The default behavior is to listen for networking/v1beta1.Ingress objects and let the API server
-	// transparently upgrade the extensions version for us.
-	if ctx.UseExtensionsV1beta1Ingress {
-		informers = registerEventHandler(informers, coreInformers.Extensions().V1beta1().Ingresses().Informer(), eh)
-	} else {
-		informers = registerEventHandler(informers, coreInformers.Networking().V1beta1().Ingresses().Informer(), eh)
+	for _, f := range factories {
+		informers = registerEventHandler(informers, f.core.Core().V1().Services().Informer(), eh)
+		informers = registerEventHandler(informers, f.contour.Contour().V1beta1().IngressRoutes().Informer(), eh)
+		informers = registerEventHandler(informers, f.contour.Contour().V1beta1().TLSCertificateDelegations().Informer(), eh)
+		informers = registerEventHandler(informers, f.contour.Projectcontour().V1().HTTPProxies().Informer(), eh)
+		informers = registerEventHandler(informers, f.contour.Projectcontour().V1().TLSCertificateDelegations().Informer(), eh)
+		informers = registerEventHandler(informers, f.contour.Projectcontour().V1().ServiceDelegations().Informer(), eh)
+		informers = registerEventHandler(informers, f.contour.Projectcontour().V1().Upstreams().Informer(), eh)
+		informers = registerEventHandler(informers, f.ingresses(ctx.UseExtensionsV1beta1Ingress), eh)
 	}
 
-	// Add informers for each root-ingressroute namespaces
-	for _, inf := range namespacedInformers {
-		informers = registerEventHandler(informers, inf.Core().V1().Secrets().Informer(), eh)
+	// Gateway API objects are only watched if Contour implements
+	// a GatewayClass, as their CRDs may not be installed.
+	if ctx.GatewayControllerName != "" {
+		informers = registerEventHandler(informers, clusterInformers.Networking().V1alpha1().GatewayClasses().Informer(), eh)
+		for _, f := range factories {
+			informers = registerEventHandler(informers, f.contour.Networking().V1alpha1().Gateways().Informer(), eh)
+			informers = registerEventHandler(informers, f.contour.Networking().V1alpha1().HTTPRoutes().Informer(), eh)
+		}
+	}
+
+	// the IngressStatusUpdater copies the Envoy Service's load balancer
+	// status into the Ingresses that Contour owns.
+	isu := &contour.IngressStatusUpdater{
+		Client:                client,
+		EnvoyServiceName:      ctx.EnvoyServiceName,
+		EnvoyServiceNamespace: ctx.EnvoyServiceNamespace,
+		IngressClasses:        ctx.servedIngressClasses(),
+		FieldLogger:           log.WithField("context", "ingressstatusupdater"),
 	}
-	// If root-ingressroutes are not defined, then add the informer for all namespaces
-	if len(namespacedInformers) == 0 {
-		informers = registerEventHandler(informers, coreInformers.Core().V1().Secrets().Informer(), eh)
+	for _, f := range factories {
+		f.core.Core().V1().Services().Informer().AddEventHandler(isu)
+		f.ingresses(ctx.UseExtensionsV1beta1Ingress).AddEventHandler(isu)
 	}
 
-	// step 5. endpoints updates are handled directly by the EndpointsTranslator
-	// due to their high update rate and their orthogonal nature.
-	et := &contour.EndpointsTranslator{
-		FieldLogger: log.WithField("context", "endpointstranslator"),
+	// Add informers for each root-ingressroute namespaces, or if
+	// root-ingressroutes are not defined, each watched namespace.
+	for _, f := range secretFactories {
+		informers = registerEventHandler(informers, f.core.Core().V1().Secrets().Informer(), eh)
 	}
 
-	informers = registerEventHandler(informers, coreInformers.Core().V1().Endpoints().Informer(), et)
+	// the validating admission webhook, if enabled, sees
+	// the same objects as the event handler.
+	var validator *webhook.Validator
+	if ctx.webhookPort != 0 {
+		validator = &webhook.Validator{
+			RootNamespaces:        ctx.ingressRouteRootNamespaces(),
+			IngressClasses:        ctx.servedIngressClasses(),
+			ListenerClasses:       ctx.listenerClasses(),
+			DisablePermitInsecure: ctx.DisablePermitInsecure,
+			FieldLogger:           log.WithField("context", "webhook"),
+		}
+		for _, inf := range informers {
+			inf.AddEventHandler(validator)
+		}
+	}
+
+	// step 5. endpoints updates are handled directly by the EndpointsTranslator,
+	// or the EndpointSliceTranslator, due to their high update rate and their
+	// orthogonal nature.
+	var et interface {
+		cgrpc.Resource
+		cache.ResourceEventHandler
+	}
+	switch {
+	case ctx.UseEndpointSlices:
+		et = &contour.EndpointSliceTranslator{
+			FieldLogger: log.WithField("context", "endpointslicetranslator"),
+			Versions:    versions,
+		}
+		for _, f := range factories {
+			informers = registerEventHandler(informers, f.dynamic.ForResource(k8s.EndpointSlicesResource).Informer(), et)
+		}
+	default:
+		et = &contour.EndpointsTranslator{
+			FieldLogger: log.WithField("context", "endpointstranslator"),
+			Versions:    versions,
+		}
+		for _, f := range factories {
+			informers = registerEventHandler(informers, f.core.Core().V1().Endpoints().Informer(), et)
+		}
+	}
+	for _, f := range factories {
+		// the slow start window of a Service is set by its annotation.
+		f.core.Core().V1().Services().Informer().AddEventHandler(et)
+	}
+	// the zone and region labels of Nodes give the locality of their endpoints.
+	// Nodes are cluster scoped and may not be watchable, for example with
+	// --watch-namespaces, so serving xDS does not wait for them to sync;
+	// localities are added to the endpoints as Nodes are seen.
+	clusterCoreInformers.Core().V1().Nodes().Informer().AddEventHandler(et)
 
 	// step 6. setup workgroup runner and register informers.
 	var g workgroup.Group
-	g.Add(startInformer(coreInformers, log.WithField("context", "coreinformers")))
-	g.Add(startInformer(contourInformers, log.WithField("context", "contourinformers")))
-	for _, inf := range namespacedInformers {
-		g.Add(startInformer(inf, log.WithField("context", "corenamespacedinformers")))
+	for _, f := range factories {
+		f.start(&g, log)
+	}
+	// secretFactories are only distinct if root-ingressroutes are defined.
+	if len(ctx.ingressRouteRootNamespaces()) > 0 {
+		for _, f := range secretFactories {
+			f.start(&g, log)
+		}
 	}
+	g.Add(startInformer(clusterInformers, log.WithField("context", "clusterinformers")))
+	g.Add(startInformer(clusterCoreInformers, log.WithField("context", "clustercoreinformers")))
 
-	// step 7. register our event handler with the workgroup
+	// step 7. register our event handlers with the workgroup
 	g.Add(eh.Start())
+	g.Add(isu.Start())
 
 	// step 8. setup prometheus registry and register base metrics.
 	registry := prometheus.NewRegistry()
@@ -235,22 +346,62 @@ func doServe(log logrus.FieldLogger, ctx *serveContext) error {
 	}
 	g.Add(metricsvc.Start)
 
-	// step 10. create debug service and register with workgroup.
+	// step 10. create debug service and register with workgroup. The
+	// xDS resources are served once the informer caches have synced.
+	resources := map[string]cgrpc.Resource{
+		eh.CacheHandler.ClusterCache.TypeURL():  &eh.CacheHandler.ClusterCache,
+		eh.CacheHandler.RouteCache.TypeURL():    &eh.CacheHandler.RouteCache,
+		eh.CacheHandler.ListenerCache.TypeURL(): &eh.CacheHandler.ListenerCache,
+		eh.CacheHandler.SecretCache.TypeURL():   &eh.CacheHandler.SecretCache,
+		et.TypeURL():                            et,
+	}
 	debugsvc := debug.Service{
 		Service: httpsvc.Service{
 			Addr:        ctx.debugAddr,
 			Port:        ctx.debugPort,
 			FieldLogger: log.WithField("context", "debugsvc"),
 		},
-		Builder: &eh.Builder,
+		Builder:   &eh.Builder,
+		Resources: resources,
+	}
+	for _, inf := range informers {
+		debugsvc.Synced = append(debugsvc.Synced, inf.HasSynced)
 	}
 	g.Add(debugsvc.Start)
 
+	// step 10a. if enabled, create the validating admission webhook
+	// service and register with workgroup once the informer caches
+	// have synced, so objects are validated against the whole cluster.
+	if validator != nil {
+		if ctx.webhookCert == "" || ctx.webhookKey == "" {
+			log.Fatal("the validating admission webhook requires --webhook-cert-file and --webhook-key-file")
+		}
+		webhooksvc := httpsvc.Service{
+			Addr:        ctx.webhookAddr,
+			Port:        ctx.webhookPort,
+			CertFile:    ctx.webhookCert,
+			KeyFile:     ctx.webhookKey,
+			FieldLogger: log.WithField("context", "webhook"),
+		}
+		webhooksvc.Handle("/validate", validator)
+		g.Add(func(stop <-chan struct{}) error {
+			synced := make([]cache.InformerSynced, 0, len(informers))
+			for _, inf := range informers {
+				synced = append(synced, inf.HasSynced)
+			}
+			if !cache.WaitForCacheSync(stop, synced...) {
+				return fmt.Errorf("error waiting for cache to sync")
+			}
+			return webhooksvc.Start(stop)
+		})
+	}
+
 	// step 11. if enabled, register leader election
 	if !ctx.DisableLeaderElection {
 		var le *leaderelection.LeaderElector
 		var deposed chan struct{}
 		le, eh.IsLeader, deposed = newLeaderElector(log, ctx, client, coordinationClient)
+		isu.IsLeader = eh.IsLeader
 
 		g.AddContext(func(electionCtx context.Context) {
 			log.WithFields(logrus.Fields{
@@ -300,6 +451,7 @@ func doServe(log logrus.FieldLogger, ctx *serveContext) error {
 		leader := make(chan struct{})
 		close(leader)
 		eh.IsLeader = leader
+		isu.IsLeader = leader
 	}
 
 	// step 12. register our custom metrics and plumb into cache handler
@@ -323,15 +475,8 @@ func doServe(log logrus.FieldLogger, ctx *serveContext) error {
 		}
 		log.Printf("informer caches synced")
 
-		resources := map[string]cgrpc.Resource{
-			eh.CacheHandler.ClusterCache.TypeURL():  &eh.CacheHandler.ClusterCache,
-			eh.CacheHandler.RouteCache.TypeURL():    &eh.CacheHandler.RouteCache,
-			eh.CacheHandler.ListenerCache.TypeURL(): &eh.CacheHandler.ListenerCache,
-			eh.CacheHandler.SecretCache.TypeURL():   &eh.CacheHandler.SecretCache,
-			et.TypeURL():                            et,
-		}
 		opts := ctx.grpcOptions()
-		s := cgrpc.NewAPI(log, resources, registry, opts...)
+		s := cgrpc.NewAPI(log, resources, registry, metrics, ctx.authorizer(), opts...)
 		addr := net.JoinHostPort(ctx.xdsAddr, strconv.Itoa(ctx.xdsPort))
 		l, err := net.Listen("tcp", addr)
 		if err != nil {
@@ -346,12 +491,22 @@ func doServe(log logrus.FieldLogger, ctx *serveContext) error {
 		log.Info("started")
 		defer log.Info("stopped")
 
+		drained := make(chan struct{})
 		go func() {
 			<-stop
-			s.Stop()
+			log.WithField("drain_timeout", ctx.GRPCDrainTimeout).Info("draining")
+			s.Shutdown(ctx.GRPCDrainTimeout)
+			close(drained)
 		}()
 
-		return s.Serve(l)
+		if err := s.Serve(l); err != nil {
+			return err
+		}
+
+		// Serve returns as soon as shutdown begins, wait
+		// for open streams to drain before returning.
+		<-drained
+		return nil
 	})
 
 	// step 14. Setup SIGTERM handler
@@ -371,13 +526,66 @@ func doServe(log logrus.FieldLogger, ctx *serveContext) error {
 	return g.Run()
 }
 
+// informerFactories are the SharedInformerFactories for a namespace,
+// or for the whole cluster if namespace is empty.
+type informerFactories struct {
+	namespace string
+	core      coreinformers.SharedInformerFactory
+	contour   contourinformers.SharedInformerFactory
+	dynamic   dynamicinformer.DynamicSharedInformerFactory
+}
+
+// newInformerFactories returns a set of informerFactories for each
+// namespace in namespaces, or a single cluster-wide set if namespaces
+// is empty.
+func newInformerFactories(client kubernetes.Interface, contourClient clientset.Interface, dynamicClient dynamic.Interface, namespaces []string) []informerFactories {
+	if len(namespaces) == 0 {
+		return []informerFactories{{
+			core:    coreinformers.NewSharedInformerFactory(client, 0),
+			contour: contourinformers.NewSharedInformerFactory(contourClient, 0),
+			dynamic: dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 0),
+		}}
+	}
+	var factories []informerFactories
+	for _, namespace := range namespaces {
+		factories = append(factories, informerFactories{
+			namespace: namespace,
+			core:      coreinformers.NewSharedInformerFactoryWithOptions(client, 0, coreinformers.WithNamespace(namespace)),
+			contour:   contourinformers.NewSharedInformerFactoryWithOptions(contourClient, 0, contourinformers.WithNamespace(namespace)),
+			dynamic:   dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynamicClient, 0, namespace, nil),
+		})
+	}
+	return factories
+}
+
+// ingresses returns the Ingress informer.
+// After K8s 1.13 the API server will automatically translate extensions/v1beta1.Ingress objects
+// to networking/v1beta1.Ingress objects so we should only listen for one type or the other.
+// The default behavior is to listen for networking/v1beta1.Ingress objects and let the API server
+// transparently upgrade the extensions version for us.
+func (f informerFactories) ingresses(useExtensionsV1beta1 bool) cache.SharedIndexInformer {
+	if useExtensionsV1beta1 {
+		return f.core.Extensions().V1beta1().Ingresses().Informer()
+	}
+	return f.core.Networking().V1beta1().Ingresses().Informer()
+}
+
+// start registers the informer factories with the workgroup.
+func (f informerFactories) start(g *workgroup.Group, log logrus.FieldLogger) {
+	if f.namespace != "" {
+		log = log.WithField("namespace", f.namespace)
+	}
+	g.Add(startInformer(f.core, log.WithField("context", "coreinformers")))
+	g.Add(startInformer(f.contour, log.WithField("context", "contourinformers")))
+	g.Add(startInformer(f.dynamic, log.WithField("context", "dynamicinformers")))
+}
+
 func registerEventHandler(informers []cache.SharedIndexInformer, inf cache.SharedIndexInformer, eh cache.ResourceEventHandler) []cache.SharedIndexInformer {
 	inf.AddEventHandler(eh)
 	return append(informers, inf)
 }
 
 type informer interface {
-	WaitForCacheSync(stopCh <-chan struct{}) map[reflect.Type]bool
 	Start(stopCh <-chan struct{})
 }
 
@@ -18,14 +18,19 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"log"
+	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/projectcontour/contour/internal/contour"
+	"github.com/projectcontour/contour/internal/dag"
+	cgrpc "github.com/projectcontour/contour/internal/grpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/keepalive"
@@ -49,11 +54,21 @@ type serveContext struct {
 	metricsAddr string
 	metricsPort int
 
+	// contour's validating admission webhook parameters,
+	// the webhook is disabled if webhookPort is zero.
+	webhookAddr             string
+	webhookPort             int
+	webhookCert, webhookKey string
+
 	// ingressroute root namespaces
 	rootNamespaces string
 
-	// ingress class
-	ingressClass string
+	// namespaces contour watches, if empty
+	// contour watches the whole cluster.
+	watchNamespaces string
+
+	// ingress classes
+	ingressClasses []string
 
 	// envoy's stats listener parameters
 	statsAddr string
@@ -85,6 +100,25 @@ type serveContext struct {
 	// PermitInsecureGRPC disables TLS on Contour's gRPC listener.
 	PermitInsecureGRPC bool `yaml:"-"`
 
+	// XDSAllowedIdentities, if not empty, restricts the xDS API to
+	// clients whose certificate carries one of these URI or DNS
+	// subject alternative names.
+	XDSAllowedIdentities []string `yaml:"xds-allowed-identities,omitempty"`
+
+	// XDSLogUnauthorized logs, rather than rejects, xDS clients
+	// without an allowed identity.
+	XDSLogUnauthorized bool `yaml:"xds-log-unauthorized,omitempty"`
+
+	// GRPCMaxConnectionAge, if non zero, is the maximum age of an xDS
+	// connection. Once reached, Contour sends a GOAWAY asking Envoy to
+	// reconnect, which rebalances Envoys across Contour replicas.
+	GRPCMaxConnectionAge time.Duration `yaml:"grpc-max-connection-age,omitempty"`
+
+	// GRPCDrainTimeout is the period over which open xDS streams are
+	// terminated when Contour shuts down. It is also the time a connection
+	// past GRPCMaxConnectionAge is given to close before it is closed forcibly.
+	GRPCDrainTimeout time.Duration `yaml:"grpc-drain-timeout,omitempty"`
+
 	TLSConfig `yaml:"tls,omitempty"`
 
 	// DisablePermitInsecure disables the use of the
@@ -100,6 +134,35 @@ type serveContext struct {
 	// RequestTimeout sets the client request timeout globally for Contour.
 	RequestTimeout time.Duration `yaml:"request-timeout,omitempty"`
 
+	// ConnectTimeout, IdleConnectionTimeout and MaxConnectionDuration
+	// are the upstream connection timeouts of services which do not
+	// set their own in a connectionTimeoutPolicy.
+	ConnectTimeout        time.Duration `yaml:"connect-timeout,omitempty"`
+	IdleConnectionTimeout time.Duration `yaml:"idle-connection-timeout,omitempty"`
+	MaxConnectionDuration time.Duration `yaml:"max-connection-duration,omitempty"`
+
+	// EnvoyServiceName and EnvoyServiceNamespace identify the Service
+	// in front of Envoy. Its load balancer status is copied into the
+	// status of each Ingress owned by Contour.
+	EnvoyServiceName      string `yaml:"envoy-service-name,omitempty"`
+	EnvoyServiceNamespace string `yaml:"envoy-service-namespace,omitempty"`
+
+	// IngressClassListeners binds the virtual hosts of each ingress
+	// class listed to their own Envoy listeners, rather than the
+	// listeners shared by every other ingress class. Contour serves
+	// these classes as well as those given by --ingress-class-name.
+	IngressClassListeners []IngressClassListener `yaml:"ingress-class-listeners,omitempty"`
+
+	// GatewayControllerName is the controller name of the Gateway API
+	// GatewayClasses Contour implements. If empty, Contour does not
+	// watch Gateway API objects.
+	GatewayControllerName string `yaml:"gateway-controller-name,omitempty"`
+
+	// UseEndpointSlices translates discovery.k8s.io/v1 EndpointSlices,
+	// rather than Endpoints, into EDS. EndpointSlices are not truncated
+	// at 1000 addresses and are cheaper to update for large Services.
+	UseEndpointSlices bool `yaml:"use-endpoint-slices,omitempty"`
+
 	// Should Contour fall back to registering an informer for the deprecated
 	// extensions/v1beta1.Ingress type.
 	// By default this value is false, meaning Contour will register an informer for
@@ -123,6 +186,7 @@ func newServeContext() *serveContext {
 		debugPort:             6060,
 		metricsAddr:           "0.0.0.0",
 		metricsPort:           8000,
+		webhookAddr:           "0.0.0.0",
 		httpAccessLog:         contour.DEFAULT_HTTP_ACCESS_LOG,
 		httpsAccessLog:        contour.DEFAULT_HTTPS_ACCESS_LOG,
 		httpAddr:              "0.0.0.0",
@@ -130,6 +194,7 @@ func newServeContext() *serveContext {
 		httpPort:              8080,
 		httpsPort:             8443,
 		PermitInsecureGRPC:    false,
+		GRPCDrainTimeout:      10 * time.Second,
 		DisablePermitInsecure: false,
 		DisableLeaderElection: false,
 		AccessLogFormat:       "envoy",
@@ -163,6 +228,8 @@ func newServeContext() *serveContext {
 			Namespace:     "projectcontour",
 			Name:          "leader-elect",
 		},
+		EnvoyServiceName:            "envoy",
+		EnvoyServiceNamespace:       "projectcontour",
 		UseExtensionsV1beta1Ingress: false,
 	}
 }
@@ -172,6 +239,17 @@ type TLSConfig struct {
 	MinimumProtocolVersion string `yaml:"minimum-protocol-version"`
 }
 
+// IngressClassListener holds the configuration file details of the
+// Envoy listeners serving an ingress class. If an address is not set
+// the address of the default listener is used.
+type IngressClassListener struct {
+	Class        string `yaml:"class"`
+	HTTPAddress  string `yaml:"http-address,omitempty"`
+	HTTPPort     int    `yaml:"http-port"`
+	HTTPSAddress string `yaml:"https-address,omitempty"`
+	HTTPSPort    int    `yaml:"https-port"`
+}
+
 // LeaderElectionConfig holds the config bits for leader election inside the
 // configuration file.
 type LeaderElectionConfig struct {
@@ -202,8 +280,10 @@ func (ctx *serveContext) grpcOptions() []grpc.ServerOption {
 			PermitWithoutStream: true,
 		}),
 		grpc.KeepaliveParams(keepalive.ServerParameters{
-			Time:    60 * time.Second,
-			Timeout: 20 * time.Second,
+			Time:                  60 * time.Second,
+			Timeout:               20 * time.Second,
+			MaxConnectionAge:      ctx.GRPCMaxConnectionAge,
+			MaxConnectionAgeGrace: ctx.GRPCDrainTimeout,
 		}),
 	}
 	if !ctx.PermitInsecureGRPC {
@@ -214,6 +294,18 @@ func (ctx *serveContext) grpcOptions() []grpc.ServerOption {
 	return opts
 }
 
+// authorizer returns the *cgrpc.Authorizer for the xDS API, or nil
+// if no client identities are configured.
+func (ctx *serveContext) authorizer() *cgrpc.Authorizer {
+	if len(ctx.XDSAllowedIdentities) == 0 {
+		return nil
+	}
+	return &cgrpc.Authorizer{
+		Identities: ctx.XDSAllowedIdentities,
+		LogOnly:    ctx.XDSLogUnauthorized,
+	}
+}
+
 // tlsconfig returns a new *tls.Config. If the context is not properly configured
 // for tls communication, tlsconfig returns nil.
 func (ctx *serveContext) tlsconfig() *tls.Config {
@@ -255,12 +347,155 @@ func (ctx *serveContext) verifyTLSFlags() error {
 // ingressRouteRootNamespaces returns a slice of namespaces restricting where
 // contour should look for ingressroute roots.
 func (ctx *serveContext) ingressRouteRootNamespaces() []string {
-	if strings.TrimSpace(ctx.rootNamespaces) == "" {
+	return parseRootNamespaces(ctx.rootNamespaces)
+}
+
+// watchedNamespaces returns a slice of the namespaces contour watches,
+// or nil if contour watches every namespace.
+func (ctx *serveContext) watchedNamespaces() []string {
+	return parseRootNamespaces(ctx.watchNamespaces)
+}
+
+// verifyWatchNamespaces indicates if the root namespaces are watched.
+func (ctx *serveContext) verifyWatchNamespaces() error {
+	watched := ctx.watchedNamespaces()
+	if len(watched) == 0 {
+		return nil
+	}
+	for _, root := range ctx.ingressRouteRootNamespaces() {
+		if !contains(watched, root) {
+			return fmt.Errorf("root namespace %q is not in --watch-namespaces", root)
+		}
+	}
+	return nil
+}
+
+func contains(namespaces []string, namespace string) bool {
+	for _, ns := range namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// servedIngressClasses returns the ingress classes contour serves,
+// those passed to --ingress-class-name, or dag.DEFAULT_INGRESS_CLASS
+// if none were, and the class of each IngressClassListener.
+func (ctx *serveContext) servedIngressClasses() []string {
+	classes := append([]string(nil), ctx.ingressClasses...)
+	if len(classes) == 0 && len(ctx.IngressClassListeners) > 0 {
+		classes = []string{dag.DEFAULT_INGRESS_CLASS}
+	}
+	for _, l := range ctx.IngressClassListeners {
+		if !contains(classes, l.Class) {
+			classes = append(classes, l.Class)
+		}
+	}
+	return classes
+}
+
+// listenerClasses returns the ingress classes bound to their own listeners.
+func (ctx *serveContext) listenerClasses() []string {
+	var classes []string
+	for _, l := range ctx.IngressClassListeners {
+		classes = append(classes, l.Class)
+	}
+	return classes
+}
+
+// ingressClassListeners returns the addresses of the listeners
+// serving each ingress class bound to its own listeners.
+func (ctx *serveContext) ingressClassListeners() map[string]contour.ListenerAddresses {
+	if len(ctx.IngressClassListeners) == 0 {
+		return nil
+	}
+	listeners := make(map[string]contour.ListenerAddresses)
+	for _, l := range ctx.IngressClassListeners {
+		listeners[l.Class] = contour.ListenerAddresses{
+			HTTPAddress:  l.HTTPAddress,
+			HTTPPort:     l.HTTPPort,
+			HTTPSAddress: l.HTTPSAddress,
+			HTTPSPort:    l.HTTPSPort,
+		}
+	}
+	return listeners
+}
+
+// verifyIngressClassListeners indicates if the ingress class
+// listeners are set up correctly.
+func (ctx *serveContext) verifyIngressClassListeners() error {
+	seen := make(map[string]bool)
+	for _, l := range ctx.IngressClassListeners {
+		switch {
+		case l.Class == "":
+			return errors.New("ingress class listeners must specify a class")
+		case seen[l.Class]:
+			return fmt.Errorf("ingress class %q has more than one set of listeners", l.Class)
+		case l.HTTPPort == 0 || l.HTTPSPort == 0:
+			return fmt.Errorf("ingress class %q listeners must specify an http-port and https-port", l.Class)
+		}
+		seen[l.Class] = true
+	}
+
+	// Envoy rejects every listener if any two of them are bound to
+	// the same port, so each listener must be given its own.
+	type binding struct {
+		name    string
+		address string
+		port    int
+	}
+	bindings := []binding{
+		{"the default http listener", ctx.httpAddr, ctx.httpPort},
+		{"the default https listener", ctx.httpsAddr, ctx.httpsPort},
+	}
+	for _, l := range ctx.IngressClassListeners {
+		httpAddr, httpsAddr := l.HTTPAddress, l.HTTPSAddress
+		if httpAddr == "" {
+			httpAddr = ctx.httpAddr
+		}
+		if httpsAddr == "" {
+			httpsAddr = ctx.httpsAddr
+		}
+		bindings = append(bindings,
+			binding{fmt.Sprintf("the http listener of ingress class %q", l.Class), httpAddr, l.HTTPPort},
+			binding{fmt.Sprintf("the https listener of ingress class %q", l.Class), httpsAddr, l.HTTPSPort},
+		)
+	}
+	for i, a := range bindings {
+		for _, b := range bindings[:i] {
+			if a.port == b.port && overlaps(a.address, b.address) {
+				return fmt.Errorf("%s and %s are both bound to %s", b.name, a.name, net.JoinHostPort(a.address, strconv.Itoa(a.port)))
+			}
+		}
+	}
+	return nil
+}
+
+// overlaps returns true if listeners bound to addresses a and b on the
+// same port would conflict; an unspecified address, such as 0.0.0.0,
+// conflicts with every other address.
+func overlaps(a, b string) bool {
+	if a == b {
+		return true
+	}
+	for _, addr := range []string{a, b} {
+		if ip := net.ParseIP(addr); ip != nil && ip.IsUnspecified() {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRootNamespaces returns the namespaces in the comma
+// separated list s, or nil if s is empty.
+func parseRootNamespaces(s string) []string {
+	if strings.TrimSpace(s) == "" {
 		return nil
 	}
 	var ns []string
-	for _, s := range strings.Split(ctx.rootNamespaces, ",") {
-		ns = append(ns, strings.TrimSpace(s))
+	for _, n := range strings.Split(s, ",") {
+		ns = append(ns, strings.TrimSpace(n))
 	}
 	return ns
 }
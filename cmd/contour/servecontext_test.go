@@ -69,6 +69,159 @@ func TestServeContextIngressRouteRootNamespaces(t *testing.T) {
 	}
 }
 
+func TestServeContextVerifyWatchNamespaces(t *testing.T) {
+	tests := map[string]struct {
+		ctx         serveContext
+		expecterror bool
+	}{
+		"cluster-wide": {
+			ctx: serveContext{
+				rootNamespaces: "prod1,prod2",
+			},
+			expecterror: false,
+		},
+		"roots watched": {
+			ctx: serveContext{
+				rootNamespaces:  "prod1",
+				watchNamespaces: "prod1, prod2",
+			},
+			expecterror: false,
+		},
+		"no roots": {
+			ctx: serveContext{
+				watchNamespaces: "prod1",
+			},
+			expecterror: false,
+		},
+		"root not watched": {
+			ctx: serveContext{
+				rootNamespaces:  "prod1,prod3",
+				watchNamespaces: "prod1,prod2",
+			},
+			expecterror: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tc.ctx.verifyWatchNamespaces()
+			goterror := err != nil
+			if goterror != tc.expecterror {
+				t.Errorf("watch namespaces: %v", err)
+			}
+		})
+	}
+}
+
+func TestServeContextVerifyIngressClassListeners(t *testing.T) {
+	tests := map[string]struct {
+		listeners   []IngressClassListener
+		expecterror bool
+	}{
+		"none": {
+			expecterror: false,
+		},
+		"own ports": {
+			listeners: []IngressClassListener{
+				{Class: "internal", HTTPPort: 9080, HTTPSPort: 9443},
+				{Class: "staging", HTTPPort: 10080, HTTPSPort: 10443},
+			},
+			expecterror: false,
+		},
+		"same port on different addresses": {
+			listeners: []IngressClassListener{
+				{Class: "internal", HTTPAddress: "10.0.0.1", HTTPPort: 9080, HTTPSAddress: "10.0.0.1", HTTPSPort: 9443},
+				{Class: "staging", HTTPAddress: "10.0.0.2", HTTPPort: 9080, HTTPSAddress: "10.0.0.2", HTTPSPort: 9443},
+			},
+			expecterror: false,
+		},
+		"missing class": {
+			listeners:   []IngressClassListener{{HTTPPort: 9080, HTTPSPort: 9443}},
+			expecterror: true,
+		},
+		"missing port": {
+			listeners:   []IngressClassListener{{Class: "internal", HTTPPort: 9080}},
+			expecterror: true,
+		},
+		"duplicate class": {
+			listeners: []IngressClassListener{
+				{Class: "internal", HTTPPort: 9080, HTTPSPort: 9443},
+				{Class: "internal", HTTPPort: 10080, HTTPSPort: 10443},
+			},
+			expecterror: true,
+		},
+		"default http port": {
+			listeners:   []IngressClassListener{{Class: "internal", HTTPPort: 8080, HTTPSPort: 9443}},
+			expecterror: true,
+		},
+		"default https port on a specific address": {
+			listeners:   []IngressClassListener{{Class: "internal", HTTPPort: 9080, HTTPSAddress: "10.0.0.1", HTTPSPort: 8443}},
+			expecterror: true,
+		},
+		"http and https on one port": {
+			listeners:   []IngressClassListener{{Class: "internal", HTTPPort: 9080, HTTPSPort: 9080}},
+			expecterror: true,
+		},
+		"two classes on one port": {
+			listeners: []IngressClassListener{
+				{Class: "internal", HTTPPort: 9080, HTTPSPort: 9443},
+				{Class: "staging", HTTPPort: 10080, HTTPSPort: 9443},
+			},
+			expecterror: true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := newServeContext()
+			ctx.IngressClassListeners = tc.listeners
+			err := ctx.verifyIngressClassListeners()
+			goterror := err != nil
+			if goterror != tc.expecterror {
+				t.Errorf("ingress class listeners: %v", err)
+			}
+		})
+	}
+}
+
+func TestServeContextServedIngressClasses(t *testing.T) {
+	tests := map[string]struct {
+		ctx  serveContext
+		want []string
+	}{
+		"default": {
+			ctx:  serveContext{},
+			want: nil,
+		},
+		"flags only": {
+			ctx: serveContext{
+				ingressClasses: []string{"external", "internal"},
+			},
+			want: []string{"external", "internal"},
+		},
+		"listeners only": {
+			ctx: serveContext{
+				IngressClassListeners: []IngressClassListener{{Class: "internal"}},
+			},
+			want: []string{"contour", "internal"},
+		},
+		"flags and listeners": {
+			ctx: serveContext{
+				ingressClasses:        []string{"external", "internal"},
+				IngressClassListeners: []IngressClassListener{{Class: "internal"}, {Class: "staging"}},
+			},
+			want: []string{"external", "internal", "staging"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := tc.ctx.servedIngressClasses()
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("expected: %q, got: %q", tc.want, got)
+			}
+		})
+	}
+}
+
 func TestServeContextTLSParams(t *testing.T) {
 	tests := map[string]struct {
 		ctx         serveContext
@@ -176,6 +329,25 @@ leaderelection:
 				return ctx
 			},
 		},
+		"ingress class listeners": {
+			yamlIn: `
+ingress-class-listeners:
+- class: internal
+  http-address: 10.0.0.1
+  http-port: 9080
+  https-port: 9443
+`,
+			want: func() *serveContext {
+				ctx := newServeContext()
+				ctx.IngressClassListeners = []IngressClassListener{{
+					Class:       "internal",
+					HTTPAddress: "10.0.0.1",
+					HTTPPort:    9080,
+					HTTPSPort:   9443,
+				}}
+				return ctx
+			},
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -0,0 +1,154 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+
+	gatewayapi "github.com/projectcontour/contour/apis/networking/v1alpha1"
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/k8s"
+	"github.com/sirupsen/logrus"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// registerValidate registers the validate subcommand and flags
+// with the Application provided.
+func registerValidate(app *kingpin.Application) (*kingpin.CmdClause, *validateContext) {
+	var ctx validateContext
+
+	validate := app.Command("validate", "Validate Kubernetes manifests without a cluster.")
+	registerManifestFlags(validate, &ctx)
+	return validate, &ctx
+}
+
+// registerManifestFlags registers the arguments and flags which
+// control how manifests are loaded with the command provided.
+func registerManifestFlags(cmd *kingpin.CmdClause, ctx *validateContext) {
+	cmd.Arg("paths", "Manifest files, or directories to search for .yaml, .yml, and .json manifests").Required().ExistingFilesOrDirsVar(&ctx.paths)
+	cmd.Flag("namespace", "Namespace of objects which do not specify one").Default("default").StringVar(&ctx.namespace)
+	cmd.Flag("root-namespaces", "Restrict contour to searching these namespaces for root ingress routes").StringVar(&ctx.rootNamespaces)
+	cmd.Flag("ingress-class-name", "Contour IngressClass name. May be repeated").StringsVar(&ctx.ingressClasses)
+	cmd.Flag("disable-permit-insecure", "Disable the use of the permitInsecure field").BoolVar(&ctx.disablePermitInsecure)
+	cmd.Flag("gateway-controller-name", "Controller name of the Gateway API GatewayClasses to implement").StringVar(&ctx.gatewayController)
+}
+
+type validateContext struct {
+	paths                 []string
+	namespace             string
+	rootNamespaces        string
+	ingressClasses        []string
+	disablePermitInsecure bool
+	gatewayController     string
+}
+
+// builder returns a dag.Builder holding the objects in ctx's manifests.
+func (ctx *validateContext) builder(log logrus.FieldLogger) (*dag.Builder, error) {
+	objs, err := k8s.LoadManifests(ctx.paths...)
+	if err != nil {
+		return nil, err
+	}
+
+	builder := &dag.Builder{
+		Source: dag.KubernetesCache{
+			RootNamespaces:    parseRootNamespaces(ctx.rootNamespaces),
+			IngressClasses:    ctx.ingressClasses,
+			GatewayController: ctx.gatewayController,
+			FieldLogger:       log,
+		},
+		DisablePermitInsecure: ctx.disablePermitInsecure,
+	}
+	for _, obj := range objs {
+		if o, ok := obj.(metav1.Object); ok && o.GetNamespace() == "" && namespaced(obj) {
+			o.SetNamespace(ctx.namespace)
+		}
+		builder.Source.Insert(obj)
+	}
+	return builder, nil
+}
+
+// doValidate prints the status of each IngressRoute, HTTPProxy, and
+// Gateway API object in ctx's manifests and returns an error if any of them are invalid.
+func doValidate(ctx *validateContext, out io.Writer) error {
+	// problems found while loading objects are reported as warnings.
+	log := logrus.New()
+	log.SetOutput(ioutil.Discard)
+
+	builder, err := ctx.builder(log)
+	if err != nil {
+		return err
+	}
+
+	d := builder.Build()
+	var statuses []dag.Status
+	for _, st := range d.Statuses() {
+		statuses = append(statuses, st)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statusName(statuses[i]) < statusName(statuses[j])
+	})
+	statuses = append(statuses, d.GatewayStatuses()...)
+
+	invalid := 0
+	for _, st := range statuses {
+		if len(st.Errors) > 0 {
+			// the description repeats the first error.
+			fmt.Fprintf(out, "%s: %s\n", statusName(st), st.Status)
+		} else {
+			fmt.Fprintf(out, "%s: %s: %s\n", statusName(st), st.Status, st.Description)
+		}
+		for _, e := range st.Errors {
+			fmt.Fprintf(out, "  error: %s\n", e)
+		}
+		for _, w := range st.Warnings {
+			fmt.Fprintf(out, "  warning: %s\n", w)
+		}
+		if st.Status == dag.StatusInvalid {
+			invalid++
+		}
+	}
+
+	for _, w := range builder.Source.Warnings() {
+		if k8s.KindOf(w.Object) == "HTTPProxy" {
+			// already reported in the HTTPProxy's status.
+			continue
+		}
+		om := w.Object.GetObjectMeta()
+		fmt.Fprintf(out, "%s %s/%s: warning: %s\n", k8s.KindOf(w.Object), om.GetNamespace(), om.GetName(), w.Message)
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("%d invalid object(s)", invalid)
+	}
+	return nil
+}
+
+// namespaced returns false if obj is of a cluster scoped kind.
+func namespaced(obj interface{}) bool {
+	_, ok := obj.(*gatewayapi.GatewayClass)
+	return !ok
+}
+
+// statusName returns the kind, namespace, and name of st's object.
+func statusName(st dag.Status) string {
+	om := st.Object.GetObjectMeta()
+	if om.GetNamespace() == "" {
+		return fmt.Sprintf("%s %s", k8s.KindOf(st.Object), om.GetName())
+	}
+	return fmt.Sprintf("%s %s/%s", k8s.KindOf(st.Object), om.GetNamespace(), om.GetName())
+}
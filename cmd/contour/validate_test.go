@@ -0,0 +1,135 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDoValidate(t *testing.T) {
+	const service = `apiVersion: v1
+kind: Service
+metadata:
+  name: kuard
+spec:
+  ports:
+  - port: 80
+`
+	const root = `apiVersion: projectcontour.io/v1
+kind: HTTPProxy
+metadata:
+  name: root
+spec:
+  virtualhost:
+    fqdn: example.com
+  includes:
+  - name: child
+  routes:
+  - services:
+    - name: kuard
+      port: 80
+`
+	const child = `apiVersion: projectcontour.io/v1
+kind: HTTPProxy
+metadata:
+  name: child
+spec:
+  routes:
+  - conditions:
+    - prefix: /child
+    services:
+    - name: kuard
+      port: 80
+`
+	const annotated = `apiVersion: v1
+kind: Secret
+metadata:
+  name: annotated
+  annotations:
+    projectcontour.io/num-retries: "3"
+`
+
+	tests := map[string]struct {
+		files   map[string]string
+		want    string
+		wantErr string
+	}{
+		"valid": {
+			files: map[string]string{
+				"service.yaml": service,
+				"proxies.yaml": root + "---\n" + child,
+			},
+			want: `HTTPProxy default/child: valid: valid HTTPProxy
+HTTPProxy default/root: valid: valid HTTPProxy
+`,
+		},
+		"missing include": {
+			files: map[string]string{
+				"service.yml": service,
+				"root.yml":    root,
+				"notes.txt":   child,
+			},
+			want: `HTTPProxy default/root: invalid
+  error: include default/child not found
+`,
+			wantErr: "1 invalid object(s)",
+		},
+		"warnings": {
+			files: map[string]string{
+				"service.json": `{"apiVersion": "v1", "kind": "Service", "metadata": {"name": "kuard"}, "spec": {"ports": [{"port": 80}]}}`,
+				"root.yaml":    root + "---\n" + child + "---\n" + annotated,
+			},
+			want: `HTTPProxy default/child: valid: valid HTTPProxy
+HTTPProxy default/root: valid: valid HTTPProxy
+Secret default/annotated: warning: ignoring invalid or unsupported annotation "projectcontour.io/num-retries"
+`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "validate")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			for file, contents := range tc.files {
+				if err := ioutil.WriteFile(filepath.Join(dir, file), []byte(contents), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var out bytes.Buffer
+			err = doValidate(&validateContext{
+				paths:     []string{dir},
+				namespace: "default",
+			}, &out)
+
+			gotErr := ""
+			if err != nil {
+				gotErr = err.Error()
+			}
+			if gotErr != tc.wantErr {
+				t.Fatalf("expected error %q, got %q", tc.wantErr, gotErr)
+			}
+			if got := out.String(); got != tc.want {
+				t.Fatalf("expected:\n%s\ngot:\n%s", tc.want, got)
+			}
+		})
+	}
+}
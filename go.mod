@@ -37,4 +37,5 @@ require (
 	k8s.io/klog v0.4.0
 	mvdan.cc/unparam v0.0.0-20190720180237-d51796306d8f
 	sigs.k8s.io/controller-tools v0.2.2-0.20191004105652-6eef39898e44
+	sigs.k8s.io/yaml v1.1.0
 )
@@ -33,6 +33,11 @@ type CacheHandler struct {
 	ClusterCache
 	SecretCache
 
+	// Versions issues the version of each Snapshot. Sharing it with
+	// the EndpointsTranslator places every xDS resource type on one
+	// sequence. If nil, the CacheHandler allocates its own.
+	Versions *Versions
+
 	*metrics.Metrics
 
 	logrus.FieldLogger
@@ -42,30 +47,47 @@ func (ch *CacheHandler) OnChange(dag *dag.DAG) {
 	timer := prometheus.NewTimer(ch.CacheHandlerOnUpdateSummary)
 	defer timer.ObserveDuration()
 
-	ch.updateSecrets(dag)
-	ch.updateListeners(dag)
-	ch.updateRoutes(dag)
-	ch.updateClusters(dag)
+	if ch.Versions == nil {
+		ch.Versions = new(Versions)
+	}
+
+	snapshot := &Snapshot{
+		Version:   ch.Versions.Next(),
+		Secrets:   visitSecrets(dag),
+		Listeners: visitListeners(dag, &ch.ListenerVisitorConfig),
+		Routes:    visitRoutes(dag),
+		Clusters:  visitClusters(dag),
+	}
+	ch.publish(snapshot)
 
 	ch.SetDAGLastRebuilt(time.Now())
 }
 
-func (ch *CacheHandler) updateSecrets(root dag.Visitable) {
-	secrets := visitSecrets(root)
-	ch.SecretCache.Update(secrets)
-}
+// publish replaces the contents of each cache with those of s. The
+// caches are held locked together while they are replaced so a reader
+// of any cache observes either the previous Snapshot or s, never a
+// cache from each.
+func (ch *CacheHandler) publish(s *Snapshot) {
+	ch.SecretCache.mu.Lock()
+	defer ch.SecretCache.mu.Unlock()
+	ch.ListenerCache.mu.Lock()
+	defer ch.ListenerCache.mu.Unlock()
+	ch.RouteCache.mu.Lock()
+	defer ch.RouteCache.mu.Unlock()
+	ch.ClusterCache.mu.Lock()
+	defer ch.ClusterCache.mu.Unlock()
 
-func (ch *CacheHandler) updateListeners(root dag.Visitable) {
-	listeners := visitListeners(root, &ch.ListenerVisitorConfig)
-	ch.ListenerCache.Update(listeners)
-}
+	ch.SecretCache.values = s.Secrets
+	ch.ListenerCache.values = s.Listeners
+	ch.RouteCache.values = s.Routes
+	ch.ClusterCache.values = s.Clusters
 
-func (ch *CacheHandler) updateRoutes(root dag.Visitable) {
-	routes := visitRoutes(root)
-	ch.RouteCache.Update(routes)
-}
+	// waiters are notified only once every cache holds s, so the
+	// response to a notification is never from an older Snapshot.
+	ch.SecretCache.notifyVersion(s.Version)
+	ch.ListenerCache.notifyVersion(s.Version)
+	ch.RouteCache.notifyVersion(s.Version)
+	ch.ClusterCache.notifyVersion(s.Version)
 
-func (ch *CacheHandler) updateClusters(root dag.Visitable) {
-	clusters := visitClusters(root)
-	ch.ClusterCache.Update(clusters)
+	ch.WithField("version", s.Version).Debug("published snapshot")
 }
@@ -0,0 +1,75 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contour
+
+import (
+	"testing"
+
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestCacheHandlerOnChangeVersions(t *testing.T) {
+	versions := new(Versions)
+	ch := &CacheHandler{
+		Metrics:     metrics.NewMetrics(prometheus.NewRegistry()),
+		Versions:    versions,
+		FieldLogger: testLogger(t),
+	}
+	et := &EndpointsTranslator{
+		FieldLogger: testLogger(t),
+		Versions:    versions,
+	}
+
+	// assertVersions asserts every xDS cache reports the expected version.
+	assertVersions := func(caches, eds int) {
+		t.Helper()
+		got := map[string]int{}
+		got["cds"], _ = ch.ClusterCache.Snapshot(nil)
+		got["rds"], _ = ch.RouteCache.Snapshot(nil)
+		got["lds"], _ = ch.ListenerCache.Snapshot(nil)
+		got["sds"], _ = ch.SecretCache.Snapshot(nil)
+		for typ, v := range got {
+			if v != caches {
+				t.Fatalf("%s: expected version %d, got %d", typ, caches, v)
+			}
+		}
+		if v, _ := et.Snapshot(nil); v != eds {
+			t.Fatalf("eds: expected version %d, got %d", eds, v)
+		}
+	}
+
+	b := dag.Builder{
+		Source: dag.KubernetesCache{
+			FieldLogger: testLogger(t),
+		},
+	}
+
+	ch.OnChange(b.Build())
+	assertVersions(1, 0)
+
+	et.OnAdd(endpoints("default", "kuard", v1.EndpointSubset{
+		Addresses: addresses("192.168.183.24"),
+		Ports: []v1.EndpointPort{{
+			Port:     8080,
+			Protocol: "TCP",
+		}},
+	}))
+	assertVersions(1, 2)
+
+	ch.OnChange(b.Build())
+	assertVersions(3, 2)
+}
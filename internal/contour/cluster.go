@@ -31,19 +31,14 @@ type ClusterCache struct {
 	Cond
 }
 
-// Update replaces the contents of the cache with the supplied map.
-func (c *ClusterCache) Update(v map[string]*envoy_api_v2.Cluster) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	c.values = v
-	c.Cond.Notify()
-}
-
 // Contents returns a copy of the cache's contents.
 func (c *ClusterCache) Contents() []proto.Message {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	return c.contents()
+}
+
+func (c *ClusterCache) contents() []proto.Message {
 	var values []proto.Message
 	for _, v := range c.values {
 		values = append(values, v)
@@ -55,6 +50,21 @@ func (c *ClusterCache) Contents() []proto.Message {
 func (c *ClusterCache) Query(names []string) []proto.Message {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	return c.query(names)
+}
+
+// Snapshot returns the version of the cache and its contents, or the
+// entries matching names, at that version.
+func (c *ClusterCache) Snapshot(names []string) (int, []proto.Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(names) == 0 {
+		return c.version(), c.contents()
+	}
+	return c.version(), c.query(names)
+}
+
+func (c *ClusterCache) query(names []string) []proto.Message {
 	var values []proto.Message
 	for _, n := range names {
 		// if the cluster is not registered we cannot return
@@ -20,11 +20,15 @@ import (
 	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
 	envoy_api_v2_cluster "github.com/envoyproxy/go-control-plane/envoy/api/v2/cluster"
 	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	tcp "github.com/envoyproxy/go-control-plane/envoy/config/filter/network/tcp_proxy/v2"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/duration"
 	ingressroutev1 "github.com/projectcontour/contour/apis/contour/v1beta1"
 	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
 	"github.com/projectcontour/contour/internal/assert"
+	"github.com/projectcontour/contour/internal/dag"
 	"github.com/projectcontour/contour/internal/envoy"
 	"github.com/projectcontour/contour/internal/protobuf"
 	v1 "k8s.io/api/core/v1"
@@ -70,7 +74,7 @@ func TestClusterCacheContents(t *testing.T) {
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			var cc ClusterCache
-			cc.Update(tc.contents)
+			cc.values = tc.contents
 			got := cc.Contents()
 			assert.Equal(t, tc.want, got)
 		})
@@ -150,7 +154,7 @@ func TestClusterCacheQuery(t *testing.T) {
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			var cc ClusterCache
-			cc.Update(tc.contents)
+			cc.values = tc.contents
 			got := cc.Query(tc.query)
 			assert.Equal(t, tc.want, got)
 		})
@@ -830,6 +834,214 @@ func TestClusterVisit(t *testing.T) {
 	}
 }
 
+func TestClusterVisitConnectionTimeouts(t *testing.T) {
+	defaults := dag.ConnectionTimeoutPolicy{
+		ConnectTimeout:        1500 * time.Millisecond,
+		IdleConnectionTimeout: time.Minute,
+	}
+	kuard := service("default", "kuard",
+		v1.ServicePort{
+			Protocol: "TCP",
+			Name:     "http",
+			Port:     80,
+		},
+	)
+
+	tests := map[string]struct {
+		objs []interface{}
+		want map[string]*v2.Cluster
+	}{
+		"ingress uses defaults": {
+			objs: []interface{}{
+				&v1beta1.Ingress{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "kuard",
+						Namespace: "default",
+					},
+					Spec: v1beta1.IngressSpec{
+						Backend: &v1beta1.IngressBackend{
+							ServiceName: "kuard",
+							ServicePort: intstr.FromString("http"),
+						},
+					},
+				},
+				kuard,
+			},
+			want: clustermap(
+				&v2.Cluster{
+					Name:                 "default/kuard/80/4a9aae2897",
+					AltStatName:          "default_kuard_80",
+					ClusterDiscoveryType: envoy.ClusterDiscoveryType(v2.Cluster_EDS),
+					EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+						EdsConfig:   envoy.ConfigSource("contour"),
+						ServiceName: "default/kuard/http",
+					},
+					ConnectTimeout: protobuf.Duration(1500 * time.Millisecond),
+					CommonHttpProtocolOptions: &envoy_api_v2_core.HttpProtocolOptions{
+						IdleTimeout: protobuf.Duration(time.Minute),
+					},
+				},
+			),
+		},
+		"httpproxy service policy overrides defaults": {
+			objs: []interface{}{
+				&projcontour.HTTPProxy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "kuard",
+						Namespace: "default",
+					},
+					Spec: projcontour.HTTPProxySpec{
+						VirtualHost: &projcontour.VirtualHost{
+							Fqdn: "www.example.com",
+						},
+						Routes: []projcontour.Route{{
+							Services: []projcontour.Service{{
+								Name: "kuard",
+								Port: 80,
+								ConnectionTimeoutPolicy: &projcontour.ConnectionTimeoutPolicy{
+									ConnectTimeout:        "2.5s",
+									MaxConnectionDuration: "1h",
+								},
+							}},
+						}},
+					},
+				},
+				kuard,
+			},
+			want: clustermap(
+				&v2.Cluster{
+					Name:                 "default/kuard/80/ecfbe8a5d0",
+					AltStatName:          "default_kuard_80",
+					ClusterDiscoveryType: envoy.ClusterDiscoveryType(v2.Cluster_EDS),
+					EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+						EdsConfig:   envoy.ConfigSource("contour"),
+						ServiceName: "default/kuard/http",
+					},
+					ConnectTimeout: protobuf.Duration(2500 * time.Millisecond),
+					CommonHttpProtocolOptions: &envoy_api_v2_core.HttpProtocolOptions{
+						IdleTimeout:           protobuf.Duration(time.Minute),
+						MaxConnectionDuration: protobuf.Duration(time.Hour),
+					},
+				},
+			),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := buildDAGWithTimeouts(t, defaults, tc.objs...)
+			got := visitClusters(root)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestConnectionTimeoutClusterNames(t *testing.T) {
+	root := buildDAGWithTimeouts(t, dag.ConnectionTimeoutPolicy{
+		ConnectTimeout:        1500 * time.Millisecond,
+		IdleConnectionTimeout: time.Minute,
+		MaxConnectionDuration: time.Hour,
+	},
+		service("default", "kuard", v1.ServicePort{
+			Protocol: "TCP",
+			Name:     "http",
+			Port:     80,
+		}),
+		&projcontour.HTTPProxy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "kuard",
+				Namespace: "default",
+			},
+			Spec: projcontour.HTTPProxySpec{
+				VirtualHost: &projcontour.VirtualHost{
+					Fqdn: "www.example.com",
+				},
+				Routes: []projcontour.Route{{
+					Services: []projcontour.Service{{
+						Name: "kuard",
+						Port: 80,
+						ConnectionTimeoutPolicy: &projcontour.ConnectionTimeoutPolicy{
+							ConnectTimeout: "2.5s",
+						},
+					}},
+				}},
+			},
+		},
+		&projcontour.HTTPProxy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "passthrough",
+				Namespace: "default",
+			},
+			Spec: projcontour.HTTPProxySpec{
+				VirtualHost: &projcontour.VirtualHost{
+					Fqdn: "passthrough.example.com",
+					TLS: &projcontour.TLS{
+						Passthrough: true,
+					},
+				},
+				TCPProxy: &projcontour.TCPProxy{
+					Services: []projcontour.Service{{
+						Name: "kuard",
+						Port: 80,
+					}},
+				},
+			},
+		},
+	)
+
+	clusters := visitClusters(root)
+
+	var names []string
+	for _, rc := range visitRoutes(root) {
+		for _, vh := range rc.VirtualHosts {
+			for _, r := range vh.Routes {
+				names = append(names, r.GetRoute().GetCluster())
+			}
+		}
+	}
+	for _, l := range visitListeners(root, new(ListenerVisitorConfig)) {
+		for _, fc := range l.FilterChains {
+			for _, f := range fc.Filters {
+				if f.Name != wellknown.TCPProxy {
+					continue
+				}
+				var tp tcp.TcpProxy
+				if err := ptypes.UnmarshalAny(f.GetTypedConfig(), &tp); err != nil {
+					t.Fatal(err)
+				}
+				names = append(names, tp.GetCluster())
+			}
+		}
+	}
+
+	if len(names) != 2 {
+		t.Fatalf("expected a route and a tcpproxy cluster, got %v", names)
+	}
+	for _, name := range names {
+		if _, ok := clusters[name]; !ok {
+			t.Errorf("cluster %q is not in CDS", name)
+		}
+	}
+
+	// the idle timeout and maximum duration do not apply to tcpproxy clusters.
+	if c := clusters[names[1]]; c != nil && c.CommonHttpProtocolOptions != nil {
+		t.Errorf("tcpproxy cluster %q: unexpected http protocol options %v", c.Name, c.CommonHttpProtocolOptions)
+	}
+}
+
+func buildDAGWithTimeouts(t *testing.T, timeouts dag.ConnectionTimeoutPolicy, objs ...interface{}) *dag.DAG {
+	builder := dag.Builder{
+		Source: dag.KubernetesCache{
+			FieldLogger: testLogger(t),
+		},
+		ConnectionTimeouts: timeouts,
+	}
+	for _, o := range objs {
+		builder.Source.Insert(o)
+	}
+	return builder.Build()
+}
+
 func service(ns, name string, ports ...v1.ServicePort) *v1.Service {
 	return serviceWithAnnotations(ns, name, nil, ports...)
 }
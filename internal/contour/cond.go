@@ -58,7 +58,28 @@ func (c *Cond) Register(ch chan int, last int, hints ...string) {
 func (c *Cond) Notify(hints ...string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.last++
+	c.notify(c.last+1, hints)
+}
+
+// notifyVersion is like Notify but advances the Cond's counter to
+// version rather than incrementing it. This permits several Conds to
+// share a version Sequence. version must be greater than any value
+// previously notified.
+func (c *Cond) notifyVersion(version int, hints ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify(version, hints)
+}
+
+// version returns the value of the Cond's counter.
+func (c *Cond) version() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.last
+}
+
+func (c *Cond) notify(version int, hints []string) {
+	c.last = version
 
 	notify := c.waiters
 	c.waiters = nil
@@ -106,3 +106,25 @@ func TestCondRegisterWithHintShouldNotNotifyWithWrongHint(t *testing.T) {
 		t.Fatal("ch was not notified")
 	}
 }
+
+func TestCondNotifyVersionShouldBroadcastVersion(t *testing.T) {
+	var c Cond
+	ch := make(chan int, 1)
+	c.Register(ch, 0)
+	c.notifyVersion(7)
+	select {
+	case v := <-ch:
+		if v != 7 {
+			t.Fatal("ch was notified with the wrong sequence number", v)
+		}
+	default:
+		t.Fatal("ch was not notified")
+	}
+
+	// a subsequent Notify continues from the notified version.
+	c.Register(ch, 7)
+	c.Notify()
+	if v := <-ch; v != 8 {
+		t.Fatal("ch was notified with the wrong sequence number", v)
+	}
+}
@@ -0,0 +1,431 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contour
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	"github.com/projectcontour/contour/internal/k8s"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	k8scache "k8s.io/client-go/tools/cache"
+)
+
+// An EndpointSliceTranslator translates Kubernetes EndpointSlice objects
+// into Envoy ClusterLoadAssignment objects. A Service may have many
+// EndpointSlices; their endpoints are merged for each Service port.
+type EndpointSliceTranslator struct {
+	logrus.FieldLogger
+
+	// Versions issues the version of each change to the EDS
+	// cache. Sharing it with the CacheHandler places every xDS
+	// resource type on one sequence. If nil, the EDS cache
+	// counts its own versions.
+	Versions *Versions
+
+	clusterLoadAssignmentCache
+
+	// mu protects slices, localities and slow start state,
+	// which are updated by the EndpointSlice, Node and Service
+	// informers.
+	mu sync.Mutex
+
+	// slices holds the EndpointSlices of each Service, by name.
+	slices map[types.NamespacedName]map[string]*k8s.EndpointSlice
+
+	localities nodeLocalities
+
+	slowStart slowStart
+}
+
+func (e *EndpointSliceTranslator) OnAdd(obj interface{}) {
+	switch obj := obj.(type) {
+	case *unstructured.Unstructured:
+		if slice := e.endpointSlice(obj); slice != nil {
+			e.OnAdd(slice)
+		}
+	case *k8s.EndpointSlice:
+		e.updateEndpointSlice(obj)
+	case *v1.Node:
+		e.updateNode(obj)
+	case *v1.Service:
+		e.updateService(obj)
+	default:
+		e.Errorf("OnAdd unexpected type %T: %#v", obj, obj)
+	}
+}
+
+func (e *EndpointSliceTranslator) OnUpdate(oldObj, newObj interface{}) {
+	switch newObj := newObj.(type) {
+	case *unstructured.Unstructured:
+		if slice := e.endpointSlice(newObj); slice != nil {
+			e.OnUpdate(oldObj, slice)
+		}
+	case *k8s.EndpointSlice:
+		e.updateEndpointSlice(newObj)
+	case *v1.Node:
+		e.updateNode(newObj)
+	case *v1.Service:
+		e.updateService(newObj)
+	default:
+		e.Errorf("OnUpdate unexpected type %T: %#v", newObj, newObj)
+	}
+}
+
+func (e *EndpointSliceTranslator) OnDelete(obj interface{}) {
+	switch obj := obj.(type) {
+	case *unstructured.Unstructured:
+		if slice := e.endpointSlice(obj); slice != nil {
+			e.OnDelete(slice)
+		}
+	case *k8s.EndpointSlice:
+		e.removeEndpointSlice(obj)
+	case *v1.Node:
+		e.removeNode(obj)
+	case *v1.Service:
+		e.setSlowStartWindow(types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}, 0)
+	case k8scache.DeletedFinalStateUnknown:
+		e.OnDelete(obj.Obj) // recurse into ourselves with the tombstoned value
+	default:
+		e.Errorf("OnDelete unexpected type %T: %#v", obj, obj)
+	}
+}
+
+// endpointSlice converts u to an EndpointSlice, or logs
+// and returns nil if u is not a valid EndpointSlice.
+func (e *EndpointSliceTranslator) endpointSlice(u *unstructured.Unstructured) *k8s.EndpointSlice {
+	slice, err := k8s.ToEndpointSlice(u)
+	if err != nil {
+		e.WithError(err).Errorf("invalid EndpointSlice %s/%s", u.GetNamespace(), u.GetName())
+		return nil
+	}
+	return slice
+}
+
+func (e *EndpointSliceTranslator) updateEndpointSlice(slice *k8s.EndpointSlice) {
+	service, ok := serviceOf(slice)
+	if !ok {
+		// not managed by a Service.
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	old := e.clusterLoadAssignments(service)
+	if e.slices == nil {
+		e.slices = make(map[types.NamespacedName]map[string]*k8s.EndpointSlice)
+	}
+	if e.slices[service] == nil {
+		e.slices[service] = make(map[string]*k8s.EndpointSlice)
+	}
+	// the endpoints of EndpointSlices seen for the first time, for
+	// example when Contour starts, are not slow started.
+	_, seen := e.slices[service][slice.Name]
+	e.slices[service][slice.Name] = slice
+	e.slowStart.observe(service.String(), e.readyIPs(service), !seen)
+	e.recomputeService(service, old)
+}
+
+func (e *EndpointSliceTranslator) removeEndpointSlice(slice *k8s.EndpointSlice) {
+	service, ok := serviceOf(slice)
+	if !ok {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.slices[service][slice.Name]; !ok {
+		return
+	}
+	old := e.clusterLoadAssignments(service)
+	delete(e.slices[service], slice.Name)
+	if len(e.slices[service]) == 0 {
+		delete(e.slices, service)
+		e.slowStart.forget(service.String())
+	} else {
+		e.slowStart.observe(service.String(), e.readyIPs(service), false)
+	}
+	e.recomputeService(service, old)
+}
+
+// updateService records the slow start window of svc.
+func (e *EndpointSliceTranslator) updateService(svc *v1.Service) {
+	window, err := slowStartWindow(svc)
+	if err != nil {
+		e.WithError(err).Errorf("invalid slow start window on Service %s/%s", svc.Namespace, svc.Name)
+	}
+	e.setSlowStartWindow(types.NamespacedName{Namespace: svc.Namespace, Name: svc.Name}, window)
+}
+
+// setSlowStartWindow records the slow start window of service and,
+// if it has changed, recomputes its assignments.
+func (e *EndpointSliceTranslator) setSlowStartWindow(service types.NamespacedName, window time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.slowStart.setWindow(service.String(), window) {
+		return
+	}
+	add := e.clusterLoadAssignments(service)
+	if len(add) == 0 {
+		return
+	}
+	e.update(e.Versions, add, nil)
+	e.slowStart.schedule(service.String(), e.rampUp)
+}
+
+// rampUp recomputes the assignments of the Services whose endpoints
+// are ramping up, until each has finished its slow start.
+func (e *EndpointSliceTranslator) rampUp() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var add []*v2.ClusterLoadAssignment
+	for _, key := range e.slowStart.due(e.rampUp) {
+		namespace, name, err := k8scache.SplitMetaNamespaceKey(key)
+		if err != nil {
+			continue
+		}
+		add = append(add, e.clusterLoadAssignments(types.NamespacedName{Namespace: namespace, Name: name})...)
+	}
+	if len(add) == 0 {
+		return
+	}
+	e.update(e.Versions, add, nil)
+}
+
+// updateNode records the locality of node and, if it has changed,
+// recomputes the assignments of the Services with endpoints on node.
+func (e *EndpointSliceTranslator) updateNode(node *v1.Node) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.localities.update(node) {
+		e.recomputeNode(node.Name)
+	}
+}
+
+// removeNode forgets the locality of node and recomputes the
+// assignments of the Services with endpoints on node.
+func (e *EndpointSliceTranslator) removeNode(node *v1.Node) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.localities.remove(node) {
+		e.recomputeNode(node.Name)
+	}
+}
+
+// recomputeNode recomputes the assignments of every Service
+// with an endpoint on the named node.
+func (e *EndpointSliceTranslator) recomputeNode(name string) {
+	var add []*v2.ClusterLoadAssignment
+	for service, slices := range e.slices {
+		for _, slice := range slices {
+			if sliceOnNode(slice, name) {
+				add = append(add, e.clusterLoadAssignments(service)...)
+				break
+			}
+		}
+	}
+	if len(add) == 0 {
+		return
+	}
+	e.update(e.Versions, add, nil)
+}
+
+// recomputeService recomputes the assignments of service, removing
+// those in old which are no longer present.
+func (e *EndpointSliceTranslator) recomputeService(service types.NamespacedName, old []*v2.ClusterLoadAssignment) {
+	add := e.clusterLoadAssignments(service)
+	seen := make(map[string]bool)
+	for _, cla := range add {
+		seen[cla.ClusterName] = true
+	}
+
+	var remove []string
+	for _, cla := range old {
+		if !seen[cla.ClusterName] {
+			remove = append(remove, cla.ClusterName)
+		}
+	}
+
+	if len(add) == 0 && len(remove) == 0 {
+		return
+	}
+	e.update(e.Versions, add, remove)
+	e.slowStart.schedule(service.String(), e.rampUp)
+}
+
+// clusterLoadAssignments returns a ClusterLoadAssignment for each TCP
+// port of service with endpoints, merging the endpoints of each of its
+// EndpointSlices. While any endpoint of service is ramping up, every
+// endpoint is weighted by its progress through the slow start window.
+func (e *EndpointSliceTranslator) clusterLoadAssignments(service types.NamespacedName) []*v2.ClusterLoadAssignment {
+	now := e.slowStart.time()
+	ramping := e.slowStart.ramping(service.String(), now)
+
+	slices := make([]*k8s.EndpointSlice, 0, len(e.slices[service]))
+	for _, slice := range e.slices[service] {
+		slices = append(slices, slice)
+	}
+	sort.Slice(slices, func(i, j int) bool { return slices[i].Name < slices[j].Name })
+
+	ports := make(map[string][]localityAddress)
+	seen := make(map[string]map[localityAddress]bool)
+	for _, slice := range slices {
+		if slice.AddressType != "IPv4" && slice.AddressType != "IPv6" {
+			// skip FQDN slices
+			continue
+		}
+		for _, p := range slice.Ports {
+			if p.Protocol != nil && *p.Protocol != v1.ProtocolTCP {
+				// skip non TCP ports
+				continue
+			}
+			if p.Port == nil {
+				continue
+			}
+			var portname string
+			if p.Name != nil {
+				portname = *p.Name
+			}
+			if seen[portname] == nil {
+				seen[portname] = make(map[localityAddress]bool)
+			}
+
+			for _, ep := range slice.Endpoints {
+				health, ok := endpointHealth(ep.Conditions)
+				if len(ep.Addresses) == 0 || !ok {
+					continue
+				}
+				// addresses are fungible, use the first.
+				addr := localityAddress{
+					ip:   ep.Addresses[0],
+					port: int(*p.Port),
+				}
+				if seen[portname][addr] {
+					// the same endpoint may briefly appear in two slices.
+					continue
+				}
+				seen[portname][addr] = true
+				addr.locality = e.endpointLocality(ep)
+				addr.health = health
+				if ramping {
+					addr.weight = e.slowStart.weight(service.String(), addr.ip, now)
+				}
+				ports[portname] = append(ports[portname], addr)
+			}
+		}
+	}
+
+	meta := metav1.ObjectMeta{
+		Namespace: service.Namespace,
+		Name:      service.Name,
+	}
+	var clas []*v2.ClusterLoadAssignment
+	for portname, addresses := range ports {
+		sort.Slice(addresses, func(i, j int) bool {
+			if addresses[i].ip != addresses[j].ip {
+				return addresses[i].ip < addresses[j].ip
+			}
+			return addresses[i].port < addresses[j].port
+		})
+		clas = append(clas, &v2.ClusterLoadAssignment{
+			ClusterName: servicename(meta, portname),
+			Endpoints:   localityLbEndpoints(addresses),
+		})
+	}
+	sort.Slice(clas, func(i, j int) bool { return clas[i].ClusterName < clas[j].ClusterName })
+	return clas
+}
+
+// readyIPs returns the addresses of the ready endpoints of service.
+func (e *EndpointSliceTranslator) readyIPs(service types.NamespacedName) []string {
+	var ips []string
+	for _, slice := range e.slices[service] {
+		if slice.AddressType != "IPv4" && slice.AddressType != "IPv6" {
+			continue
+		}
+		for _, ep := range slice.Endpoints {
+			if health, ok := endpointHealth(ep.Conditions); len(ep.Addresses) > 0 && ok && health == envoy_api_v2_core.HealthStatus_UNKNOWN {
+				ips = append(ips, ep.Addresses[0])
+			}
+		}
+	}
+	return ips
+}
+
+// endpointLocality returns the locality of the Node of ep or, if that is
+// not known, the zone the EndpointSlice controller recorded for ep.
+func (e *EndpointSliceTranslator) endpointLocality(ep k8s.Endpoint) *envoy_api_v2_core.Locality {
+	if locality := e.localities.lookup(ep.NodeName); locality != nil {
+		return locality
+	}
+	if ep.Zone != nil && *ep.Zone != "" {
+		return &envoy_api_v2_core.Locality{
+			Zone: *ep.Zone,
+		}
+	}
+	return nil
+}
+
+// endpointHealth returns the health status of an endpoint with these
+// conditions, and false if the endpoint should not be sent to Envoy.
+// Terminating endpoints which are still serving are draining, so Envoy
+// sends them no new requests but lets those in flight finish. Other
+// endpoints which are not ready, such as those still starting, are not
+// sent. An unknown ready condition is taken as ready.
+func endpointHealth(c k8s.EndpointConditions) (envoy_api_v2_core.HealthStatus, bool) {
+	terminating := c.Terminating != nil && *c.Terminating
+	switch {
+	case terminating && c.Serving != nil && *c.Serving:
+		return envoy_api_v2_core.HealthStatus_DRAINING, true
+	case terminating, c.Ready != nil && !*c.Ready:
+		return envoy_api_v2_core.HealthStatus_UNKNOWN, false
+	default:
+		return envoy_api_v2_core.HealthStatus_UNKNOWN, true
+	}
+}
+
+// serviceOf returns the name of the Service slice belongs to.
+func serviceOf(slice *k8s.EndpointSlice) (types.NamespacedName, bool) {
+	name, ok := slice.Labels[k8s.ServiceNameLabel]
+	if !ok || name == "" {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{
+		Namespace: slice.Namespace,
+		Name:      name,
+	}, true
+}
+
+// sliceOnNode returns true if any endpoint of slice is placed on the named node.
+func sliceOnNode(slice *k8s.EndpointSlice, name string) bool {
+	for _, ep := range slice.Endpoints {
+		if ep.NodeName != nil && *ep.NodeName == name {
+			return true
+		}
+	}
+	return false
+}
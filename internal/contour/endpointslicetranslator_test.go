@@ -0,0 +1,401 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contour
+
+import (
+	"testing"
+	"time"
+
+	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	envoy_api_v2_endpoint "github.com/envoyproxy/go-control-plane/envoy/api/v2/endpoint"
+	"github.com/golang/protobuf/proto"
+	"github.com/projectcontour/contour/internal/assert"
+	"github.com/projectcontour/contour/internal/envoy"
+	"github.com/projectcontour/contour/internal/k8s"
+	"github.com/projectcontour/contour/internal/protobuf"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestEndpointSliceTranslatorAddEndpointSlices(t *testing.T) {
+	tests := map[string]struct {
+		slices []*k8s.EndpointSlice
+		want   []proto.Message
+	}{
+		"simple": {
+			slices: []*k8s.EndpointSlice{
+				endpointSlice("default", "simple-abc", "simple",
+					slicePorts(slicePort("", 8080)),
+					sliceEndpoint("192.168.183.24"),
+				),
+			},
+			want: []proto.Message{
+				envoy.ClusterLoadAssignment("default/simple", envoy.SocketAddress("192.168.183.24", 8080)),
+			},
+		},
+		"slices are merged": {
+			slices: []*k8s.EndpointSlice{
+				endpointSlice("default", "simple-abc", "simple",
+					slicePorts(slicePort("http", 8080)),
+					sliceEndpoint("10.0.0.2"),
+				),
+				endpointSlice("default", "simple-def", "simple",
+					slicePorts(slicePort("http", 8080)),
+					sliceEndpoint("10.0.0.1"),
+					sliceEndpoint("10.0.0.2"), // duplicates are ignored
+				),
+			},
+			want: []proto.Message{
+				envoy.ClusterLoadAssignment("default/simple/http",
+					envoy.SocketAddress("10.0.0.1", 8080),
+					envoy.SocketAddress("10.0.0.2", 8080),
+				),
+			},
+		},
+		"multiple ports": {
+			slices: []*k8s.EndpointSlice{
+				endpointSlice("default", "httpbin-org-abc", "httpbin-org",
+					slicePorts(slicePort("b", 309), slicePort("a", 8675)),
+					sliceEndpoint("10.10.1.1"),
+				),
+			},
+			want: []proto.Message{
+				envoy.ClusterLoadAssignment("default/httpbin-org/a",
+					envoy.SocketAddress("10.10.1.1", 8675),
+				),
+				envoy.ClusterLoadAssignment("default/httpbin-org/b",
+					envoy.SocketAddress("10.10.1.1", 309),
+				),
+			},
+		},
+		"not ready and terminating endpoints": {
+			slices: []*k8s.EndpointSlice{
+				endpointSlice("default", "simple-abc", "simple",
+					slicePorts(slicePort("", 8080)),
+					sliceEndpoint("10.0.0.1"),
+					withConditions(sliceEndpoint("10.0.0.2"), false, false, false),
+					withConditions(sliceEndpoint("10.0.0.3"), false, true, true),
+					withConditions(sliceEndpoint("10.0.0.4"), true, true, false),
+					withConditions(sliceEndpoint("10.0.0.5"), false, false, true),
+				),
+			},
+			want: []proto.Message{
+				&v2.ClusterLoadAssignment{
+					ClusterName: "default/simple",
+					Endpoints: []*envoy_api_v2_endpoint.LocalityLbEndpoints{{
+						LbEndpoints: []*envoy_api_v2_endpoint.LbEndpoint{
+							envoy.LBEndpoint(envoy.SocketAddress("10.0.0.1", 8080)),
+							draining(envoy.LBEndpoint(envoy.SocketAddress("10.0.0.3", 8080))),
+							envoy.LBEndpoint(envoy.SocketAddress("10.0.0.4", 8080)),
+						},
+						LoadBalancingWeight: protobuf.UInt32(3),
+					}},
+				},
+			},
+		},
+		"no ready endpoints": {
+			slices: []*k8s.EndpointSlice{
+				endpointSlice("default", "simple-abc", "simple",
+					slicePorts(slicePort("", 8080)),
+					withConditions(sliceEndpoint("10.0.0.1"), false, false, false),
+				),
+			},
+			want: nil,
+		},
+		"slices without a service are ignored": {
+			slices: []*k8s.EndpointSlice{
+				endpointSlice("default", "custom", "",
+					slicePorts(slicePort("", 8080)),
+					sliceEndpoint("10.0.0.1"),
+				),
+			},
+			want: nil,
+		},
+		"fqdn slices are ignored": {
+			slices: []*k8s.EndpointSlice{
+				func() *k8s.EndpointSlice {
+					slice := endpointSlice("default", "simple-abc", "simple",
+						slicePorts(slicePort("", 8080)),
+						sliceEndpoint("www.example.com"),
+					)
+					slice.AddressType = "FQDN"
+					return slice
+				}(),
+			},
+			want: nil,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			et := &EndpointSliceTranslator{
+				FieldLogger: testLogger(t),
+			}
+			for _, slice := range tc.slices {
+				et.OnAdd(slice)
+			}
+			assert.Equal(t, tc.want, et.Contents())
+		})
+	}
+}
+
+func TestEndpointSliceTranslatorRemoveEndpointSlices(t *testing.T) {
+	et := &EndpointSliceTranslator{
+		FieldLogger: testLogger(t),
+	}
+
+	s1 := endpointSlice("default", "simple-abc", "simple",
+		slicePorts(slicePort("http", 8080)),
+		sliceEndpoint("10.0.0.1"),
+	)
+	s2 := endpointSlice("default", "simple-def", "simple",
+		slicePorts(slicePort("http", 8080), slicePort("admin", 9000)),
+		sliceEndpoint("10.0.0.2"),
+	)
+	et.OnAdd(s1)
+	et.OnAdd(s2)
+
+	want := []proto.Message{
+		envoy.ClusterLoadAssignment("default/simple/admin",
+			envoy.SocketAddress("10.0.0.2", 9000),
+		),
+		envoy.ClusterLoadAssignment("default/simple/http",
+			envoy.SocketAddress("10.0.0.1", 8080),
+			envoy.SocketAddress("10.0.0.2", 8080),
+		),
+	}
+	assert.Equal(t, want, et.Contents())
+
+	// removing s2 removes the admin port, and its endpoint from the http port.
+	et.OnDelete(s2)
+	want = []proto.Message{
+		envoy.ClusterLoadAssignment("default/simple/http",
+			envoy.SocketAddress("10.0.0.1", 8080),
+		),
+	}
+	assert.Equal(t, want, et.Contents())
+
+	// s1 is now without endpoints.
+	et.OnUpdate(s1, endpointSlice("default", "simple-abc", "simple",
+		slicePorts(slicePort("http", 8080)),
+	))
+	assert.Equal(t, []proto.Message(nil), et.Contents())
+}
+
+func TestEndpointSliceTranslatorUnstructured(t *testing.T) {
+	et := &EndpointSliceTranslator{
+		FieldLogger: testLogger(t),
+	}
+
+	u := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "discovery.k8s.io/v1",
+			"kind":       "EndpointSlice",
+			"metadata": map[string]interface{}{
+				"name":      "simple-abc",
+				"namespace": "default",
+				"labels": map[string]interface{}{
+					"kubernetes.io/service-name": "simple",
+				},
+			},
+			"addressType": "IPv4",
+			"endpoints": []interface{}{
+				map[string]interface{}{
+					"addresses": []interface{}{"10.0.0.1"},
+					"conditions": map[string]interface{}{
+						"ready": true,
+					},
+					"zone": "us-east-1a",
+				},
+			},
+			"ports": []interface{}{
+				map[string]interface{}{
+					"name":     "http",
+					"port":     int64(8080),
+					"protocol": "TCP",
+				},
+			},
+		},
+	}
+	et.OnAdd(u)
+
+	want := []proto.Message{
+		&v2.ClusterLoadAssignment{
+			ClusterName: "default/simple/http",
+			Endpoints: []*envoy_api_v2_endpoint.LocalityLbEndpoints{{
+				Locality: &envoy_api_v2_core.Locality{
+					Zone: "us-east-1a",
+				},
+				LbEndpoints: []*envoy_api_v2_endpoint.LbEndpoint{
+					envoy.LBEndpoint(envoy.SocketAddress("10.0.0.1", 8080)),
+				},
+				LoadBalancingWeight: protobuf.UInt32(1),
+			}},
+		},
+	}
+	assert.Equal(t, want, et.Contents())
+
+	et.OnDelete(u)
+	assert.Equal(t, []proto.Message(nil), et.Contents())
+}
+
+func TestEndpointSliceTranslatorSlowStart(t *testing.T) {
+	now := time.Unix(1000, 0)
+	et := &EndpointSliceTranslator{
+		FieldLogger: testLogger(t),
+		slowStart: slowStart{
+			now: func() time.Time { return now },
+		},
+	}
+	// tick stands in for the ramp up timer.
+	tick := func() {
+		if et.slowStart.timer == nil {
+			t.Fatal("expected the ramp up to be scheduled")
+		}
+		et.slowStart.timer.Stop()
+		et.rampUp()
+	}
+	weighted := func(ip string, weight uint32) *envoy_api_v2_endpoint.LbEndpoint {
+		lbendpoint := envoy.LBEndpoint(envoy.SocketAddress(ip, 8080))
+		lbendpoint.LoadBalancingWeight = protobuf.UInt32(weight)
+		return lbendpoint
+	}
+
+	et.OnAdd(&v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "simple",
+			Namespace: "default",
+			Annotations: map[string]string{
+				"projectcontour.io/slow-start-window": "100s",
+			},
+		},
+	})
+
+	// the endpoints of newly seen slices are not ramped up.
+	s1 := endpointSlice("default", "simple-abc", "simple",
+		slicePorts(slicePort("", 8080)),
+		sliceEndpoint("10.0.0.1"),
+	)
+	et.OnAdd(s1)
+	want := []proto.Message{
+		envoy.ClusterLoadAssignment("default/simple", envoy.SocketAddress("10.0.0.1", 8080)),
+	}
+	assert.Equal(t, want, et.Contents())
+
+	// a newly ready endpoint starts at the minimum weight.
+	s2 := endpointSlice("default", "simple-abc", "simple",
+		slicePorts(slicePort("", 8080)),
+		sliceEndpoint("10.0.0.1"),
+		sliceEndpoint("10.0.0.2"),
+	)
+	et.OnUpdate(s1, s2)
+	want = []proto.Message{
+		&v2.ClusterLoadAssignment{
+			ClusterName: "default/simple",
+			Endpoints: []*envoy_api_v2_endpoint.LocalityLbEndpoints{{
+				LbEndpoints: []*envoy_api_v2_endpoint.LbEndpoint{
+					weighted("10.0.0.1", 100),
+					weighted("10.0.0.2", 1),
+				},
+				LoadBalancingWeight: protobuf.UInt32(101),
+			}},
+		},
+	}
+	assert.Equal(t, want, et.Contents())
+
+	// half way through the window it has half the weight.
+	now = now.Add(50 * time.Second)
+	tick()
+	want = []proto.Message{
+		&v2.ClusterLoadAssignment{
+			ClusterName: "default/simple",
+			Endpoints: []*envoy_api_v2_endpoint.LocalityLbEndpoints{{
+				LbEndpoints: []*envoy_api_v2_endpoint.LbEndpoint{
+					weighted("10.0.0.1", 100),
+					weighted("10.0.0.2", 50),
+				},
+				LoadBalancingWeight: protobuf.UInt32(150),
+			}},
+		},
+	}
+	assert.Equal(t, want, et.Contents())
+
+	// at the end of the window the weights are removed
+	// and the ramp up is finished.
+	now = now.Add(50 * time.Second)
+	tick()
+	want = []proto.Message{
+		envoy.ClusterLoadAssignment("default/simple",
+			envoy.SocketAddress("10.0.0.1", 8080),
+			envoy.SocketAddress("10.0.0.2", 8080),
+		),
+	}
+	assert.Equal(t, want, et.Contents())
+	if et.slowStart.timer != nil {
+		t.Fatal("expected the ramp up to be finished")
+	}
+}
+
+func endpointSlice(ns, name, service string, ports []k8s.EndpointPort, endpoints ...k8s.Endpoint) *k8s.EndpointSlice {
+	slice := &k8s.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ns,
+		},
+		AddressType: "IPv4",
+		Endpoints:   endpoints,
+		Ports:       ports,
+	}
+	if service != "" {
+		slice.Labels = map[string]string{
+			k8s.ServiceNameLabel: service,
+		}
+	}
+	return slice
+}
+
+func slicePorts(ports ...k8s.EndpointPort) []k8s.EndpointPort {
+	return ports
+}
+
+func slicePort(name string, port int32) k8s.EndpointPort {
+	protocol := v1.ProtocolTCP
+	return k8s.EndpointPort{
+		Name:     &name,
+		Port:     &port,
+		Protocol: &protocol,
+	}
+}
+
+func sliceEndpoint(address string) k8s.Endpoint {
+	return k8s.Endpoint{
+		Addresses: []string{address},
+	}
+}
+
+func withConditions(ep k8s.Endpoint, ready, serving, terminating bool) k8s.Endpoint {
+	ep.Conditions = k8s.EndpointConditions{
+		Ready:       &ready,
+		Serving:     &serving,
+		Terminating: &terminating,
+	}
+	return ep
+}
+
+func draining(lbendpoint *envoy_api_v2_endpoint.LbEndpoint) *envoy_api_v2_endpoint.LbEndpoint {
+	lbendpoint.HealthStatus = envoy_api_v2_core.HealthStatus_DRAINING
+	return lbendpoint
+}
@@ -17,12 +17,11 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
-	envoy_api_v2_endpoint "github.com/envoyproxy/go-control-plane/envoy/api/v2/endpoint"
 	"github.com/envoyproxy/go-control-plane/pkg/cache"
 	"github.com/golang/protobuf/proto"
-	"github.com/projectcontour/contour/internal/envoy"
 	"github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -33,13 +32,38 @@ import (
 // ClusterLoadAssignment objects.
 type EndpointsTranslator struct {
 	logrus.FieldLogger
+
+	// Versions issues the version of each change to the EDS
+	// cache. Sharing it with the CacheHandler places every xDS
+	// resource type on one sequence. If nil, the EDS cache
+	// counts its own versions.
+	Versions *Versions
+
 	clusterLoadAssignmentCache
+
+	// mu protects endpoints, localities and slow start state,
+	// which are updated by the Endpoints, Node and Service
+	// informers.
+	mu sync.Mutex
+
+	// endpoints holds the Endpoints last seen for each Service,
+	// so that their assignments can be recomputed when the
+	// locality of a Node changes.
+	endpoints map[string]*v1.Endpoints
+
+	localities nodeLocalities
+
+	slowStart slowStart
 }
 
 func (e *EndpointsTranslator) OnAdd(obj interface{}) {
 	switch obj := obj.(type) {
 	case *v1.Endpoints:
 		e.addEndpoints(obj)
+	case *v1.Node:
+		e.updateNode(obj)
+	case *v1.Service:
+		e.updateService(obj)
 	default:
 		e.Errorf("OnAdd unexpected type %T: %#v", obj, obj)
 	}
@@ -54,6 +78,10 @@ func (e *EndpointsTranslator) OnUpdate(oldObj, newObj interface{}) {
 			return
 		}
 		e.updateEndpoints(oldObj, newObj)
+	case *v1.Node:
+		e.updateNode(newObj)
+	case *v1.Service:
+		e.updateService(newObj)
 	default:
 		e.Errorf("OnUpdate unexpected type %T: %#v", newObj, newObj)
 	}
@@ -63,6 +91,10 @@ func (e *EndpointsTranslator) OnDelete(obj interface{}) {
 	switch obj := obj.(type) {
 	case *v1.Endpoints:
 		e.removeEndpoints(obj)
+	case *v1.Node:
+		e.removeNode(obj)
+	case *v1.Service:
+		e.setSlowStartWindow(obj.ObjectMeta, 0)
 	case k8scache.DeletedFinalStateUnknown:
 		e.OnDelete(obj.Obj) // recurse into ourselves with the tombstoned value
 	default:
@@ -70,39 +102,6 @@ func (e *EndpointsTranslator) OnDelete(obj interface{}) {
 	}
 }
 
-func (e *EndpointsTranslator) Contents() []proto.Message {
-	values := e.clusterLoadAssignmentCache.Contents()
-	sort.Stable(clusterLoadAssignmentsByName(values))
-	return values
-}
-
-func (e *EndpointsTranslator) Query(names []string) []proto.Message {
-	e.clusterLoadAssignmentCache.mu.Lock()
-	defer e.clusterLoadAssignmentCache.mu.Unlock()
-	var values []proto.Message
-	for _, n := range names {
-		v, ok := e.entries[n]
-		if !ok {
-			v = &v2.ClusterLoadAssignment{
-				ClusterName: n,
-			}
-		}
-		values = append(values, v)
-	}
-	sort.Stable(clusterLoadAssignmentsByName(values))
-	return values
-}
-
-type clusterLoadAssignmentsByName []proto.Message
-
-func (c clusterLoadAssignmentsByName) Len() int      { return len(c) }
-func (c clusterLoadAssignmentsByName) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
-func (c clusterLoadAssignmentsByName) Less(i, j int) bool {
-	return c[i].(*v2.ClusterLoadAssignment).ClusterName < c[j].(*v2.ClusterLoadAssignment).ClusterName
-}
-
-func (*EndpointsTranslator) TypeURL() string { return cache.EndpointType }
-
 func (e *EndpointsTranslator) addEndpoints(ep *v1.Endpoints) {
 	e.recomputeClusterLoadAssignment(nil, ep)
 }
@@ -121,6 +120,92 @@ func (e *EndpointsTranslator) removeEndpoints(ep *v1.Endpoints) {
 	e.recomputeClusterLoadAssignment(ep, nil)
 }
 
+// updateNode records the locality of node and, if it has changed,
+// recomputes the assignments of the endpoints placed on node.
+func (e *EndpointsTranslator) updateNode(node *v1.Node) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.localities.update(node) {
+		e.recomputeNode(node.Name)
+	}
+}
+
+// removeNode forgets the locality of node and recomputes the
+// assignments of the endpoints placed on node.
+func (e *EndpointsTranslator) removeNode(node *v1.Node) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.localities.remove(node) {
+		e.recomputeNode(node.Name)
+	}
+}
+
+// updateService records the slow start window of svc.
+func (e *EndpointsTranslator) updateService(svc *v1.Service) {
+	window, err := slowStartWindow(svc)
+	if err != nil {
+		e.WithError(err).Errorf("invalid slow start window on Service %s/%s", svc.Namespace, svc.Name)
+	}
+	e.setSlowStartWindow(svc.ObjectMeta, window)
+}
+
+// setSlowStartWindow records the slow start window of the Service
+// and, if it has changed, recomputes the assignments of its Endpoints.
+func (e *EndpointsTranslator) setSlowStartWindow(meta metav1.ObjectMeta, window time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	service := servicename(meta, "")
+	if !e.slowStart.setWindow(service, window) {
+		return
+	}
+	ep, ok := e.endpoints[service]
+	if !ok {
+		return
+	}
+	add := e.clusterLoadAssignments(ep)
+	if len(add) == 0 {
+		return
+	}
+	e.update(e.Versions, add, nil)
+	e.slowStart.schedule(service, e.rampUp)
+}
+
+// rampUp recomputes the assignments of the Services whose addresses
+// are ramping up, until each has finished its slow start.
+func (e *EndpointsTranslator) rampUp() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var add []*v2.ClusterLoadAssignment
+	for _, service := range e.slowStart.due(e.rampUp) {
+		if ep, ok := e.endpoints[service]; ok {
+			add = append(add, e.clusterLoadAssignments(ep)...)
+		}
+	}
+	if len(add) == 0 {
+		return
+	}
+	e.update(e.Versions, add, nil)
+}
+
+// recomputeNode recomputes the assignments of every Endpoints
+// object with an address on the named node.
+func (e *EndpointsTranslator) recomputeNode(name string) {
+	var add []*v2.ClusterLoadAssignment
+	for _, ep := range e.endpoints {
+		if onNode(ep, name) {
+			add = append(add, e.clusterLoadAssignments(ep)...)
+		}
+	}
+	if len(add) == 0 {
+		return
+	}
+	e.update(e.Versions, add, nil)
+}
+
 // recomputeClusterLoadAssignment recomputes the EDS cache taking into account old and new endpoints.
 func (e *EndpointsTranslator) recomputeClusterLoadAssignment(oldep, newep *v1.Endpoints) {
 	// skip computation if either old and new services or endpoints are equal (thus also handling nil)
@@ -128,21 +213,78 @@ func (e *EndpointsTranslator) recomputeClusterLoadAssignment(oldep, newep *v1.En
 		return
 	}
 
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// the addresses of Endpoints seen for the first time, for
+	// example when Contour starts, are not slow started.
+	warm := oldep == nil
 	if oldep == nil {
 		oldep = &v1.Endpoints{
 			ObjectMeta: newep.ObjectMeta,
 		}
 	}
 
+	var service string
 	if newep == nil {
 		newep = &v1.Endpoints{
 			ObjectMeta: oldep.ObjectMeta,
 		}
+		service = servicename(oldep.ObjectMeta, "")
+		delete(e.endpoints, service)
+		e.slowStart.forget(service)
+	} else {
+		if e.endpoints == nil {
+			e.endpoints = make(map[string]*v1.Endpoints)
+		}
+		service = servicename(newep.ObjectMeta, "")
+		e.endpoints[service] = newep
+		e.slowStart.observe(service, readyIPs(newep), warm)
 	}
 
+	var remove []string
+
+	add := e.clusterLoadAssignments(newep)
 	seen := make(map[string]bool)
-	// add or update endpoints
-	for _, s := range newep.Subsets {
+	for _, cla := range add {
+		seen[cla.ClusterName] = true
+	}
+
+	// iterate over the ports in the old spec, remove any were not seen.
+	for _, s := range oldep.Subsets {
+		if len(s.Addresses) == 0 {
+			continue
+		}
+		for _, p := range s.Ports {
+			name := servicename(oldep.ObjectMeta, p.Name)
+			if _, ok := seen[name]; !ok {
+				// port is no longer present, remove it.
+				remove = append(remove, name)
+			}
+		}
+	}
+
+	if len(add) == 0 && len(remove) == 0 {
+		return
+	}
+	e.update(e.Versions, add, remove)
+	e.slowStart.schedule(service, e.rampUp)
+}
+
+// clusterLoadAssignments returns a ClusterLoadAssignment for each TCP
+// port of ep with ready addresses. Not ready addresses are not sent;
+// Endpoints do not say whether a not ready address is starting or
+// terminating, so it cannot be given a health status. If the locality
+// of any address is known, the addresses are grouped by locality.
+// While any address of ep is ramping up, every address is weighted by
+// its progress through the slow start window.
+func (e *EndpointsTranslator) clusterLoadAssignments(ep *v1.Endpoints) []*v2.ClusterLoadAssignment {
+	service := servicename(ep.ObjectMeta, "")
+	now := e.slowStart.time()
+	ramping := e.slowStart.ramping(service, now)
+
+	var clas []*v2.ClusterLoadAssignment
+	for _, s := range ep.Subsets {
 		if len(s.Addresses) < 1 {
 			// skip subset without ready addresses.
 			continue
@@ -153,40 +295,51 @@ func (e *EndpointsTranslator) recomputeClusterLoadAssignment(oldep, newep *v1.En
 				continue
 			}
 
-			addresses := append([]v1.EndpointAddress{}, s.Addresses...) // shallow copy
-			sort.Slice(addresses, func(i, j int) bool { return addresses[i].IP < addresses[j].IP })
-
-			lbendpoints := make([]*envoy_api_v2_endpoint.LbEndpoint, 0, len(addresses))
-			for _, a := range addresses {
-				addr := envoy.SocketAddress(a.IP, int(p.Port))
-				lbendpoints = append(lbendpoints, envoy.LBEndpoint(addr))
+			addresses := make([]localityAddress, 0, len(s.Addresses))
+			for _, a := range s.Addresses {
+				addresses = append(addresses, localityAddress{
+					ip:       a.IP,
+					port:     int(p.Port),
+					locality: e.localities.lookup(a.NodeName),
+				})
 			}
-
-			cla := &v2.ClusterLoadAssignment{
-				ClusterName: servicename(newep.ObjectMeta, p.Name),
-				Endpoints: []*envoy_api_v2_endpoint.LocalityLbEndpoints{{
-					LbEndpoints: lbendpoints,
-				}},
+			sort.Slice(addresses, func(i, j int) bool { return addresses[i].ip < addresses[j].ip })
+			if ramping {
+				for i := range addresses {
+					addresses[i].weight = e.slowStart.weight(service, addresses[i].ip, now)
+				}
 			}
-			seen[cla.ClusterName] = true
-			e.Add(cla)
+
+			clas = append(clas, &v2.ClusterLoadAssignment{
+				ClusterName: servicename(ep.ObjectMeta, p.Name),
+				Endpoints:   localityLbEndpoints(addresses),
+			})
 		}
 	}
+	return clas
+}
 
-	// iterate over the ports in the old spec, remove any were not seen.
-	for _, s := range oldep.Subsets {
-		if len(s.Addresses) == 0 {
-			continue
+// readyIPs returns the ready addresses of ep.
+func readyIPs(ep *v1.Endpoints) []string {
+	var ips []string
+	for _, s := range ep.Subsets {
+		for _, a := range s.Addresses {
+			ips = append(ips, a.IP)
 		}
-		for _, p := range s.Ports {
-			name := servicename(oldep.ObjectMeta, p.Name)
-			if _, ok := seen[name]; !ok {
-				// port is no longer present, remove it.
-				e.Remove(name)
+	}
+	return ips
+}
+
+// onNode returns true if any address of ep is placed on the named node.
+func onNode(ep *v1.Endpoints, name string) bool {
+	for _, s := range ep.Subsets {
+		for _, a := range s.Addresses {
+			if a.NodeName != nil && *a.NodeName == name {
+				return true
 			}
 		}
 	}
-
+	return false
 }
 
 type clusterLoadAssignmentCache struct {
@@ -195,38 +348,91 @@ type clusterLoadAssignmentCache struct {
 	Cond
 }
 
-// Add adds an entry to the cache. If a ClusterLoadAssignment with the same
-// name exists, it is replaced.
-func (c *clusterLoadAssignmentCache) Add(a *v2.ClusterLoadAssignment) {
+// update adds each entry in add to the cache, replacing any entry
+// with the same name, and removes each entry named in remove. Waiters
+// interested in those entries are notified of the next version issued
+// by versions, or, if versions is nil, of the cache's next version.
+func (c *clusterLoadAssignmentCache) update(versions *Versions, add []*v2.ClusterLoadAssignment, remove []string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	if c.entries == nil {
 		c.entries = make(map[string]*v2.ClusterLoadAssignment)
 	}
-	c.entries[a.ClusterName] = a
-	c.Notify(a.ClusterName)
+	var names []string
+	for _, a := range add {
+		c.entries[a.ClusterName] = a
+		names = append(names, a.ClusterName)
+	}
+	for _, name := range remove {
+		delete(c.entries, name)
+		names = append(names, name)
+	}
+	if versions == nil {
+		c.Notify(names...)
+		return
+	}
+	// the version is issued while c is locked, so the versions
+	// notified by c are increasing.
+	c.notifyVersion(versions.Next(), names...)
 }
 
-// Remove removes the named entry from the cache. If the entry
-// is not present in the cache, the operation is a no-op.
-func (c *clusterLoadAssignmentCache) Remove(name string) {
+func (c *clusterLoadAssignmentCache) Contents() []proto.Message {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	delete(c.entries, name)
-	c.Notify(name)
+	return c.contents()
 }
 
-// Contents returns a copy of the contents of the cache.
-func (c *clusterLoadAssignmentCache) Contents() []proto.Message {
+func (c *clusterLoadAssignmentCache) Query(names []string) []proto.Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.query(names)
+}
+
+// Snapshot returns the version of the EDS cache and its contents, or the
+// entries matching names, at that version.
+func (c *clusterLoadAssignmentCache) Snapshot(names []string) (int, []proto.Message) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	if len(names) == 0 {
+		return c.version(), c.contents()
+	}
+	return c.version(), c.query(names)
+}
+
+func (*clusterLoadAssignmentCache) TypeURL() string { return cache.EndpointType }
+
+func (c *clusterLoadAssignmentCache) contents() []proto.Message {
 	var values []proto.Message
 	for _, v := range c.entries {
 		values = append(values, v)
 	}
+	sort.Stable(clusterLoadAssignmentsByName(values))
 	return values
 }
 
+func (c *clusterLoadAssignmentCache) query(names []string) []proto.Message {
+	var values []proto.Message
+	for _, n := range names {
+		v, ok := c.entries[n]
+		if !ok {
+			v = &v2.ClusterLoadAssignment{
+				ClusterName: n,
+			}
+		}
+		values = append(values, v)
+	}
+	sort.Stable(clusterLoadAssignmentsByName(values))
+	return values
+}
+
+type clusterLoadAssignmentsByName []proto.Message
+
+func (c clusterLoadAssignmentsByName) Len() int      { return len(c) }
+func (c clusterLoadAssignmentsByName) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+func (c clusterLoadAssignmentsByName) Less(i, j int) bool {
+	return c[i].(*v2.ClusterLoadAssignment).ClusterName < c[j].(*v2.ClusterLoadAssignment).ClusterName
+}
+
 // servicename returns the name of the cluster this meta and port
 // refers to. The CDS name of the cluster may include additional suffixes
 // but these are not known to EDS.
@@ -15,12 +15,17 @@ package contour
 
 import (
 	"testing"
+	"time"
 
 	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	envoy_api_v2_endpoint "github.com/envoyproxy/go-control-plane/envoy/api/v2/endpoint"
 	"github.com/golang/protobuf/proto"
 	"github.com/projectcontour/contour/internal/assert"
 	"github.com/projectcontour/contour/internal/envoy"
+	"github.com/projectcontour/contour/internal/protobuf"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestEndpointsTranslatorContents(t *testing.T) {
@@ -431,6 +436,248 @@ func TestEndpointsTranslatorScaleToZeroEndpoints(t *testing.T) {
 	assert.Equal(t, want, got)
 }
 
+func TestEndpointsTranslatorNodeLocality(t *testing.T) {
+	et := &EndpointsTranslator{
+		FieldLogger: testLogger(t),
+	}
+
+	et.OnAdd(node("node-a", map[string]string{
+		"topology.kubernetes.io/region": "us-east-1",
+		"topology.kubernetes.io/zone":   "us-east-1a",
+	}))
+	et.OnAdd(node("node-b", map[string]string{
+		"failure-domain.beta.kubernetes.io/region": "us-east-1",
+		"failure-domain.beta.kubernetes.io/zone":   "us-east-1b",
+	}))
+
+	e1 := endpoints("default", "simple", v1.EndpointSubset{
+		Addresses: []v1.EndpointAddress{
+			address("10.0.0.3", "node-b"),
+			address("10.0.0.2", "node-a"),
+			address("10.0.0.1", "node-a"),
+			address("10.0.0.4", "node-c"),
+		},
+		Ports: ports(
+			port("", 8080),
+		),
+	})
+	et.OnAdd(e1)
+
+	// addresses are grouped by locality, those on node-c,
+	// which has no locality, first.
+	want := []proto.Message{
+		&v2.ClusterLoadAssignment{
+			ClusterName: "default/simple",
+			Endpoints: []*envoy_api_v2_endpoint.LocalityLbEndpoints{{
+				LbEndpoints: []*envoy_api_v2_endpoint.LbEndpoint{
+					envoy.LBEndpoint(envoy.SocketAddress("10.0.0.4", 8080)),
+				},
+				LoadBalancingWeight: protobuf.UInt32(1),
+			}, {
+				Locality: &envoy_api_v2_core.Locality{
+					Region: "us-east-1",
+					Zone:   "us-east-1a",
+				},
+				LbEndpoints: []*envoy_api_v2_endpoint.LbEndpoint{
+					envoy.LBEndpoint(envoy.SocketAddress("10.0.0.1", 8080)),
+					envoy.LBEndpoint(envoy.SocketAddress("10.0.0.2", 8080)),
+				},
+				LoadBalancingWeight: protobuf.UInt32(2),
+			}, {
+				Locality: &envoy_api_v2_core.Locality{
+					Region: "us-east-1",
+					Zone:   "us-east-1b",
+				},
+				LbEndpoints: []*envoy_api_v2_endpoint.LbEndpoint{
+					envoy.LBEndpoint(envoy.SocketAddress("10.0.0.3", 8080)),
+				},
+				LoadBalancingWeight: protobuf.UInt32(1),
+			}},
+		},
+	}
+	assert.Equal(t, want, et.Contents())
+
+	// moving node-b into node-a's zone recomputes the assignment.
+	et.OnUpdate(
+		node("node-b", nil),
+		node("node-b", map[string]string{
+			"topology.kubernetes.io/region": "us-east-1",
+			"topology.kubernetes.io/zone":   "us-east-1a",
+		}),
+	)
+	want = []proto.Message{
+		&v2.ClusterLoadAssignment{
+			ClusterName: "default/simple",
+			Endpoints: []*envoy_api_v2_endpoint.LocalityLbEndpoints{{
+				LbEndpoints: []*envoy_api_v2_endpoint.LbEndpoint{
+					envoy.LBEndpoint(envoy.SocketAddress("10.0.0.4", 8080)),
+				},
+				LoadBalancingWeight: protobuf.UInt32(1),
+			}, {
+				Locality: &envoy_api_v2_core.Locality{
+					Region: "us-east-1",
+					Zone:   "us-east-1a",
+				},
+				LbEndpoints: []*envoy_api_v2_endpoint.LbEndpoint{
+					envoy.LBEndpoint(envoy.SocketAddress("10.0.0.1", 8080)),
+					envoy.LBEndpoint(envoy.SocketAddress("10.0.0.2", 8080)),
+					envoy.LBEndpoint(envoy.SocketAddress("10.0.0.3", 8080)),
+				},
+				LoadBalancingWeight: protobuf.UInt32(3),
+			}},
+		},
+	}
+	assert.Equal(t, want, et.Contents())
+
+	// once no address has a known locality, the assignment
+	// reverts to a single group. It is still weighted, else
+	// a cluster using the LocalityWeighted policy would send
+	// it no traffic.
+	et.OnDelete(node("node-a", nil))
+	et.OnDelete(node("node-b", nil))
+	want = []proto.Message{
+		&v2.ClusterLoadAssignment{
+			ClusterName: "default/simple",
+			Endpoints: []*envoy_api_v2_endpoint.LocalityLbEndpoints{{
+				LbEndpoints: []*envoy_api_v2_endpoint.LbEndpoint{
+					envoy.LBEndpoint(envoy.SocketAddress("10.0.0.1", 8080)),
+					envoy.LBEndpoint(envoy.SocketAddress("10.0.0.2", 8080)),
+					envoy.LBEndpoint(envoy.SocketAddress("10.0.0.3", 8080)),
+					envoy.LBEndpoint(envoy.SocketAddress("10.0.0.4", 8080)),
+				},
+				LoadBalancingWeight: protobuf.UInt32(4),
+			}},
+		},
+	}
+	assert.Equal(t, want, et.Contents())
+}
+
+func TestEndpointsTranslatorSlowStart(t *testing.T) {
+	now := time.Unix(1000, 0)
+	et := &EndpointsTranslator{
+		FieldLogger: testLogger(t),
+		slowStart: slowStart{
+			now: func() time.Time { return now },
+		},
+	}
+	// tick stands in for the ramp up timer.
+	tick := func() {
+		if et.slowStart.timer == nil {
+			t.Fatal("expected the ramp up to be scheduled")
+		}
+		et.slowStart.timer.Stop()
+		et.rampUp()
+	}
+	weighted := func(ip string, weight uint32) *envoy_api_v2_endpoint.LbEndpoint {
+		lbendpoint := envoy.LBEndpoint(envoy.SocketAddress(ip, 8080))
+		lbendpoint.LoadBalancingWeight = protobuf.UInt32(weight)
+		return lbendpoint
+	}
+
+	et.OnAdd(&v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "simple",
+			Namespace: "default",
+			Annotations: map[string]string{
+				"projectcontour.io/slow-start-window": "100s",
+			},
+		},
+	})
+
+	// the addresses of newly seen endpoints are not ramped up.
+	e1 := endpoints("default", "simple", v1.EndpointSubset{
+		Addresses: addresses("10.0.0.1"),
+		Ports: ports(
+			port("", 8080),
+		),
+	})
+	et.OnAdd(e1)
+	want := []proto.Message{
+		envoy.ClusterLoadAssignment("default/simple", envoy.SocketAddress("10.0.0.1", 8080)),
+	}
+	assert.Equal(t, want, et.Contents())
+
+	// a newly ready address starts at the minimum weight.
+	e2 := endpoints("default", "simple", v1.EndpointSubset{
+		Addresses: addresses("10.0.0.1", "10.0.0.2"),
+		Ports: ports(
+			port("", 8080),
+		),
+	})
+	et.OnUpdate(e1, e2)
+	want = []proto.Message{
+		&v2.ClusterLoadAssignment{
+			ClusterName: "default/simple",
+			Endpoints: []*envoy_api_v2_endpoint.LocalityLbEndpoints{{
+				LbEndpoints: []*envoy_api_v2_endpoint.LbEndpoint{
+					weighted("10.0.0.1", 100),
+					weighted("10.0.0.2", 1),
+				},
+				LoadBalancingWeight: protobuf.UInt32(101),
+			}},
+		},
+	}
+	assert.Equal(t, want, et.Contents())
+
+	// half way through the window it has half the weight.
+	now = now.Add(50 * time.Second)
+	tick()
+	want = []proto.Message{
+		&v2.ClusterLoadAssignment{
+			ClusterName: "default/simple",
+			Endpoints: []*envoy_api_v2_endpoint.LocalityLbEndpoints{{
+				LbEndpoints: []*envoy_api_v2_endpoint.LbEndpoint{
+					weighted("10.0.0.1", 100),
+					weighted("10.0.0.2", 50),
+				},
+				LoadBalancingWeight: protobuf.UInt32(150),
+			}},
+		},
+	}
+	assert.Equal(t, want, et.Contents())
+
+	// at the end of the window the weights are removed
+	// and the ramp up is finished.
+	now = now.Add(50 * time.Second)
+	tick()
+	want = []proto.Message{
+		envoy.ClusterLoadAssignment("default/simple",
+			envoy.SocketAddress("10.0.0.1", 8080),
+			envoy.SocketAddress("10.0.0.2", 8080),
+		),
+	}
+	assert.Equal(t, want, et.Contents())
+	if et.slowStart.timer != nil {
+		t.Fatal("expected the ramp up to be finished")
+	}
+
+	// without a slow start window, new addresses are not ramped up.
+	et.OnDelete(&v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "simple",
+			Namespace: "default",
+		},
+	})
+	e3 := endpoints("default", "simple", v1.EndpointSubset{
+		Addresses: addresses("10.0.0.1", "10.0.0.2", "10.0.0.3"),
+		Ports: ports(
+			port("", 8080),
+		),
+	})
+	et.OnUpdate(e2, e3)
+	want = []proto.Message{
+		envoy.ClusterLoadAssignment("default/simple",
+			envoy.SocketAddress("10.0.0.1", 8080),
+			envoy.SocketAddress("10.0.0.2", 8080),
+			envoy.SocketAddress("10.0.0.3", 8080),
+		),
+	}
+	assert.Equal(t, want, et.Contents())
+	if et.slowStart.timer != nil {
+		t.Fatal("expected no ramp up")
+	}
+}
+
 func ports(eps ...v1.EndpointPort) []v1.EndpointPort {
 	return eps
 }
@@ -450,3 +697,55 @@ func clusterloadassignments(clas ...*v2.ClusterLoadAssignment) map[string]*v2.Cl
 	}
 	return m
 }
+
+func address(ip, nodename string) v1.EndpointAddress {
+	return v1.EndpointAddress{
+		IP:       ip,
+		NodeName: &nodename,
+	}
+}
+
+func node(name string, labels map[string]string) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+	}
+}
+
+func TestSlowStartScheduleShorterInterval(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := slowStart{
+		now: func() time.Time { return now },
+	}
+	f := func() {}
+	ramp := func(service string, window time.Duration) {
+		s.setWindow(service, window)
+		s.observe(service, []string{"10.0.0.1"}, true)
+		s.observe(service, []string{"10.0.0.1", "10.0.0.2"}, false)
+		s.schedule(service, f)
+	}
+	defer func() {
+		if s.timer != nil {
+			s.timer.Stop()
+		}
+	}()
+
+	// a ten minute window recomputes its weights every minute.
+	ramp("default/slow", 10*time.Minute)
+	assert.Equal(t, now.Add(time.Minute), s.deadline)
+
+	// a Service with a shorter interval moves the timer earlier.
+	ramp("default/fast", 10*time.Second)
+	assert.Equal(t, now.Add(time.Second), s.deadline)
+
+	// but one with a longer interval does not move it later.
+	ramp("default/slower", 20*time.Minute)
+	assert.Equal(t, now.Add(time.Second), s.deadline)
+	assert.Equal(t, map[string]bool{
+		"default/slow":   true,
+		"default/fast":   true,
+		"default/slower": true,
+	}, s.pending)
+}
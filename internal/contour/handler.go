@@ -17,17 +17,23 @@
 package contour
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	ingressroutev1 "github.com/projectcontour/contour/apis/contour/v1beta1"
+	gatewayapi "github.com/projectcontour/contour/apis/networking/v1alpha1"
 	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
 	"github.com/projectcontour/contour/internal/dag"
 	"github.com/projectcontour/contour/internal/k8s"
 	"github.com/projectcontour/contour/internal/metrics"
 	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 )
 
 // EventHandler implements cache.ResourceEventHandler, filters k8s events towards
@@ -42,6 +48,10 @@ type EventHandler struct {
 
 	StatusClient k8s.StatusClient
 
+	// EventRecorder, if not nil, records a Kubernetes Event for
+	// each Warning found by the dag.KubernetesCache.
+	EventRecorder record.EventRecorder
+
 	*metrics.Metrics
 
 	logrus.FieldLogger
@@ -65,6 +75,10 @@ type EventHandler struct {
 	// seq is the sequence counter of the number of times
 	// an event has been received.
 	seq int
+
+	// recorded holds the key of each Warning for which
+	// a Kubernetes Event has been recorded.
+	recorded map[string]bool
 }
 
 type opAdd struct {
@@ -188,6 +202,9 @@ func (e *EventHandler) onUpdate(op interface{}) bool {
 	case opUpdate:
 		if cmp.Equal(op.oldObj, op.newObj,
 			cmpopts.IgnoreFields(ingressroutev1.IngressRoute{}, "Status"),
+			cmpopts.IgnoreFields(gatewayapi.GatewayClass{}, "Status"),
+			cmpopts.IgnoreFields(gatewayapi.Gateway{}, "Status"),
+			cmpopts.IgnoreFields(gatewayapi.HTTPRoute{}, "Status"),
 			cmpopts.IgnoreFields(metav1.ObjectMeta{}, "ResourceVersion")) {
 			e.WithField("op", "update").Debugf("%T skipping update, only status has changed", op.newObj)
 			return false
@@ -226,6 +243,8 @@ func (e *EventHandler) updateDAG() {
 		// we're the leader, update status and metrics
 		statuses := dag.Statuses()
 		e.setStatus(statuses)
+		e.setGatewayStatus(dag.GatewayStatuses())
+		e.recordEvents()
 
 		metrics, proxymetrics := calculateRouteMetric(statuses)
 		e.Metrics.SetIngressRouteMetric(metrics)
@@ -237,12 +256,55 @@ func (e *EventHandler) updateDAG() {
 	e.last = time.Now()
 }
 
+// recordEvents records a Kubernetes Event for each Warning held
+// by the dag.KubernetesCache which has not already been recorded.
+func (e *EventHandler) recordEvents() {
+	if e.EventRecorder == nil {
+		return
+	}
+
+	recorded := make(map[string]bool)
+	for _, w := range e.Builder.Source.Warnings() {
+		om := w.Object.GetObjectMeta()
+		key := fmt.Sprintf("%s/%s/%s/%s/%s", k8s.KindOf(w.Object), om.GetNamespace(), om.GetName(), om.GetResourceVersion(), w.Message)
+		if obj, ok := w.Object.(runtime.Object); ok && !e.recorded[key] {
+			e.EventRecorder.Event(obj, v1.EventTypeWarning, w.Reason, w.Message)
+		}
+		recorded[key] = true
+	}
+	e.recorded = recorded
+}
+
+// validCondition returns the Valid condition for st.
+func validCondition(st dag.Status) projcontour.DetailedCondition {
+	cond := projcontour.DetailedCondition{
+		Type:     projcontour.ValidCondition,
+		Status:   projcontour.ConditionTrue,
+		Reason:   "Valid",
+		Message:  st.Description,
+		Errors:   st.Errors,
+		Warnings: st.Warnings,
+	}
+	switch st.Status {
+	case dag.StatusInvalid:
+		cond.Status = projcontour.ConditionFalse
+		cond.Reason = "Invalid"
+	case dag.StatusOrphaned:
+		cond.Status = projcontour.ConditionFalse
+		cond.Reason = "Orphaned"
+	}
+	return cond
+}
+
 // setStatus updates the status of objects.
 func (e *EventHandler) setStatus(statuses map[dag.Meta]dag.Status) {
 	for _, st := range statuses {
 		switch obj := st.Object.(type) {
 		case *ingressroutev1.IngressRoute:
-			err := e.StatusClient.SetStatus(st.Status, st.Description, obj)
+			err := e.StatusClient.SetStatus(projcontour.Status{
+				CurrentStatus: st.Status,
+				Description:   st.Description,
+			}, obj)
 			if err != nil {
 				e.WithError(err).
 					WithField("status", st.Status).
@@ -252,7 +314,11 @@ func (e *EventHandler) setStatus(statuses map[dag.Meta]dag.Status) {
 					Error("failed to set status")
 			}
 		case *projcontour.HTTPProxy:
-			err := e.StatusClient.SetStatus(st.Status, st.Description, obj)
+			err := e.StatusClient.SetStatus(projcontour.Status{
+				CurrentStatus: st.Status,
+				Description:   st.Description,
+				Conditions:    []projcontour.DetailedCondition{validCondition(st)},
+			}, obj)
 			if err != nil {
 				e.WithError(err).
 					WithField("status", st.Status).
@@ -268,3 +334,39 @@ func (e *EventHandler) setStatus(statuses map[dag.Meta]dag.Status) {
 		}
 	}
 }
+
+// gatewayCondition returns the condition reported on a
+// Gateway API object for st.
+func gatewayCondition(st dag.Status) gatewayapi.Condition {
+	cond := gatewayapi.Condition{
+		Type:    gatewayapi.AdmittedCondition,
+		Status:  gatewayapi.ConditionTrue,
+		Reason:  "Valid",
+		Message: st.Description,
+	}
+	if _, ok := st.Object.(*gatewayapi.Gateway); ok {
+		cond.Type = gatewayapi.ReadyCondition
+	}
+	if st.Status == dag.StatusInvalid {
+		cond.Status = gatewayapi.ConditionFalse
+		cond.Reason = "Invalid"
+		cond.Message = strings.Join(st.Errors, "; ")
+	}
+	return cond
+}
+
+// setGatewayStatus updates the status of Gateway API objects.
+func (e *EventHandler) setGatewayStatus(statuses []dag.Status) {
+	for _, st := range statuses {
+		cond := gatewayCondition(st)
+		if err := e.StatusClient.SetConditions([]gatewayapi.Condition{cond}, st.Object); err != nil {
+			om := st.Object.GetObjectMeta()
+			e.WithError(err).
+				WithField("kind", k8s.KindOf(st.Object)).
+				WithField("status", cond.Status).
+				WithField("name", om.GetName()).
+				WithField("namespace", om.GetNamespace()).
+				Error("failed to set status")
+		}
+	}
+}
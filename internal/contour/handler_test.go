@@ -0,0 +1,162 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contour
+
+import (
+	"testing"
+
+	gatewayapi "github.com/projectcontour/contour/apis/networking/v1alpha1"
+	"github.com/projectcontour/contour/internal/assert"
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/k8s"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestEventHandlerRecordEvents(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	e := &EventHandler{
+		Builder: dag.Builder{
+			Source: dag.KubernetesCache{
+				FieldLogger: testLogger(t),
+			},
+		},
+		EventRecorder: recorder,
+	}
+
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "kuard",
+			Namespace:       "default",
+			ResourceVersion: "1",
+			Annotations: map[string]string{
+				"projectcontour.io/websocket-routes": "/",
+			},
+		},
+	}
+	e.Builder.Source.Insert(svc)
+
+	events := func() []string {
+		var got []string
+		for {
+			select {
+			case ev := <-recorder.Events:
+				got = append(got, ev)
+			default:
+				return got
+			}
+		}
+	}
+
+	e.recordEvents()
+	assert.Equal(t, []string{
+		`Warning IgnoredAnnotation ignoring invalid or unsupported annotation "projectcontour.io/websocket-routes"`,
+	}, events())
+
+	// the same warning is not recorded twice.
+	e.recordEvents()
+	assert.Equal(t, []string(nil), events())
+
+	// a new revision of the object with the same problem is recorded again.
+	svc = svc.DeepCopy()
+	svc.ResourceVersion = "2"
+	e.Builder.Source.Insert(svc)
+	e.recordEvents()
+	assert.Equal(t, []string{
+		`Warning IgnoredAnnotation ignoring invalid or unsupported annotation "projectcontour.io/websocket-routes"`,
+	}, events())
+}
+
+func TestEventHandlerSetGatewayStatus(t *testing.T) {
+	statuses := &k8s.StatusCacher{}
+	e := &EventHandler{
+		Builder: dag.Builder{
+			Source: dag.KubernetesCache{
+				GatewayController: "projectcontour.io/contour",
+				FieldLogger:       testLogger(t),
+			},
+		},
+		StatusClient: statuses,
+		FieldLogger:  testLogger(t),
+	}
+
+	class := &gatewayapi.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "contour",
+		},
+		Spec: gatewayapi.GatewayClassSpec{
+			Controller: "projectcontour.io/contour",
+		},
+	}
+	gateway := &gatewayapi.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gateway",
+			Namespace: "default",
+		},
+		Spec: gatewayapi.GatewaySpec{
+			GatewayClassName: "contour",
+			Listeners: []gatewayapi.Listener{{
+				Port:     80,
+				Protocol: gatewayapi.HTTPProtocolType,
+			}},
+		},
+	}
+	route := &gatewayapi.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: gatewayapi.HTTPRouteSpec{
+			Rules: []gatewayapi.HTTPRouteRule{{
+				ForwardTo: []gatewayapi.HTTPRouteForwardTo{{
+					ServiceName: "missing",
+					Port:        80,
+				}},
+			}},
+		},
+	}
+	e.Builder.Source.Insert(class)
+	e.Builder.Source.Insert(gateway)
+	e.Builder.Source.Insert(route)
+
+	e.setGatewayStatus(e.Builder.Build().GatewayStatuses())
+
+	conditions := func(obj interface{}) []gatewayapi.Condition {
+		t.Helper()
+		conds, err := statuses.GetConditions(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return conds
+	}
+	assert.Equal(t, []gatewayapi.Condition{{
+		Type:    gatewayapi.AdmittedCondition,
+		Status:  gatewayapi.ConditionTrue,
+		Reason:  "Valid",
+		Message: "valid GatewayClass",
+	}}, conditions(class))
+	assert.Equal(t, []gatewayapi.Condition{{
+		Type:    gatewayapi.ReadyCondition,
+		Status:  gatewayapi.ConditionTrue,
+		Reason:  "Valid",
+		Message: "valid Gateway",
+	}}, conditions(gateway))
+	assert.Equal(t, []gatewayapi.Condition{{
+		Type:    gatewayapi.AdmittedCondition,
+		Status:  gatewayapi.ConditionFalse,
+		Reason:  "Invalid",
+		Message: "Service [missing:80] is invalid or missing",
+	}}, conditions(route))
+}
@@ -0,0 +1,195 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contour
+
+import (
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	"k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// IngressStatusUpdater implements cache.ResourceEventHandler. It watches
+// the Service in front of Envoy and copies its load balancer status into
+// every Ingress owned by Contour, so tools such as ExternalDNS can discover
+// Envoy's address.
+type IngressStatusUpdater struct {
+	Client kubernetes.Interface
+
+	// EnvoyServiceName and EnvoyServiceNamespace identify
+	// the Service in front of Envoy.
+	EnvoyServiceName      string
+	EnvoyServiceNamespace string
+
+	// Contour's IngressClasses.
+	// If not set, defaults to dag.DEFAULT_INGRESS_CLASS.
+	IngressClasses []string
+
+	// IsLeader will become ready to read when this IngressStatusUpdater
+	// becomes the leader. If IsLeader is not readable, or nil, status
+	// updates will be suppressed.
+	IsLeader chan struct{}
+
+	logrus.FieldLogger
+
+	update chan interface{}
+
+	// leader is true once IsLeader has become readable.
+	leader bool
+
+	// lb is the Envoy Service's current load balancer status.
+	lb v1.LoadBalancerStatus
+
+	// ingresses holds the latest version of each Ingress owned by Contour.
+	ingresses map[types.NamespacedName]interface{}
+}
+
+func (isu *IngressStatusUpdater) OnAdd(obj interface{}) {
+	isu.update <- opAdd{obj: obj}
+}
+
+func (isu *IngressStatusUpdater) OnUpdate(oldObj, newObj interface{}) {
+	isu.update <- opUpdate{oldObj: oldObj, newObj: newObj}
+}
+
+func (isu *IngressStatusUpdater) OnDelete(obj interface{}) {
+	isu.update <- opDelete{obj: obj}
+}
+
+// Start initializes the IngressStatusUpdater and returns a function
+// suitable for registration with a workgroup.Group.
+func (isu *IngressStatusUpdater) Start() func(<-chan struct{}) error {
+	isu.update = make(chan interface{})
+	return isu.run
+}
+
+func (isu *IngressStatusUpdater) run(stop <-chan struct{}) error {
+	isu.WithField("service", isu.EnvoyServiceNamespace+"/"+isu.EnvoyServiceName).Info("started")
+	defer isu.Info("stopped")
+
+	leader := isu.IsLeader
+	for {
+		select {
+		case op := <-isu.update:
+			isu.handle(op)
+		case <-leader:
+			// disable this case
+			leader = nil
+			isu.leader = true
+			isu.Info("elected as leader, updating ingress status")
+			isu.updateAll()
+		case <-stop:
+			return nil
+		}
+	}
+}
+
+// handle applies op, writing the status of any Ingress that is out of date.
+func (isu *IngressStatusUpdater) handle(op interface{}) {
+	switch op := op.(type) {
+	case opAdd:
+		isu.onChange(op.obj, false)
+	case opUpdate:
+		isu.onChange(op.newObj, false)
+	case opDelete:
+		if d, ok := op.obj.(cache.DeletedFinalStateUnknown); ok {
+			isu.onChange(d.Obj, true)
+			return
+		}
+		isu.onChange(op.obj, true)
+	}
+}
+
+func (isu *IngressStatusUpdater) onChange(obj interface{}, deleted bool) {
+	switch obj := obj.(type) {
+	case *v1.Service:
+		if obj.Name != isu.EnvoyServiceName || obj.Namespace != isu.EnvoyServiceNamespace {
+			return
+		}
+		lb := obj.Status.LoadBalancer
+		if deleted {
+			lb = v1.LoadBalancerStatus{}
+		}
+		if equality.Semantic.DeepEqual(lb, isu.lb) {
+			return
+		}
+		isu.lb = lb
+		isu.updateAll()
+	case *v1beta1.Ingress, *extensionsv1beta1.Ingress:
+		o := obj.(dag.Object).GetObjectMeta()
+		key := types.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}
+		if deleted || !dag.MatchesIngressClass(obj.(dag.Object), isu.IngressClasses...) {
+			delete(isu.ingresses, key)
+			return
+		}
+		if isu.ingresses == nil {
+			isu.ingresses = make(map[types.NamespacedName]interface{})
+		}
+		isu.ingresses[key] = obj
+		isu.updateStatus(obj)
+	}
+}
+
+// updateAll writes the current load balancer status to every owned Ingress.
+func (isu *IngressStatusUpdater) updateAll() {
+	for _, obj := range isu.ingresses {
+		isu.updateStatus(obj)
+	}
+}
+
+// updateStatus writes the current load balancer status to obj if
+// this IngressStatusUpdater is the leader and obj is out of date.
+// The updated Ingress replaces obj so it is not written again.
+func (isu *IngressStatusUpdater) updateStatus(obj interface{}) {
+	if !isu.leader {
+		return
+	}
+
+	var err error
+	switch obj := obj.(type) {
+	case *v1beta1.Ingress:
+		if equality.Semantic.DeepEqual(obj.Status.LoadBalancer, isu.lb) {
+			return
+		}
+		updated := obj.DeepCopy()
+		updated.Status.LoadBalancer = *isu.lb.DeepCopy()
+		updated, err = isu.Client.NetworkingV1beta1().Ingresses(obj.Namespace).UpdateStatus(updated)
+		if err == nil {
+			isu.ingresses[types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}] = updated
+		}
+	case *extensionsv1beta1.Ingress:
+		if equality.Semantic.DeepEqual(obj.Status.LoadBalancer, isu.lb) {
+			return
+		}
+		updated := obj.DeepCopy()
+		updated.Status.LoadBalancer = *isu.lb.DeepCopy()
+		updated, err = isu.Client.ExtensionsV1beta1().Ingresses(obj.Namespace).UpdateStatus(updated)
+		if err == nil {
+			isu.ingresses[types.NamespacedName{Namespace: obj.Namespace, Name: obj.Name}] = updated
+		}
+	}
+
+	if err != nil {
+		o := obj.(dag.Object).GetObjectMeta()
+		isu.WithError(err).
+			WithField("name", o.GetName()).
+			WithField("namespace", o.GetNamespace()).
+			Error("failed to update ingress status")
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contour
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestIngressStatusUpdater(t *testing.T) {
+	ingress := func(name, class string) *v1beta1.Ingress {
+		i := &v1beta1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+		}
+		if class != "" {
+			i.Annotations = map[string]string{"kubernetes.io/ingress.class": class}
+		}
+		return i
+	}
+	service := func(name string, lb v1.LoadBalancerStatus) *v1.Service {
+		return &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "projectcontour",
+			},
+			Status: v1.ServiceStatus{
+				LoadBalancer: lb,
+			},
+		}
+	}
+	lb := v1.LoadBalancerStatus{
+		Ingress: []v1.LoadBalancerIngress{{IP: "192.0.2.1"}},
+	}
+
+	owned := ingress("owned", "")
+	explicit := ingress("explicit", "contour")
+	other := ingress("other", "nginx")
+
+	client := fake.NewSimpleClientset(owned, explicit, other)
+	isu := &IngressStatusUpdater{
+		Client:                client,
+		EnvoyServiceName:      "envoy",
+		EnvoyServiceNamespace: "projectcontour",
+		FieldLogger:           testLogger(t),
+	}
+
+	status := func(name string) v1.LoadBalancerStatus {
+		t.Helper()
+		i, err := client.NetworkingV1beta1().Ingresses("default").Get(name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return i.Status.LoadBalancer
+	}
+	assertStatus := func(name string, want v1.LoadBalancerStatus) {
+		t.Helper()
+		if diff := cmp.Diff(want, status(name)); diff != "" {
+			t.Fatalf("%s: %s", name, diff)
+		}
+	}
+
+	isu.handle(opAdd{obj: owned})
+	isu.handle(opAdd{obj: explicit})
+	isu.handle(opAdd{obj: other})
+	isu.handle(opAdd{obj: service("envoy", lb)})
+
+	// nothing is written until elected leader.
+	assertStatus("owned", v1.LoadBalancerStatus{})
+
+	isu.leader = true
+	isu.updateAll()
+	assertStatus("owned", lb)
+	assertStatus("explicit", lb)
+	assertStatus("other", v1.LoadBalancerStatus{})
+
+	// other services are ignored.
+	isu.handle(opUpdate{newObj: service("kuard", v1.LoadBalancerStatus{
+		Ingress: []v1.LoadBalancerIngress{{IP: "192.0.2.2"}},
+	})})
+	assertStatus("owned", lb)
+
+	// a new address is copied to every owned ingress.
+	lb2 := v1.LoadBalancerStatus{
+		Ingress: []v1.LoadBalancerIngress{{Hostname: "envoy.example.com"}},
+	}
+	isu.handle(opUpdate{newObj: service("envoy", lb2)})
+	assertStatus("owned", lb2)
+	assertStatus("explicit", lb2)
+	assertStatus("other", v1.LoadBalancerStatus{})
+
+	// an ingress which moves to another class is no longer updated.
+	isu.handle(opUpdate{newObj: ingress("explicit", "nginx")})
+	isu.handle(opDelete{obj: service("envoy", lb2)})
+	assertStatus("owned", v1.LoadBalancerStatus{})
+	assertStatus("explicit", lb2)
+}
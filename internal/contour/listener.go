@@ -87,6 +87,56 @@ type ListenerVisitorConfig struct {
 
 	// RequestTimeout configures the request_timeout for all Connection Managers.
 	RequestTimeout time.Duration
+
+	// IngressClassListeners holds the addresses of the listeners
+	// serving each ingress class bound to its own listeners, as
+	// given by the dag.Listener's IngressClass.
+	IngressClassListeners map[string]ListenerAddresses
+}
+
+// ListenerAddresses holds the addresses and ports of a pair of
+// HTTP and HTTPS listeners. If an address is not set, the default
+// listener's address is used. The ports must be set.
+type ListenerAddresses struct {
+	HTTPAddress  string
+	HTTPPort     int
+	HTTPSAddress string
+	HTTPSPort    int
+}
+
+// listenerAddresses returns the ListenerAddresses of the listeners
+// serving the ingress class, or of the default listeners if class
+// is empty.
+func (lvc *ListenerVisitorConfig) listenerAddresses(class string) ListenerAddresses {
+	addrs := ListenerAddresses{
+		HTTPAddress:  lvc.httpAddress(),
+		HTTPPort:     lvc.httpPort(),
+		HTTPSAddress: lvc.httpsAddress(),
+		HTTPSPort:    lvc.httpsPort(),
+	}
+	if class == "" {
+		return addrs
+	}
+	cl := lvc.IngressClassListeners[class]
+	if cl.HTTPAddress != "" {
+		addrs.HTTPAddress = cl.HTTPAddress
+	}
+	if cl.HTTPSAddress != "" {
+		addrs.HTTPSAddress = cl.HTTPSAddress
+	}
+	addrs.HTTPPort = cl.HTTPPort
+	addrs.HTTPSPort = cl.HTTPSPort
+	return addrs
+}
+
+// listenerName returns the name of the listener, and its route
+// configuration, serving the ingress class. The default listeners,
+// shared by every other ingress class, are named base.
+func listenerName(base, class string) string {
+	if class == "" {
+		return base
+	}
+	return base + "_" + class
 }
 
 // httpAddress returns the port for the HTTP (non TLS)
@@ -219,19 +269,14 @@ func NewListenerCache(address string, port int) ListenerCache {
 	}
 }
 
-// Update replaces the contents of the cache with the supplied map.
-func (c *ListenerCache) Update(v map[string]*v2.Listener) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	c.values = v
-	c.Cond.Notify()
-}
-
 // Contents returns a copy of the cache's contents.
 func (c *ListenerCache) Contents() []proto.Message {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	return c.contents()
+}
+
+func (c *ListenerCache) contents() []proto.Message {
 	var values []proto.Message
 	for _, v := range c.values {
 		values = append(values, v)
@@ -248,6 +293,21 @@ func (c *ListenerCache) Contents() []proto.Message {
 func (c *ListenerCache) Query(names []string) []proto.Message {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	return c.query(names)
+}
+
+// Snapshot returns the version of the cache and its contents, or the
+// entries matching names, at that version.
+func (c *ListenerCache) Snapshot(names []string) (int, []proto.Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(names) == 0 {
+		return c.version(), c.contents()
+	}
+	return c.version(), c.query(names)
+}
+
+func (c *ListenerCache) query(names []string) []proto.Message {
 	var values []proto.Message
 	for _, n := range names {
 		v, ok := c.values[n]
@@ -282,51 +342,64 @@ type listenerVisitor struct {
 	*ListenerVisitorConfig
 
 	listeners map[string]*v2.Listener
-	http      bool // at least one dag.VirtualHost encountered
+	http      map[string]bool         // ingress classes with at least one dag.VirtualHost
+	https     map[string]*v2.Listener // https listener of each ingress class
+	class     string                  // ingress class of the dag.Listener being visited
 }
 
 func visitListeners(root dag.Vertex, lvc *ListenerVisitorConfig) map[string]*v2.Listener {
 	lv := listenerVisitor{
 		ListenerVisitorConfig: lvc,
-		listeners: map[string]*v2.Listener{
-			ENVOY_HTTPS_LISTENER: envoy.Listener(
-				ENVOY_HTTPS_LISTENER,
-				lvc.httpsAddress(), lvc.httpsPort(),
-				secureProxyProtocol(lvc.UseProxyProto),
-			),
-		},
+		listeners:             make(map[string]*v2.Listener),
+		http:                  make(map[string]bool),
+		https:                 make(map[string]*v2.Listener),
 	}
 	lv.visit(root)
 
-	// add a listener if there are vhosts bound to http.
-	if lv.http {
-		lv.listeners[ENVOY_HTTP_LISTENER] = envoy.Listener(
-			ENVOY_HTTP_LISTENER,
-			lvc.httpAddress(), lvc.httpPort(),
+	// add a listener for each ingress class with vhosts bound to http.
+	for class := range lv.http {
+		name := listenerName(ENVOY_HTTP_LISTENER, class)
+		addrs := lvc.listenerAddresses(class)
+		lv.listeners[name] = envoy.Listener(
+			name,
+			addrs.HTTPAddress, addrs.HTTPPort,
 			proxyProtocol(lvc.UseProxyProto),
-			envoy.HTTPConnectionManager(ENVOY_HTTP_LISTENER, lvc.newInsecureAccessLog(), lvc.requestTimeout()),
+			envoy.HTTPConnectionManager(name, lvc.newInsecureAccessLog(), lvc.requestTimeout()),
 		)
-
 	}
 
-	// remove the https listener if there are no vhosts bound to it.
-	if len(lv.listeners[ENVOY_HTTPS_LISTENER].FilterChains) == 0 {
-		delete(lv.listeners, ENVOY_HTTPS_LISTENER)
-	} else {
-		// there's some https listeners, we need to sort the filter chains
-		// to ensure that the LDS entries are identical.
-		sort.SliceStable(lv.listeners[ENVOY_HTTPS_LISTENER].FilterChains,
-			func(i, j int) bool {
-				// The ServerNames field will only ever have a single entry
-				// in our FilterChain config, so it's okay to only sort
-				// on the first slice entry.
-				return lv.listeners[ENVOY_HTTPS_LISTENER].FilterChains[i].FilterChainMatch.ServerNames[0] < lv.listeners[ENVOY_HTTPS_LISTENER].FilterChains[j].FilterChainMatch.ServerNames[0]
-			})
+	// https listeners are only created for ingress classes with vhosts bound
+	// to them, we need to sort the filter chains to ensure that the LDS
+	// entries are identical.
+	for _, l := range lv.https {
+		fcs := l.FilterChains
+		sort.SliceStable(fcs, func(i, j int) bool {
+			// The ServerNames field will only ever have a single entry
+			// in our FilterChain config, so it's okay to only sort
+			// on the first slice entry.
+			return fcs[i].FilterChainMatch.ServerNames[0] < fcs[j].FilterChainMatch.ServerNames[0]
+		})
+		lv.listeners[l.Name] = l
 	}
 
 	return lv.listeners
 }
 
+// httpsListener returns the https listener of the ingress class being visited.
+func (v *listenerVisitor) httpsListener() *v2.Listener {
+	l, ok := v.https[v.class]
+	if !ok {
+		addrs := v.listenerAddresses(v.class)
+		l = envoy.Listener(
+			listenerName(ENVOY_HTTPS_LISTENER, v.class),
+			addrs.HTTPSAddress, addrs.HTTPSPort,
+			secureProxyProtocol(v.UseProxyProto),
+		)
+		v.https[v.class] = l
+	}
+	return l
+}
+
 func proxyProtocol(useProxy bool) []*envoy_api_v2_listener.ListenerFilter {
 	if useProxy {
 		return envoy.ListenerFilters(
@@ -349,19 +422,23 @@ func (v *listenerVisitor) visit(vertex dag.Vertex) {
 	}
 
 	switch vh := vertex.(type) {
+	case *dag.Listener:
+		v.class = vh.IngressClass
+		vertex.Visit(v.visit)
 	case *dag.VirtualHost:
-		// we only create on http listener so record the fact
-		// that we need to then double back at the end and add
-		// the listener properly.
-		v.http = true
+		// we only create one http listener per ingress class so
+		// record the fact that we need to then double back at the
+		// end and add the listener properly.
+		v.http[v.class] = true
 	case *dag.SecureVirtualHost:
+		name := listenerName(ENVOY_HTTPS_LISTENER, v.class)
 		filters := envoy.Filters(
-			envoy.HTTPConnectionManager(ENVOY_HTTPS_LISTENER, v.ListenerVisitorConfig.newSecureAccessLog(), v.ListenerVisitorConfig.requestTimeout()),
+			envoy.HTTPConnectionManager(name, v.ListenerVisitorConfig.newSecureAccessLog(), v.ListenerVisitorConfig.requestTimeout()),
 		)
 		alpnProtos := []string{"h2", "http/1.1"}
 		if vh.TCPProxy != nil {
 			filters = envoy.Filters(
-				envoy.TCPProxy(ENVOY_HTTPS_LISTENER, vh.TCPProxy, v.ListenerVisitorConfig.newSecureAccessLog()),
+				envoy.TCPProxy(name, vh.TCPProxy, v.ListenerVisitorConfig.newSecureAccessLog()),
 			)
 			alpnProtos = nil // do not offer ALPN
 		}
@@ -374,7 +451,8 @@ func (v *listenerVisitor) visit(vertex dag.Vertex) {
 			alpnProtos...,
 		)
 
-		v.listeners[ENVOY_HTTPS_LISTENER].FilterChains = append(v.listeners[ENVOY_HTTPS_LISTENER].FilterChains, fc)
+		l := v.httpsListener()
+		l.FilterChains = append(l.FilterChains, fc)
 	default:
 		// recurse
 		vertex.Visit(v.visit)
@@ -24,6 +24,7 @@ import (
 	ingressroutev1 "github.com/projectcontour/contour/apis/contour/v1beta1"
 	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
 	"github.com/projectcontour/contour/internal/assert"
+	"github.com/projectcontour/contour/internal/dag"
 	"github.com/projectcontour/contour/internal/envoy"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/api/networking/v1beta1"
@@ -58,7 +59,7 @@ func TestListenerCacheContents(t *testing.T) {
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			var lc ListenerCache
-			lc.Update(tc.contents)
+			lc.values = tc.contents
 			got := lc.Contents()
 			assert.Equal(t, tc.want, got)
 		})
@@ -115,7 +116,7 @@ func TestListenerCacheQuery(t *testing.T) {
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			var lc ListenerCache
-			lc.Update(tc.contents)
+			lc.values = tc.contents
 			got := lc.Query(tc.query)
 			assert.Equal(t, tc.want, got)
 		})
@@ -1066,6 +1067,112 @@ func TestListenerVisit(t *testing.T) {
 	}
 }
 
+func TestListenerVisitIngressClassListeners(t *testing.T) {
+	ingress := func(name, class string) *v1beta1.Ingress {
+		return &v1beta1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Annotations: map[string]string{
+					"projectcontour.io/ingress.class": class,
+				},
+			},
+			Spec: v1beta1.IngressSpec{
+				TLS: []v1beta1.IngressTLS{{
+					Hosts:      []string{name + ".example.com"},
+					SecretName: "secret",
+				}},
+				Rules: []v1beta1.IngressRule{{
+					Host: name + ".example.com",
+					IngressRuleValue: v1beta1.IngressRuleValue{
+						HTTP: &v1beta1.HTTPIngressRuleValue{
+							Paths: []v1beta1.HTTPIngressPath{{
+								Backend: *backend("kuard", 8080),
+							}},
+						},
+					},
+				}},
+			},
+		}
+	}
+
+	builder := dag.Builder{
+		Source: dag.KubernetesCache{
+			IngressClasses: []string{"external", "internal"},
+			FieldLogger:    testLogger(t),
+		},
+		ListenerClasses: []string{"internal"},
+	}
+	for _, o := range []interface{}{
+		ingress("external", "external"),
+		ingress("internal", "internal"),
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "secret",
+				Namespace: "default",
+			},
+			Type: "kubernetes.io/tls",
+			Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+		},
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "kuard",
+				Namespace: "default",
+			},
+			Spec: v1.ServiceSpec{
+				Ports: []v1.ServicePort{{
+					Name:     "http",
+					Protocol: "TCP",
+					Port:     8080,
+				}},
+			},
+		},
+	} {
+		builder.Source.Insert(o)
+	}
+
+	lvc := ListenerVisitorConfig{
+		IngressClassListeners: map[string]ListenerAddresses{
+			"internal": {
+				HTTPAddress: "10.0.0.1",
+				HTTPPort:    9080,
+				HTTPSPort:   9443,
+			},
+		},
+	}
+	got := visitListeners(builder.Build(), &lvc)
+
+	httpsListener := func(name, address string, port int, host string) *v2.Listener {
+		return &v2.Listener{
+			Name:    name,
+			Address: envoy.SocketAddress(address, port),
+			ListenerFilters: envoy.ListenerFilters(
+				envoy.TLSInspector(),
+			),
+			FilterChains: []*envoy_api_v2_listener.FilterChain{{
+				FilterChainMatch: &envoy_api_v2_listener.FilterChainMatch{
+					ServerNames: []string{host},
+				},
+				TransportSocket: transportSocket(envoy_api_v2_auth.TlsParameters_TLSv1_1, "h2", "http/1.1"),
+				Filters:         envoy.Filters(envoy.HTTPConnectionManager(name, envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG), 0)),
+			}},
+		}
+	}
+	want := listenermap(&v2.Listener{
+		Name:         ENVOY_HTTP_LISTENER,
+		Address:      envoy.SocketAddress("0.0.0.0", 8080),
+		FilterChains: envoy.FilterChains(envoy.HTTPConnectionManager(ENVOY_HTTP_LISTENER, envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG), 0)),
+	}, &v2.Listener{
+		Name:         "ingress_http_internal",
+		Address:      envoy.SocketAddress("10.0.0.1", 9080),
+		FilterChains: envoy.FilterChains(envoy.HTTPConnectionManager("ingress_http_internal", envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG), 0)),
+	},
+		httpsListener(ENVOY_HTTPS_LISTENER, "0.0.0.0", 8443, "external.example.com"),
+		httpsListener("ingress_https_internal", "0.0.0.0", 9443, "internal.example.com"),
+	)
+	assert.Equal(t, want, got)
+}
+
 func transportSocket(tlsMinProtoVersion envoy_api_v2_auth.TlsParameters_TlsProtocol, alpnprotos ...string) *envoy_api_v2_core.TransportSocket {
 	return envoy.DownstreamTLSTransportSocket(
 		envoy.DownstreamTLSContext("default/secret/68621186db", tlsMinProtoVersion, alpnprotos...),
@@ -0,0 +1,163 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contour
+
+import (
+	"sort"
+
+	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	envoy_api_v2_endpoint "github.com/envoyproxy/go-control-plane/envoy/api/v2/endpoint"
+	"github.com/golang/protobuf/proto"
+	"github.com/projectcontour/contour/internal/envoy"
+	"github.com/projectcontour/contour/internal/protobuf"
+	v1 "k8s.io/api/core/v1"
+)
+
+// nodeLocalities holds the locality of each Node which has
+// zone or region labels.
+type nodeLocalities map[string]*envoy_api_v2_core.Locality
+
+// update records the locality of node, returning true if it changed.
+func (n *nodeLocalities) update(node *v1.Node) bool {
+	locality := nodeLocality(node)
+	if proto.Equal(locality, (*n)[node.Name]) {
+		return false
+	}
+	if locality == nil {
+		delete(*n, node.Name)
+		return true
+	}
+	if *n == nil {
+		*n = make(nodeLocalities)
+	}
+	(*n)[node.Name] = locality
+	return true
+}
+
+// remove forgets the locality of node, returning true if it was known.
+func (n nodeLocalities) remove(node *v1.Node) bool {
+	if _, ok := n[node.Name]; !ok {
+		return false
+	}
+	delete(n, node.Name)
+	return true
+}
+
+// lookup returns the locality of the named Node, or nil if
+// the Node or its locality is not known.
+func (n nodeLocalities) lookup(nodename *string) *envoy_api_v2_core.Locality {
+	if nodename == nil {
+		return nil
+	}
+	return n[*nodename]
+}
+
+// nodeLocality returns the locality of node from its topology
+// labels, falling back to the deprecated failure-domain labels.
+// If node has neither a region nor a zone, nil is returned.
+func nodeLocality(node *v1.Node) *envoy_api_v2_core.Locality {
+	label := func(keys ...string) string {
+		for _, key := range keys {
+			if v, ok := node.Labels[key]; ok {
+				return v
+			}
+		}
+		return ""
+	}
+
+	locality := &envoy_api_v2_core.Locality{
+		Region: label("topology.kubernetes.io/region", "failure-domain.beta.kubernetes.io/region"),
+		Zone:   label("topology.kubernetes.io/zone", "failure-domain.beta.kubernetes.io/zone"),
+	}
+	if locality.Region == "" && locality.Zone == "" {
+		return nil
+	}
+	return locality
+}
+
+// localityAddress is an endpoint address, its locality, if known,
+// its health status, if not healthy, and its load balancing weight,
+// if weighted.
+type localityAddress struct {
+	ip       string
+	port     int
+	locality *envoy_api_v2_core.Locality
+	health   envoy_api_v2_core.HealthStatus
+	weight   uint32
+}
+
+// localityLbEndpoints returns the LocalityLbEndpoints for addresses.
+// If the locality of any address is known, the addresses are
+// grouped by locality, otherwise they form a single group. Each
+// group is weighted by the sum of the weights of its addresses, an
+// unweighted address counting as one. Addresses of unknown locality
+// are grouped first, followed by each locality in region and zone order.
+func localityLbEndpoints(addresses []localityAddress) []*envoy_api_v2_endpoint.LocalityLbEndpoints {
+	var groups []*envoy_api_v2_endpoint.LocalityLbEndpoints
+	group := func(locality *envoy_api_v2_core.Locality) *envoy_api_v2_endpoint.LocalityLbEndpoints {
+		for _, g := range groups {
+			if proto.Equal(g.Locality, locality) {
+				return g
+			}
+		}
+		g := &envoy_api_v2_endpoint.LocalityLbEndpoints{
+			Locality: locality,
+		}
+		groups = append(groups, g)
+		return g
+	}
+
+	localized := false
+	for _, a := range addresses {
+		localized = localized || a.locality != nil
+
+		g := group(a.locality)
+		lbendpoint := envoy.LBEndpoint(envoy.SocketAddress(a.ip, a.port))
+		lbendpoint.HealthStatus = a.health
+		if a.weight > 0 {
+			lbendpoint.LoadBalancingWeight = protobuf.UInt32(a.weight)
+		}
+		g.LbEndpoints = append(g.LbEndpoints, lbendpoint)
+	}
+
+	if localized {
+		sort.SliceStable(groups, func(i, j int) bool {
+			li, lj := groups[i].Locality, groups[j].Locality
+			switch {
+			case li == nil || lj == nil:
+				return li == nil && lj != nil
+			case li.Region != lj.Region:
+				return li.Region < lj.Region
+			default:
+				return li.Zone < lj.Zone
+			}
+		})
+	}
+
+	// every group is weighted, even when no locality is known, as
+	// Envoy sends no traffic to an unweighted locality when a
+	// cluster uses the LocalityWeighted policy.
+	for _, g := range groups {
+		var weight uint32
+		for _, lbendpoint := range g.LbEndpoints {
+			if w := lbendpoint.LoadBalancingWeight; w != nil {
+				weight += w.Value
+			} else {
+				weight++
+			}
+		}
+		g.LoadBalancingWeight = protobuf.UInt32(weight)
+	}
+	return groups
+}
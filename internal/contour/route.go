@@ -33,19 +33,14 @@ type RouteCache struct {
 	Cond
 }
 
-// Update replaces the contents of the cache with the supplied map.
-func (c *RouteCache) Update(v map[string]*v2.RouteConfiguration) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	c.values = v
-	c.Cond.Notify()
-}
-
 // Contents returns a copy of the cache's contents.
 func (c *RouteCache) Contents() []proto.Message {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	return c.contents()
+}
+
+func (c *RouteCache) contents() []proto.Message {
 	var values []proto.Message
 	for _, v := range c.values {
 		values = append(values, v)
@@ -58,6 +53,21 @@ func (c *RouteCache) Contents() []proto.Message {
 func (c *RouteCache) Query(names []string) []proto.Message {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	return c.query(names)
+}
+
+// Snapshot returns the version of the cache and its contents, or the
+// entries matching names, at that version.
+func (c *RouteCache) Snapshot(names []string) (int, []proto.Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(names) == 0 {
+		return c.version(), c.contents()
+	}
+	return c.version(), c.query(names)
+}
+
+func (c *RouteCache) query(names []string) []proto.Message {
 	var values []proto.Message
 	for _, n := range names {
 		v, ok := c.values[n]
@@ -93,22 +103,11 @@ type routeVisitor struct {
 }
 
 func visitRoutes(root dag.Vertex) map[string]*v2.RouteConfiguration {
-	headers := envoy.Headers(
-		envoy.AppendHeader("x-request-start", "t=%START_TIME(%s.%3f)%"),
-	)
-
 	rv := routeVisitor{
-		routes: map[string]*v2.RouteConfiguration{
-			"ingress_http": {
-				Name:                "ingress_http",
-				RequestHeadersToAdd: headers,
-			},
-			"ingress_https": {
-				Name:                "ingress_https",
-				RequestHeadersToAdd: headers,
-			},
-		},
+		routes: make(map[string]*v2.RouteConfiguration),
 	}
+	// the default route configurations are always present.
+	rv.addRouteConfigurations("")
 	rv.visit(root)
 	for _, v := range rv.routes {
 		sort.Stable(virtualHostsByName(v.VirtualHosts))
@@ -116,9 +115,29 @@ func visitRoutes(root dag.Vertex) map[string]*v2.RouteConfiguration {
 	return rv.routes
 }
 
+// addRouteConfigurations adds the http and https route
+// configurations of the ingress class, if not present.
+func (v *routeVisitor) addRouteConfigurations(class string) {
+	headers := envoy.Headers(
+		envoy.AppendHeader("x-request-start", "t=%START_TIME(%s.%3f)%"),
+	)
+	for _, base := range []string{ENVOY_HTTP_LISTENER, ENVOY_HTTPS_LISTENER} {
+		name := listenerName(base, class)
+		if _, ok := v.routes[name]; !ok {
+			v.routes[name] = &v2.RouteConfiguration{
+				Name:                name,
+				RequestHeadersToAdd: headers,
+			}
+		}
+	}
+}
+
 func (v *routeVisitor) visit(vertex dag.Vertex) {
 	switch l := vertex.(type) {
 	case *dag.Listener:
+		v.addRouteConfigurations(l.IngressClass)
+		http := listenerName(ENVOY_HTTP_LISTENER, l.IngressClass)
+		https := listenerName(ENVOY_HTTPS_LISTENER, l.IngressClass)
 		l.Visit(func(vertex dag.Vertex) {
 			switch vh := vertex.(type) {
 			case *dag.VirtualHost:
@@ -150,7 +169,7 @@ func (v *routeVisitor) visit(vertex dag.Vertex) {
 				}
 				sortRoutes(routes)
 				vhost := envoy.VirtualHost(vh.Name, routes...)
-				v.routes["ingress_http"].VirtualHosts = append(v.routes["ingress_http"].VirtualHosts, vhost)
+				v.routes[http].VirtualHosts = append(v.routes[http].VirtualHosts, vhost)
 			case *dag.SecureVirtualHost:
 				var routes []*envoy_api_v2_route.Route
 				vh.Visit(func(v dag.Vertex) {
@@ -169,7 +188,7 @@ func (v *routeVisitor) visit(vertex dag.Vertex) {
 				}
 				sortRoutes(routes)
 				vhost := envoy.VirtualHost(vh.VirtualHost.Name, routes...)
-				v.routes["ingress_https"].VirtualHosts = append(v.routes["ingress_https"].VirtualHosts, vhost)
+				v.routes[https].VirtualHosts = append(v.routes[https].VirtualHosts, vhost)
 			default:
 				// recurse
 				vertex.Visit(v.visit)
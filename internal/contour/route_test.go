@@ -65,7 +65,7 @@ func TestRouteCacheContents(t *testing.T) {
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			var rc RouteCache
-			rc.Update(tc.contents)
+			rc.values = tc.contents
 			got := rc.Contents()
 			assert.Equal(t, tc.want, got)
 		})
@@ -125,7 +125,7 @@ func TestRouteCacheQuery(t *testing.T) {
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			var rc RouteCache
-			rc.Update(tc.contents)
+			rc.values = tc.contents
 			got := rc.Query(tc.query)
 			assert.Equal(t, tc.want, got)
 		})
@@ -1874,6 +1874,76 @@ func TestRouteVisit(t *testing.T) {
 	}
 }
 
+func TestRouteVisitIngressClassListeners(t *testing.T) {
+	ingress := func(name, class string) *v1beta1.Ingress {
+		return &v1beta1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Annotations: map[string]string{
+					"projectcontour.io/ingress.class": class,
+				},
+			},
+			Spec: v1beta1.IngressSpec{
+				Rules: []v1beta1.IngressRule{{
+					Host: name + ".example.com",
+					IngressRuleValue: v1beta1.IngressRuleValue{
+						HTTP: &v1beta1.HTTPIngressRuleValue{
+							Paths: []v1beta1.HTTPIngressPath{{
+								Backend: *backend("kuard", 8080),
+							}},
+						},
+					},
+				}},
+			},
+		}
+	}
+
+	builder := dag.Builder{
+		Source: dag.KubernetesCache{
+			IngressClasses: []string{"external", "internal"},
+			FieldLogger:    testLogger(t),
+		},
+		ListenerClasses: []string{"internal"},
+	}
+	for _, o := range []interface{}{
+		ingress("external", "external"),
+		ingress("internal", "internal"),
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "kuard",
+				Namespace: "default",
+			},
+			Spec: v1.ServiceSpec{
+				Ports: []v1.ServicePort{{
+					Protocol:   "TCP",
+					Port:       8080,
+					TargetPort: intstr.FromInt(8080),
+				}},
+			},
+		},
+	} {
+		builder.Source.Insert(o)
+	}
+
+	got := visitRoutes(builder.Build())
+	want := routeConfigurations(
+		envoy.RouteConfiguration("ingress_http",
+			envoy.VirtualHost("external.example.com",
+				envoy.Route(routePrefix("/"), routecluster("default/kuard/8080/da39a3ee5e")),
+			),
+		),
+		envoy.RouteConfiguration("ingress_https"),
+		envoy.RouteConfiguration("ingress_http_internal",
+			envoy.VirtualHost("internal.example.com",
+				envoy.Route(routePrefix("/"), routecluster("default/kuard/8080/da39a3ee5e")),
+			),
+		),
+		envoy.RouteConfiguration("ingress_https_internal"),
+	)
+	assert.Equal(t, want, got)
+}
+
 func TestSortLongestRouteFirst(t *testing.T) {
 	tests := map[string]struct {
 		routes []*envoy_api_v2_route.Route
@@ -51,7 +51,7 @@ func TestSecretCacheContents(t *testing.T) {
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			var sc SecretCache
-			sc.Update(tc.contents)
+			sc.values = tc.contents
 			got := sc.Contents()
 			assert.Equal(t, tc.want, got)
 		})
@@ -95,7 +95,7 @@ func TestSecretCacheQuery(t *testing.T) {
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			var sc SecretCache
-			sc.Update(tc.contents)
+			sc.values = tc.contents
 			got := sc.Query(tc.query)
 			assert.Equal(t, tc.want, got)
 		})
@@ -0,0 +1,232 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contour
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// slowStartWindowAnnotation sets the slow start window of a Service.
+// It is not part of the HTTPProxy service API because the weights are
+// sent in the Service's ClusterLoadAssignments, which are shared by
+// every route to the Service.
+const slowStartWindowAnnotation = "projectcontour.io/slow-start-window"
+
+// slowStartMaxWeight is the load balancing weight of an address
+// which has finished its slow start.
+const slowStartMaxWeight = 100
+
+// slowStart ramps up the load balancing weight of each newly ready
+// address of a Service over the Service's slow start window, so that
+// new endpoints are not sent their full share of traffic before they
+// have warmed up.
+type slowStart struct {
+	// now returns the current time, it defaults to time.Now.
+	now func() time.Time
+
+	// windows holds the slow start window of each Service.
+	windows map[string]time.Duration
+
+	// ready holds when each ready address of each Service was
+	// first seen. Addresses which were ready when their Service
+	// was first seen have a zero time, they are never ramped.
+	ready map[string]map[string]time.Time
+
+	// pending holds the Services whose addresses are ramping
+	// up, timer is the timer which next recomputes their
+	// assignments, and deadline is when it fires.
+	pending  map[string]bool
+	timer    *time.Timer
+	deadline time.Time
+}
+
+// slowStartWindow returns the slow start window of svc, or
+// an error if its annotation is not a positive duration.
+func slowStartWindow(svc *v1.Service) (time.Duration, error) {
+	v, ok := svc.Annotations[slowStartWindowAnnotation]
+	if !ok {
+		return 0, nil
+	}
+	window, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, err
+	}
+	if window < 0 {
+		return 0, fmt.Errorf("%s: %q must not be negative", slowStartWindowAnnotation, v)
+	}
+	return window, nil
+}
+
+// setWindow records the slow start window of service, returning
+// true if it changed.
+func (s *slowStart) setWindow(service string, window time.Duration) bool {
+	if s.windows[service] == window {
+		return false
+	}
+	if window == 0 {
+		delete(s.windows, service)
+		return true
+	}
+	if s.windows == nil {
+		s.windows = make(map[string]time.Duration)
+	}
+	s.windows[service] = window
+	return true
+}
+
+// observe records the ready addresses of service. Addresses not seen
+// before are recorded as ready now, unless warm is true, and those
+// no longer ready are forgotten.
+func (s *slowStart) observe(service string, ips []string, warm bool) {
+	if len(ips) == 0 {
+		delete(s.ready, service)
+		return
+	}
+
+	ready := make(map[string]time.Time, len(ips))
+	now := s.time()
+	for _, ip := range ips {
+		t, ok := s.ready[service][ip]
+		switch {
+		case ok:
+			ready[ip] = t
+		case warm:
+			ready[ip] = time.Time{}
+		default:
+			ready[ip] = now
+		}
+	}
+	if s.ready == nil {
+		s.ready = make(map[string]map[string]time.Time)
+	}
+	s.ready[service] = ready
+}
+
+// forget forgets the ready addresses of service.
+func (s *slowStart) forget(service string) {
+	delete(s.ready, service)
+}
+
+// ramping returns true if any ready address of service is
+// within its slow start window at now.
+func (s *slowStart) ramping(service string, now time.Time) bool {
+	window := s.windows[service]
+	if window <= 0 {
+		return false
+	}
+	for _, t := range s.ready[service] {
+		if !t.IsZero() && now.Sub(t) < window {
+			return true
+		}
+	}
+	return false
+}
+
+// weight returns the load balancing weight of the address ip of
+// service at now, between 1 and slowStartMaxWeight.
+func (s *slowStart) weight(service, ip string, now time.Time) uint32 {
+	window := s.windows[service]
+	t := s.ready[service][ip]
+	if window <= 0 || t.IsZero() {
+		return slowStartMaxWeight
+	}
+	elapsed := now.Sub(t)
+	if elapsed >= window {
+		return slowStartMaxWeight
+	}
+	weight := uint32(int64(slowStartMaxWeight) * int64(elapsed) / int64(window))
+	if weight < 1 {
+		weight = 1
+	}
+	return weight
+}
+
+// interval returns how often the weights of service are recomputed
+// while it is ramping; ten times over its window, but at most once
+// a second.
+func (s *slowStart) interval(service string) time.Duration {
+	interval := s.windows[service] / 10
+	if interval < time.Second {
+		interval = time.Second
+	}
+	return interval
+}
+
+// schedule arranges for f to be called after the interval of service
+// if any of its addresses are ramping up. f is expected to call due.
+func (s *slowStart) schedule(service string, f func()) {
+	now := s.time()
+	if !s.ramping(service, now) {
+		return
+	}
+	if s.pending == nil {
+		s.pending = make(map[string]bool)
+	}
+	s.pending[service] = true
+
+	// a timer armed for a Service with a longer interval is
+	// moved earlier. If it cannot be stopped f is already
+	// running, and its call to due rearms it for service.
+	interval := s.interval(service)
+	if s.timer != nil && now.Add(interval).Before(s.deadline) && s.timer.Stop() {
+		s.timer = nil
+	}
+	if s.timer == nil {
+		s.arm(now, interval, f)
+	}
+}
+
+// due returns the Services whose assignments should be recomputed
+// when f is called. Services which have finished their slow start
+// are returned one final time, so their weights are removed, and f
+// is scheduled again for those still ramping up.
+func (s *slowStart) due(f func()) []string {
+	s.timer = nil
+	s.deadline = time.Time{}
+	now := s.time()
+	var services []string
+	var interval time.Duration
+	for service := range s.pending {
+		services = append(services, service)
+		if !s.ramping(service, now) {
+			delete(s.pending, service)
+			continue
+		}
+		if i := s.interval(service); interval == 0 || i < interval {
+			interval = i
+		}
+	}
+	if interval > 0 {
+		s.arm(now, interval, f)
+	}
+	sort.Strings(services)
+	return services
+}
+
+// arm arms the timer to call f after interval.
+func (s *slowStart) arm(now time.Time, interval time.Duration, f func()) {
+	s.timer = time.AfterFunc(interval, f)
+	s.deadline = now.Add(interval)
+}
+
+func (s *slowStart) time() time.Time {
+	if s.now == nil {
+		return time.Now()
+	}
+	return s.now()
+}
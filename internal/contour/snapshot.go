@@ -0,0 +1,49 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contour
+
+import (
+	"sync/atomic"
+
+	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	envoy_api_v2_auth "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
+)
+
+// Snapshot is the set of xDS resources computed from a single DAG.
+// Every resource in a Snapshot is published at the Snapshot's Version,
+// so the version Envoy reports identifies the DAG that produced it.
+// Endpoints are not computed from the DAG and so are not part of a
+// Snapshot, but they are versioned on the same sequence when the
+// EndpointsTranslator shares the CacheHandler's Versions. A Snapshot
+// must not be modified once published.
+type Snapshot struct {
+	Version int
+
+	Listeners map[string]*v2.Listener
+	Routes    map[string]*v2.RouteConfiguration
+	Clusters  map[string]*v2.Cluster
+	Secrets   map[string]*envoy_api_v2_auth.Secret
+}
+
+// Versions issues the versions of xDS resources. Sharing Versions
+// between a CacheHandler and an EndpointsTranslator places every xDS
+// resource type on a single, monotonically increasing, sequence.
+type Versions struct {
+	last int64
+}
+
+// Next returns the next version in the sequence.
+func (v *Versions) Next() int {
+	return int(atomic.AddInt64(&v.last, 1))
+}
@@ -63,6 +63,7 @@ var annotationsByKind = map[string]map[string]struct{}{
 		"projectcontour.io/max-pending-requests":  {},
 		"projectcontour.io/max-requests":          {},
 		"projectcontour.io/max-retries":           {},
+		"projectcontour.io/slow-start-window":     {},
 		"projectcontour.io/upstream-protocol.h2":  {},
 		"projectcontour.io/upstream-protocol.h2c": {},
 		"projectcontour.io/upstream-protocol.tls": {},
@@ -199,6 +200,25 @@ func ingressClass(o Object) string {
 	return ""
 }
 
+// MatchesIngressClass returns true if o has no ingress class
+// annotation, or its ingress class is one of classes. If classes
+// is empty DEFAULT_INGRESS_CLASS is used, as is any empty class.
+func MatchesIngressClass(o Object, classes ...string) bool {
+	c := ingressClass(o)
+	if c == "" {
+		return true
+	}
+	if len(classes) == 0 {
+		return c == DEFAULT_INGRESS_CLASS
+	}
+	for _, class := range classes {
+		if c == stringOrDefault(class, DEFAULT_INGRESS_CLASS) {
+			return true
+		}
+	}
+	return false
+}
+
 // MinProtoVersion returns the TLS protocol version specified by an ingress annotation
 // or default if non present.
 func MinProtoVersion(version string) envoy_api_v2_auth.TlsParameters_TlsProtocol {
@@ -405,6 +405,9 @@ func TestAnnotationKindValidation(t *testing.T) {
 				"projectcontour.io/annotation": {
 					known: true, valid: false,
 				},
+				"projectcontour.io/slow-start-window": {
+					known: true, valid: true,
+				},
 			},
 		},
 		"httpproxy": {
@@ -14,7 +14,9 @@
 package dag
 
 import (
+	"errors"
 	"fmt"
+	"net"
 	"sort"
 	"strconv"
 	"strings"
@@ -39,11 +41,21 @@ type Builder struct {
 	// permitInsecure field in IngressRoute.
 	DisablePermitInsecure bool
 
+	// ListenerClasses are the ingress classes whose virtual hosts
+	// are bound to their own Listeners. The virtual hosts of every
+	// other class are bound to the default Listeners.
+	ListenerClasses []string
+
+	// ConnectionTimeouts are the connection timeouts of each
+	// cluster which does not set its own. Zero durations are
+	// left unset.
+	ConnectionTimeouts ConnectionTimeoutPolicy
+
 	services map[servicemeta]*Service
 	secrets  map[Meta]*Secret
 
-	virtualhosts       map[string]*VirtualHost
-	securevirtualhosts map[string]*SecureVirtualHost
+	virtualhosts       map[listenerHost]*VirtualHost
+	securevirtualhosts map[listenerHost]*SecureVirtualHost
 
 	orphaned map[Meta]bool
 
@@ -65,7 +77,11 @@ func (b *Builder) Build() *DAG {
 
 	b.computeHTTPProxies()
 
-	return b.buildDAG()
+	b.computeGateways()
+
+	dag := b.buildDAG()
+	b.setConnectionTimeouts(dag)
+	return dag
 }
 
 // reset (re)inialises the internal state of the builder.
@@ -74,10 +90,11 @@ func (b *Builder) reset() {
 	b.secrets = make(map[Meta]*Secret, len(b.secrets))
 	b.orphaned = make(map[Meta]bool, len(b.orphaned))
 
-	b.virtualhosts = make(map[string]*VirtualHost)
-	b.securevirtualhosts = make(map[string]*SecureVirtualHost)
+	b.virtualhosts = make(map[listenerHost]*VirtualHost)
+	b.securevirtualhosts = make(map[listenerHost]*SecureVirtualHost)
 
 	b.statuses = make(map[Meta]Status, len(b.statuses))
+	b.gatewayStatuses = make(map[objectKey]Status, len(b.gatewayStatuses))
 }
 
 // lookupService returns a Service that matches the Meta and Port of the Kubernetes' Service.
@@ -132,6 +149,60 @@ func (b *Builder) addService(svc *v1.Service, port *v1.ServicePort) *Service {
 	return s
 }
 
+// lookupUpstream returns a Service for the Upstream m, whose endpoints
+// which do not set their own port use port, or an error if the Upstream
+// is missing or invalid.
+func (b *Builder) lookupUpstream(m Meta, port int) (*Service, error) {
+	u, ok := b.Source.upstreams[m]
+	if !ok {
+		return nil, errors.New("upstream not found")
+	}
+	if len(u.Spec.Endpoints) == 0 {
+		return nil, errors.New("upstream has no endpoints")
+	}
+
+	s := &Service{
+		Name:      m.name,
+		Namespace: m.namespace,
+		ServicePort: &v1.ServicePort{
+			Protocol: v1.ProtocolTCP,
+			Port:     int32(port),
+		},
+	}
+	ips := 0
+	for _, ep := range u.Spec.Endpoints {
+		if ep.Address == "" {
+			return nil, errors.New("upstream endpoint address is required")
+		}
+		p := ep.Port
+		if p == 0 {
+			p = port
+		}
+		if p < 1 || p > 65535 {
+			return nil, fmt.Errorf("upstream endpoint %s: port must be in the range 1-65535", ep.Address)
+		}
+		if net.ParseIP(ep.Address) != nil {
+			ips++
+		}
+		s.StaticEndpoints = append(s.StaticEndpoints, StaticEndpoint{
+			Address: ep.Address,
+			Port:    p,
+		})
+	}
+	if ips > 0 && ips < len(s.StaticEndpoints) {
+		return nil, errors.New("upstream endpoints must be all IP addresses or all DNS names")
+	}
+
+	if tls := u.Spec.TLS; tls != nil {
+		s.Protocol = "tls"
+		s.SNI = tls.SNI
+		if s.SNI == "" && ips == 0 {
+			s.SNI = s.StaticEndpoints[0].Address
+		}
+	}
+	return s, nil
+}
+
 func upstreamProtocol(svc *v1.Service, port *v1.ServicePort) string {
 	up := parseUpstreamProtocols(svc.Annotations)
 	protocol := up[port.Name]
@@ -141,6 +212,26 @@ func upstreamProtocol(svc *v1.Service, port *v1.ServicePort) string {
 	return protocol
 }
 
+// isTLS returns true if protocol connects to the upstream over TLS.
+func isTLS(protocol string) bool {
+	switch protocol {
+	case "tls", "h2":
+		return true
+	default:
+		return false
+	}
+}
+
+// isHTTP2 returns true if protocol speaks HTTP/2 to the upstream.
+func isHTTP2(protocol string) bool {
+	switch protocol {
+	case "h2", "h2c":
+		return true
+	default:
+		return false
+	}
+}
+
 // lookupSecret returns a Secret if present or nil if the underlying kubernetes
 // secret fails validation or is missing.
 func (b *Builder) lookupSecret(m Meta, validate func(*v1.Secret) bool) *Secret {
@@ -158,27 +249,46 @@ func (b *Builder) lookupSecret(m Meta, validate func(*v1.Secret) bool) *Secret {
 	return s
 }
 
-func (b *Builder) lookupVirtualHost(name string) *VirtualHost {
-	vh, ok := b.virtualhosts[name]
+// listenerHost identifies a virtual host by the ingress class
+// of the Listeners it is bound to and its name.
+type listenerHost struct {
+	class string
+	name  string
+}
+
+// hostFor returns the listenerHost of the virtual host name
+// defined by obj.
+func (b *Builder) hostFor(obj Object, name string) listenerHost {
+	class := ingressClass(obj)
+	for _, lc := range b.ListenerClasses {
+		if class == lc {
+			return listenerHost{class: class, name: name}
+		}
+	}
+	return listenerHost{name: name}
+}
+
+func (b *Builder) lookupVirtualHost(host listenerHost) *VirtualHost {
+	vh, ok := b.virtualhosts[host]
 	if !ok {
 		vh := &VirtualHost{
-			Name: name,
+			Name: host.name,
 		}
-		b.virtualhosts[vh.Name] = vh
+		b.virtualhosts[host] = vh
 		return vh
 	}
 	return vh
 }
 
-func (b *Builder) lookupSecureVirtualHost(name string) *SecureVirtualHost {
-	svh, ok := b.securevirtualhosts[name]
+func (b *Builder) lookupSecureVirtualHost(host listenerHost) *SecureVirtualHost {
+	svh, ok := b.securevirtualhosts[host]
 	if !ok {
 		svh := &SecureVirtualHost{
 			VirtualHost: VirtualHost{
-				Name: name,
+				Name: host.name,
 			},
 		}
-		b.securevirtualhosts[svh.VirtualHost.Name] = svh
+		b.securevirtualhosts[host] = svh
 		return svh
 	}
 	return svh
@@ -189,17 +299,20 @@ func (b *Builder) lookupSecureVirtualHost(name string) *SecureVirtualHost {
 // updated accordingly.
 func (b *Builder) validIngressRoutes() []*ingressroutev1.IngressRoute {
 	// ensure that a given fqdn is only referenced in a single ingressroute resource
+	// per set of Listeners.
 	var valid []*ingressroutev1.IngressRoute
-	fqdnIngressroutes := make(map[string][]*ingressroutev1.IngressRoute)
+	fqdnIngressroutes := make(map[listenerHost][]*ingressroutev1.IngressRoute)
 	for _, ir := range b.Source.ingressroutes {
 		if ir.Spec.VirtualHost == nil {
 			valid = append(valid, ir)
 			continue
 		}
-		fqdnIngressroutes[ir.Spec.VirtualHost.Fqdn] = append(fqdnIngressroutes[ir.Spec.VirtualHost.Fqdn], ir)
+		host := b.hostFor(ir, ir.Spec.VirtualHost.Fqdn)
+		fqdnIngressroutes[host] = append(fqdnIngressroutes[host], ir)
 	}
 
-	for fqdn, irs := range fqdnIngressroutes {
+	for host, irs := range fqdnIngressroutes {
+		fqdn := host.name
 		switch len(irs) {
 		case 1:
 			valid = append(valid, irs[0])
@@ -226,17 +339,20 @@ func (b *Builder) validIngressRoutes() []*ingressroutev1.IngressRoute {
 // updated accordingly.
 func (b *Builder) validHTTPProxies() []*projcontour.HTTPProxy {
 	// ensure that a given fqdn is only referenced in a single HTTPProxy resource
+	// per set of Listeners.
 	var valid []*projcontour.HTTPProxy
-	fqdnHTTPProxies := make(map[string][]*projcontour.HTTPProxy)
+	fqdnHTTPProxies := make(map[listenerHost][]*projcontour.HTTPProxy)
 	for _, proxy := range b.Source.httpproxies {
 		if proxy.Spec.VirtualHost == nil {
 			valid = append(valid, proxy)
 			continue
 		}
-		fqdnHTTPProxies[proxy.Spec.VirtualHost.Fqdn] = append(fqdnHTTPProxies[proxy.Spec.VirtualHost.Fqdn], proxy)
+		host := b.hostFor(proxy, proxy.Spec.VirtualHost.Fqdn)
+		fqdnHTTPProxies[host] = append(fqdnHTTPProxies[host], proxy)
 	}
 
-	for fqdn, proxies := range fqdnHTTPProxies {
+	for host, proxies := range fqdnHTTPProxies {
+		fqdn := host.name
 		switch len(proxies) {
 		case 1:
 			valid = append(valid, proxies[0])
@@ -267,7 +383,7 @@ func (b *Builder) computeSecureVirtualhosts() {
 			sec := b.lookupSecret(m, validSecret)
 			if sec != nil && b.delegationPermitted(m, ing.Namespace) {
 				for _, host := range tls.Hosts {
-					svhost := b.lookupSecureVirtualHost(host)
+					svhost := b.lookupSecureVirtualHost(b.hostFor(ing, host))
 					svhost.Secret = sec
 					version := compatAnnotation(ing, "tls-minimum-protocol-version")
 					svhost.MinProtoVersion = MinProtoVersion(version)
@@ -277,19 +393,21 @@ func (b *Builder) computeSecureVirtualhosts() {
 	}
 }
 
-func (b *Builder) delegationPermitted(secret Meta, to string) bool {
-	contains := func(haystack []string, needle string) bool {
-		if len(haystack) == 1 && haystack[0] == "*" {
+// delegatedTo returns true if the target namespaces of a delegation
+// include namespace, either by name or by the "*" wildcard.
+func delegatedTo(targets []string, namespace string) bool {
+	if len(targets) == 1 && targets[0] == "*" {
+		return true
+	}
+	for _, t := range targets {
+		if t == namespace {
 			return true
 		}
-		for _, h := range haystack {
-			if h == needle {
-				return true
-			}
-		}
-		return false
 	}
+	return false
+}
 
+func (b *Builder) delegationPermitted(secret Meta, to string) bool {
 	if secret.namespace == to {
 		// secret is in the same namespace as target
 		return true
@@ -300,7 +418,7 @@ func (b *Builder) delegationPermitted(secret Meta, to string) bool {
 			continue
 		}
 		for _, d := range d.Spec.Delegations {
-			if contains(d.TargetNamespaces, to) {
+			if delegatedTo(d.TargetNamespaces, to) {
 				if secret.name == d.SecretName {
 					return true
 				}
@@ -313,7 +431,7 @@ func (b *Builder) delegationPermitted(secret Meta, to string) bool {
 			continue
 		}
 		for _, d := range d.Spec.Delegations {
-			if contains(d.TargetNamespaces, to) {
+			if delegatedTo(d.TargetNamespaces, to) {
 				if secret.name == d.SecretName {
 					return true
 				}
@@ -323,6 +441,37 @@ func (b *Builder) delegationPermitted(secret Meta, to string) bool {
 	return false
 }
 
+// serviceDelegationPermitted returns true if an HTTPProxy in namespace
+// to may route to service. Services in the HTTPProxy's own namespace
+// are always permitted, services in other namespaces must be delegated
+// to it by a ServiceDelegation in the service's namespace.
+func (b *Builder) serviceDelegationPermitted(service Meta, to string) bool {
+	if service.namespace == to {
+		return true
+	}
+
+	for _, d := range b.Source.servicedelegations {
+		if d.Namespace != service.namespace {
+			continue
+		}
+		for _, d := range d.Spec.Delegations {
+			if delegatedTo(d.TargetNamespaces, to) && d.ServiceName == service.name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// serviceNamespace returns the namespace of an HTTPProxy service,
+// defaulting to the namespace of the HTTPProxy.
+func serviceNamespace(proxy *projcontour.HTTPProxy, service projcontour.Service) string {
+	if service.Namespace != "" {
+		return service.Namespace
+	}
+	return proxy.Namespace
+}
+
 func (b *Builder) computeIngresses() {
 	// deconstruct each ingress into routes and virtualhost entries
 	for _, ing := range b.Source.ingresses {
@@ -345,6 +494,7 @@ func (b *Builder) computeIngressRule(ing *v1beta1.Ingress, rule v1beta1.IngressR
 		// if host name is blank, rewrite to Envoy's * default host.
 		host = "*"
 	}
+	lh := b.hostFor(ing, host)
 	for _, httppath := range httppaths(rule) {
 		path := stringOrDefault(httppath.Path, "/")
 		be := httppath.Backend
@@ -358,13 +508,13 @@ func (b *Builder) computeIngressRule(ing *v1beta1.Ingress, rule v1beta1.IngressR
 
 		// should we create port 80 routes for this ingress
 		if tlsRequired(ing) || httpAllowed(ing) {
-			b.lookupVirtualHost(host).addRoute(r)
+			b.lookupVirtualHost(lh).addRoute(r)
 		}
 
 		// computeSecureVirtualhosts will have populated b.securevirtualhosts
 		// with the names of tls enabled ingress objects. If host exists then
 		// it is correctly configured for TLS.
-		svh, ok := b.securevirtualhosts[host]
+		svh, ok := b.securevirtualhosts[lh]
 		if ok && host != "*" {
 			svh.addRoute(r)
 		}
@@ -414,7 +564,7 @@ func (b *Builder) computeIngressRoute(ir *ingressroutev1.IngressRoute) {
 				sw.SetInvalid(fmt.Sprintf("%s: certificate delegation not permitted", tls.SecretName))
 				return
 			}
-			svhost := b.lookupSecureVirtualHost(ir.Spec.VirtualHost.Fqdn)
+			svhost := b.lookupSecureVirtualHost(b.hostFor(ir, host))
 			svhost.Secret = sec
 			svhost.MinProtoVersion = MinProtoVersion(ir.Spec.VirtualHost.TLS.MinimumProtocolVersion)
 			enforceTLS = true
@@ -431,9 +581,9 @@ func (b *Builder) computeIngressRoute(ir *ingressroutev1.IngressRoute) {
 	}
 
 	if ir.Spec.TCPProxy != nil && (passthrough || enforceTLS) {
-		b.processIngressRouteTCPProxy(sw, ir, nil, host)
+		b.processIngressRouteTCPProxy(sw, ir, nil, b.hostFor(ir, host))
 	}
-	b.processIngressRoutes(sw, ir, "", nil, host, ir.Spec.TCPProxy == nil && enforceTLS)
+	b.processIngressRoutes(sw, ir, "", nil, b.hostFor(ir, host), ir.Spec.TCPProxy == nil && enforceTLS)
 }
 
 func (b *Builder) computeHTTPProxies() {
@@ -483,13 +633,16 @@ func (b *Builder) computeHTTPProxy(proxy *projcontour.HTTPProxy) {
 				sw.SetInvalid(fmt.Sprintf("%s: certificate delegation not permitted", tls.SecretName))
 				return
 			}
-			svhost := b.lookupSecureVirtualHost(host)
+			svhost := b.lookupSecureVirtualHost(b.hostFor(proxy, host))
 			svhost.Secret = sec
 			svhost.MinProtoVersion = MinProtoVersion(proxy.Spec.VirtualHost.TLS.MinimumProtocolVersion)
 		}
 
 		if sec == nil && !tls.Passthrough {
 			sw.SetInvalid(fmt.Sprintf("TLS Secret [%s] not found or is malformed", tls.SecretName))
+			for _, w := range b.Source.warningsFor("Secret", m) {
+				sw.AddWarning(fmt.Sprintf("TLS Secret [%s] was rejected: %s", tls.SecretName, w.Message))
+			}
 			return
 		}
 	}
@@ -499,19 +652,19 @@ func (b *Builder) computeHTTPProxy(proxy *projcontour.HTTPProxy) {
 			sw.SetInvalid("tcpproxy: missing tls.passthrough or tls.secretName")
 			return
 		}
-		if !b.processHTTPProxyTCPProxy(sw, proxy, nil, host) {
+		if !b.processHTTPProxyTCPProxy(sw, proxy, nil, b.hostFor(proxy, host)) {
 			return
 		}
 	}
 
 	routes := b.computeRoutes(sw, proxy, nil, nil, tlsValid)
-	insecure := b.lookupVirtualHost(host)
+	insecure := b.lookupVirtualHost(b.hostFor(proxy, host))
 	addRoutes(insecure, routes)
 
 	// if TLS is enabled for this virtual host and there is no tcp proxy defined,
 	// then add routes to the secure virtualhost definition.
 	if tlsValid && proxy.Spec.TCPProxy == nil {
-		secure := b.lookupSecureVirtualHost(host)
+		secure := b.lookupSecureVirtualHost(b.hostFor(proxy, host))
 		addRoutes(secure, routes)
 	}
 }
@@ -651,15 +804,15 @@ func (b *Builder) computeRoutes(sw *ObjectStatusWriter, proxy *projcontour.HTTPP
 		delegate, ok := b.Source.httpproxies[Meta{name: include.Name, namespace: namespace}]
 		if !ok {
 			sw.SetInvalid(fmt.Sprintf("include %s/%s not found", namespace, include.Name))
-			return nil
+			continue
 		}
 		if delegate.Spec.VirtualHost != nil {
 			sw.SetInvalid("root httpproxy cannot delegate to another root httpproxy")
-			return nil
+			continue
 		}
 
 		if !pathConditionsValid(sw, include.Conditions, "include") {
-			return nil
+			continue
 		}
 
 		sw, commit := b.WithObject(delegate)
@@ -670,121 +823,217 @@ func (b *Builder) computeRoutes(sw *ObjectStatusWriter, proxy *projcontour.HTTPP
 		delete(b.orphaned, Meta{name: delegate.Name, namespace: delegate.Namespace})
 	}
 
+	// each route is validated in turn so that every problem
+	// with the HTTPProxy is reported, not just the first.
 	for _, route := range proxy.Spec.Routes {
-		if len(route.Services) > 1 && route.EnableWebsockets {
-			sw.SetInvalid("route: cannot specify multiple services and enable websockets")
-			continue
+		if r := b.computeRoute(sw, proxy, route, conditions, enforceTLS); r != nil {
+			routes = append(routes, r)
 		}
+	}
 
-		if !pathConditionsValid(sw, route.Conditions, "route") {
-			return nil
-		}
+	if sw.IsInvalid() {
+		return nil
+	}
 
-		conds := append(conditions, route.Conditions...)
+	routes = expandPrefixMatches(routes)
+
+	sw.SetValid()
+	return routes
+}
+
+// computeRoute returns the Route for route, or nil if route is invalid.
+func (b *Builder) computeRoute(sw *ObjectStatusWriter, proxy *projcontour.HTTPProxy, route projcontour.Route, conditions []projcontour.Condition, enforceTLS bool) *Route {
+	if len(route.Services) > 1 && route.EnableWebsockets {
+		// the route is dropped but the rest of the HTTPProxy is still served.
+		sw.AddWarning("route: cannot specify multiple services and enable websockets")
+		return nil
+	}
+
+	if !pathConditionsValid(sw, route.Conditions, "route") {
+		return nil
+	}
+
+	conds := append(conditions, route.Conditions...)
+
+	// Look for duplicate exact match headers on this route
+	if !headerConditionsAreValid(conds) {
+		sw.SetInvalid("cannot specify duplicate header 'exact match' conditions in the same route")
+		return nil
+	}
 
-		// Look for duplicate exact match headers on this route
-		if !headerConditionsAreValid(conds) {
-			sw.SetInvalid("cannot specify duplicate header 'exact match' conditions in the same route")
+	r := &Route{
+		PathCondition:    mergePathConditions(conds),
+		HeaderConditions: mergeHeaderConditions(conds),
+		Websocket:        route.EnableWebsockets,
+		HTTPSUpgrade:     routeEnforceTLS(enforceTLS, route.PermitInsecure && !b.DisablePermitInsecure),
+		TimeoutPolicy:    timeoutPolicy(route.TimeoutPolicy),
+		RetryPolicy:      retryPolicy(route.RetryPolicy),
+	}
+
+	if len(route.GetPrefixReplacements()) > 0 {
+		if !r.HasPathPrefix() {
+			sw.SetInvalid("cannot specify prefix replacements without a prefix condition")
 			return nil
 		}
 
-		r := &Route{
-			PathCondition:    mergePathConditions(conds),
-			HeaderConditions: mergeHeaderConditions(conds),
-			Websocket:        route.EnableWebsockets,
-			HTTPSUpgrade:     routeEnforceTLS(enforceTLS, route.PermitInsecure && !b.DisablePermitInsecure),
-			TimeoutPolicy:    timeoutPolicy(route.TimeoutPolicy),
-			RetryPolicy:      retryPolicy(route.RetryPolicy),
+		if err := prefixReplacementsAreValid(route.GetPrefixReplacements()); err != nil {
+			sw.SetInvalid(err.Error())
+			return nil
 		}
 
-		if len(route.GetPrefixReplacements()) > 0 {
-			if !r.HasPathPrefix() {
-				sw.SetInvalid("cannot specify prefix replacements without a prefix condition")
-				return nil
-			}
+		// Note that we are guaranteed to always have a prefix
+		// condition. Even if the CRD user didn't specify a
+		// prefix condition, mergePathConditions() guarantees
+		// a prefix of '/'.
+		routingPrefix := r.PathCondition.(*PrefixCondition).Prefix
 
-			if err := prefixReplacementsAreValid(route.GetPrefixReplacements()); err != nil {
-				sw.SetInvalid(err.Error())
-				return nil
+		// First, try to apply an exact prefix match.
+		for _, prefix := range route.GetPrefixReplacements() {
+			if len(prefix.Prefix) > 0 && routingPrefix == prefix.Prefix {
+				r.PrefixRewrite = prefix.Replacement
+				break
 			}
+		}
 
-			// Note that we are guaranteed to always have a prefix
-			// condition. Even if the CRD user didn't specify a
-			// prefix condition, mergePathConditions() guarantees
-			// a prefix of '/'.
-			routingPrefix := r.PathCondition.(*PrefixCondition).Prefix
-
-			// First, try to apply an exact prefix match.
+		// If there wasn't a match, we can apply the default replacement.
+		if len(r.PrefixRewrite) == 0 {
 			for _, prefix := range route.GetPrefixReplacements() {
-				if len(prefix.Prefix) > 0 && routingPrefix == prefix.Prefix {
+				if len(prefix.Prefix) == 0 {
 					r.PrefixRewrite = prefix.Replacement
 					break
 				}
 			}
+		}
 
-			// If there wasn't a match, we can apply the default replacement.
-			if len(r.PrefixRewrite) == 0 {
-				for _, prefix := range route.GetPrefixReplacements() {
-					if len(prefix.Prefix) == 0 {
-						r.PrefixRewrite = prefix.Replacement
-						break
-					}
-				}
-			}
+	}
 
-		}
+	hc, err := healthCheckPolicy(route.HealthCheckPolicy)
+	if err != nil {
+		sw.SetInvalid(err.Error())
+		return nil
+	}
 
-		for _, service := range route.Services {
-			if service.Port < 1 || service.Port > 65535 {
-				sw.SetInvalid(fmt.Sprintf("service %q: port must be in the range 1-65535", service.Name))
+	for _, service := range route.Services {
+		if service.Port < 1 || service.Port > 65535 {
+			sw.SetInvalid(fmt.Sprintf("service %q: port must be in the range 1-65535", service.Name))
+			return nil
+		}
+		var s *Service
+		validation := service.UpstreamValidation
+		switch service.Kind {
+		case "", "Service":
+			m := Meta{name: service.Name, namespace: serviceNamespace(proxy, service)}
+			if !b.serviceDelegationPermitted(m, proxy.Namespace) {
+				sw.SetInvalid(fmt.Sprintf("Service [%s/%s:%d]: delegation not permitted", m.namespace, service.Name, service.Port))
 				return nil
 			}
-			m := Meta{name: service.Name, namespace: proxy.Namespace}
-			s := b.lookupService(m, intstr.FromInt(service.Port))
+			s = b.lookupService(m, intstr.FromInt(service.Port))
 
 			if s == nil {
 				msg := fmt.Sprintf("Service [%s:%d] is invalid or missing", service.Name, service.Port)
 				sw.SetInvalid(msg)
 				return nil
 			}
-
-			var uv *UpstreamValidation
-			var err error
-			if s.Protocol == "tls" {
-				// we can only validate TLS connections to services that talk TLS
-				uv, err = b.lookupUpstreamValidation("??", service.Name, service.UpstreamValidation, proxy.Namespace)
-				if err != nil {
-					sw.SetInvalid(err.Error())
-					return nil
-				}
-			}
-
-			c := &Cluster{
-				Upstream:           s,
-				LoadBalancerPolicy: loadBalancerPolicy(route.LoadBalancerPolicy),
-				Weight:             service.Weight,
-				HealthCheckPolicy:  healthCheckPolicy(route.HealthCheckPolicy),
-				UpstreamValidation: uv,
+		case "Upstream":
+			if serviceNamespace(proxy, service) != proxy.Namespace {
+				sw.SetInvalid(fmt.Sprintf("service %q: upstreams must be in the HTTPProxy's namespace", service.Name))
+				return nil
 			}
-			if service.Mirror && r.MirrorPolicy != nil {
-				sw.SetInvalid("only one service per route may be nominated as mirror")
+			m := Meta{name: service.Name, namespace: proxy.Namespace}
+			s, err = b.lookupUpstream(m, service.Port)
+			if err != nil {
+				sw.SetInvalid(fmt.Sprintf("service %q: %s", service.Name, err))
 				return nil
 			}
-			if service.Mirror {
-				r.MirrorPolicy = &MirrorPolicy{
-					Cluster: c,
-				}
-			} else {
-				r.Clusters = append(r.Clusters, c)
+			if tls := b.Source.upstreams[m].Spec.TLS; validation == nil && tls != nil {
+				validation = tls.Validation
 			}
+		default:
+			sw.SetInvalid(fmt.Sprintf("service %q: unsupported kind %q", service.Name, service.Kind))
+			return nil
 		}
-		routes = append(routes, r)
-	}
 
-	routes = expandPrefixMatches(routes)
+		protocol := s.Protocol
+		switch service.Protocol {
+		case "":
+		case "h1", "h2", "h2c", "tls":
+			protocol = service.Protocol
+		default:
+			sw.SetInvalid(fmt.Sprintf("service %q: unsupported protocol %q", service.Name, service.Protocol))
+			return nil
+		}
 
-	sw.SetValid()
-	return routes
+		if hc != nil && hc.Protocol == "grpc" && !isHTTP2(protocol) {
+			sw.SetInvalid(fmt.Sprintf("service %q: grpc health checks require the h2 or h2c protocol", service.Name))
+			return nil
+		}
+
+		h2, err := http2Settings(service.HTTP2)
+		if err != nil {
+			sw.SetInvalid(fmt.Sprintf("service %q: %s", service.Name, err))
+			return nil
+		}
+		if h2 != nil && !isHTTP2(protocol) {
+			sw.SetInvalid(fmt.Sprintf("service %q: http2 settings require the h2 or h2c protocol", service.Name))
+			return nil
+		}
+
+		var uv *UpstreamValidation
+		if isTLS(protocol) {
+			// we can only validate TLS connections to services that talk TLS
+			uv, err = b.lookupUpstreamValidation("??", service.Name, validation, proxy.Namespace)
+			if err != nil {
+				sw.SetInvalid(err.Error())
+				return nil
+			}
+		}
+
+		policy := service.OutlierDetection
+		if policy == nil {
+			policy = route.OutlierDetection
+		}
+		od, err := outlierDetection(policy)
+		if err != nil {
+			sw.SetInvalid(fmt.Sprintf("service %q: %s", service.Name, err))
+			return nil
+		}
+
+		timeouts := service.ConnectionTimeoutPolicy
+		if timeouts == nil {
+			timeouts = route.ConnectionTimeoutPolicy
+		}
+		tp, err := connectionTimeoutPolicy(timeouts)
+		if err != nil {
+			sw.SetInvalid(fmt.Sprintf("service %q: %s", service.Name, err))
+			return nil
+		}
+
+		c := &Cluster{
+			Upstream:                s,
+			LoadBalancerPolicy:      loadBalancerPolicy(route.LoadBalancerPolicy),
+			LocalityPolicy:          localityPolicy(route.LoadBalancerPolicy),
+			Weight:                  service.Weight,
+			Protocol:                service.Protocol,
+			HTTP2Settings:           h2,
+			HealthCheckPolicy:       hc,
+			UpstreamValidation:      uv,
+			OutlierDetection:        od,
+			CircuitBreakerPolicy:    circuitBreakerPolicy(service.CircuitBreakerPolicy),
+			ConnectionTimeoutPolicy: tp,
+		}
+		if service.Mirror && r.MirrorPolicy != nil {
+			sw.SetInvalid("only one service per route may be nominated as mirror")
+			return nil
+		}
+		if service.Mirror {
+			r.MirrorPolicy = &MirrorPolicy{
+				Cluster: c,
+			}
+		} else {
+			r.Clusters = append(r.Clusters, c)
+		}
+	}
+	return r
 }
 
 func includeConditionsIdentical(includes []projcontour.Include) bool {
@@ -807,14 +1056,19 @@ func includeConditionsIdentical(includes []projcontour.Include) bool {
 func (b *Builder) buildDAG() *DAG {
 	var dag DAG
 
-	http := b.buildHTTPListener()
-	if len(http.VirtualHosts) > 0 {
-		dag.roots = append(dag.roots, http)
-	}
+	// the default Listeners come first, followed by those
+	// of each ListenerClass.
+	classes := append([]string{""}, b.ListenerClasses...)
+	for _, class := range classes {
+		http := b.buildHTTPListener(class)
+		if len(http.VirtualHosts) > 0 {
+			dag.roots = append(dag.roots, http)
+		}
 
-	https := b.buildHTTPSListener()
-	if len(https.VirtualHosts) > 0 {
-		dag.roots = append(dag.roots, https)
+		https := b.buildHTTPSListener(class)
+		if len(https.VirtualHosts) > 0 {
+			dag.roots = append(dag.roots, https)
+		}
 	}
 
 	for meta := range b.orphaned {
@@ -833,18 +1087,66 @@ func (b *Builder) buildDAG() *DAG {
 			commit()
 		}
 	}
+	b.addWarnings()
 	dag.statuses = b.statuses
+	dag.gatewayStatuses = b.gatewayStatuses
 	return &dag
 }
 
-// buildHTTPListener builds a *dag.Listener for the vhosts bound to port 80.
+// setConnectionTimeouts fills in the connection timeouts each
+// cluster of dag does not set from b.ConnectionTimeouts, so every
+// visitor of dag names the cluster the same way. Only the connect
+// timeout applies to the clusters of a TCPProxy.
+func (b *Builder) setConnectionTimeouts(dag *DAG) {
+	var visit func(v Vertex, tcp bool)
+	visit = func(v Vertex, tcp bool) {
+		switch v := v.(type) {
+		case *TCPProxy:
+			tcp = true
+		case *Route:
+			if v.MirrorPolicy != nil {
+				visit(v.MirrorPolicy.Cluster, tcp)
+			}
+		case *Cluster:
+			v.ConnectionTimeoutPolicy = connectionTimeouts(v.ConnectionTimeoutPolicy, b.ConnectionTimeouts, tcp)
+		}
+		v.Visit(func(child Vertex) {
+			visit(child, tcp)
+		})
+	}
+	dag.Visit(func(v Vertex) {
+		visit(v, false)
+	})
+}
+
+// addWarnings adds the Warnings recorded by the KubernetesCache
+// for each HTTPProxy to its status.
+func (b *Builder) addWarnings() {
+	for m, st := range b.statuses {
+		proxy, ok := st.Object.(*projcontour.HTTPProxy)
+		if !ok {
+			continue
+		}
+		var warnings []string
+		for _, w := range b.Source.warningsFor("HTTPProxy", toMeta(proxy)) {
+			warnings = append(warnings, w.Message)
+		}
+		if len(warnings) == 0 {
+			continue
+		}
+		st.Warnings = append(warnings, st.Warnings...)
+		b.statuses[m] = st
+	}
+}
+
+// buildHTTPListener builds a *dag.Listener for the vhosts of class bound to port 80.
 // The list of virtual hosts will attached to the listener will be sorted
 // by hostname.
-func (b *Builder) buildHTTPListener() *Listener {
+func (b *Builder) buildHTTPListener(class string) *Listener {
 	var virtualhosts = make([]Vertex, 0, len(b.virtualhosts))
 
-	for _, vh := range b.virtualhosts {
-		if vh.Valid() {
+	for host, vh := range b.virtualhosts {
+		if host.class == class && vh.Valid() {
 			virtualhosts = append(virtualhosts, vh)
 		}
 	}
@@ -853,17 +1155,18 @@ func (b *Builder) buildHTTPListener() *Listener {
 	})
 	return &Listener{
 		Port:         80,
+		IngressClass: class,
 		VirtualHosts: virtualhosts,
 	}
 }
 
-// buildHTTPSListener builds a *dag.Listener for the vhosts bound to port 443.
+// buildHTTPSListener builds a *dag.Listener for the vhosts of class bound to port 443.
 // The list of virtual hosts will attached to the listener will be sorted
 // by hostname.
-func (b *Builder) buildHTTPSListener() *Listener {
+func (b *Builder) buildHTTPSListener(class string) *Listener {
 	var virtualhosts = make([]Vertex, 0, len(b.securevirtualhosts))
-	for _, svh := range b.securevirtualhosts {
-		if svh.Valid() {
+	for host, svh := range b.securevirtualhosts {
+		if host.class == class && svh.Valid() {
 			virtualhosts = append(virtualhosts, svh)
 		}
 	}
@@ -872,6 +1175,7 @@ func (b *Builder) buildHTTPSListener() *Listener {
 	})
 	return &Listener{
 		Port:         443,
+		IngressClass: class,
 		VirtualHosts: virtualhosts,
 	}
 }
@@ -898,7 +1202,7 @@ func (b *Builder) rootAllowed(namespace string) bool {
 	return false
 }
 
-func (b *Builder) processIngressRoutes(sw *ObjectStatusWriter, ir *ingressroutev1.IngressRoute, prefixMatch string, visited []*ingressroutev1.IngressRoute, host string, enforceTLS bool) {
+func (b *Builder) processIngressRoutes(sw *ObjectStatusWriter, ir *ingressroutev1.IngressRoute, prefixMatch string, visited []*ingressroutev1.IngressRoute, host listenerHost, enforceTLS bool) {
 	visited = append(visited, ir)
 
 	for _, route := range ir.Spec.Routes {
@@ -945,7 +1249,7 @@ func (b *Builder) processIngressRoutes(sw *ObjectStatusWriter, ir *ingressroutev
 
 				var uv *UpstreamValidation
 				var err error
-				if s.Protocol == "tls" {
+				if isTLS(s.Protocol) {
 					// we can only validate TLS connections to services that talk TLS
 					uv, err = b.lookupUpstreamValidation(route.Match, service.Name, service.UpstreamValidation, ir.Namespace)
 					if err != nil {
@@ -1034,7 +1338,7 @@ func (b *Builder) lookupUpstreamValidation(match string, serviceName string, uv
 	}, nil
 }
 
-func (b *Builder) processIngressRouteTCPProxy(sw *ObjectStatusWriter, ir *ingressroutev1.IngressRoute, visited []*ingressroutev1.IngressRoute, host string) {
+func (b *Builder) processIngressRouteTCPProxy(sw *ObjectStatusWriter, ir *ingressroutev1.IngressRoute, visited []*ingressroutev1.IngressRoute, host listenerHost) {
 	visited = append(visited, ir)
 
 	// tcpproxy cannot both delegate and point to services
@@ -1104,7 +1408,7 @@ func (b *Builder) processIngressRouteTCPProxy(sw *ObjectStatusWriter, ir *ingres
 // following the chain of spec.tcpproxy.include references. It returns true if processing
 // was successful, otherwise false if an error was encountered. The details of the error
 // will be recorded on the status of the relevant HTTPProxy object,
-func (b *Builder) processHTTPProxyTCPProxy(sw *ObjectStatusWriter, httpproxy *projcontour.HTTPProxy, visited []*projcontour.HTTPProxy, host string) bool {
+func (b *Builder) processHTTPProxyTCPProxy(sw *ObjectStatusWriter, httpproxy *projcontour.HTTPProxy, visited []*projcontour.HTTPProxy, host listenerHost) bool {
 	tcpproxy := httpproxy.Spec.TCPProxy
 	if tcpproxy == nil {
 		// nothing to do
@@ -1121,15 +1425,81 @@ func (b *Builder) processHTTPProxyTCPProxy(sw *ObjectStatusWriter, httpproxy *pr
 	if len(tcpproxy.Services) > 0 {
 		var proxy TCPProxy
 		for _, service := range httpproxy.Spec.TCPProxy.Services {
-			m := Meta{name: service.Name, namespace: httpproxy.Namespace}
-			s := b.lookupService(m, intstr.FromInt(service.Port))
-			if s == nil {
-				sw.SetInvalid(fmt.Sprintf("tcpproxy: service %s/%s/%d: not found", httpproxy.Namespace, service.Name, service.Port))
+			m := Meta{name: service.Name, namespace: serviceNamespace(httpproxy, service)}
+			var s *Service
+			validation := service.UpstreamValidation
+			switch service.Kind {
+			case "", "Service":
+				if !b.serviceDelegationPermitted(m, httpproxy.Namespace) {
+					sw.SetInvalid(fmt.Sprintf("tcpproxy: service %s/%s/%d: delegation not permitted", m.namespace, service.Name, service.Port))
+					return false
+				}
+				s = b.lookupService(m, intstr.FromInt(service.Port))
+				if s == nil {
+					sw.SetInvalid(fmt.Sprintf("tcpproxy: service %s/%s/%d: not found", m.namespace, service.Name, service.Port))
+					return false
+				}
+			case "Upstream":
+				if m.namespace != httpproxy.Namespace {
+					sw.SetInvalid(fmt.Sprintf("tcpproxy: service %s/%s/%d: upstreams must be in the HTTPProxy's namespace", m.namespace, service.Name, service.Port))
+					return false
+				}
+				var err error
+				s, err = b.lookupUpstream(m, service.Port)
+				if err != nil {
+					sw.SetInvalid(fmt.Sprintf("tcpproxy: service %s/%s/%d: %s", m.namespace, service.Name, service.Port, err))
+					return false
+				}
+				if tls := b.Source.upstreams[m].Spec.TLS; validation == nil && tls != nil {
+					validation = tls.Validation
+				}
+			default:
+				sw.SetInvalid(fmt.Sprintf("tcpproxy: service %s/%s/%d: unsupported kind %q", m.namespace, service.Name, service.Port, service.Kind))
+				return false
+			}
+			switch service.Protocol {
+			case "", "h1", "tls":
+			default:
+				sw.SetInvalid(fmt.Sprintf("tcpproxy: service %s/%s/%d: unsupported protocol %q", m.namespace, service.Name, service.Port, service.Protocol))
+				return false
+			}
+			if service.HTTP2 != nil {
+				sw.SetInvalid(fmt.Sprintf("tcpproxy: service %s/%s/%d: http2 settings are not supported", m.namespace, service.Name, service.Port))
+				return false
+			}
+			od, err := outlierDetection(service.OutlierDetection)
+			if err != nil {
+				sw.SetInvalid(fmt.Sprintf("tcpproxy: service %s/%s/%d: %s", m.namespace, service.Name, service.Port, err))
 				return false
 			}
+			tp, err := connectionTimeoutPolicy(service.ConnectionTimeoutPolicy)
+			if err != nil {
+				sw.SetInvalid(fmt.Sprintf("tcpproxy: service %s/%s/%d: %s", m.namespace, service.Name, service.Port, err))
+				return false
+			}
+			protocol := service.Protocol
+			if protocol == "" {
+				protocol = s.Protocol
+			}
+			var uv *UpstreamValidation
+			if isTLS(protocol) {
+				// we can only validate TLS connections to services that talk TLS
+				uv, err = b.lookupUpstreamValidation("tcpproxy", service.Name, validation, httpproxy.Namespace)
+				if err != nil {
+					sw.SetInvalid(err.Error())
+					return false
+				}
+			}
 			proxy.Clusters = append(proxy.Clusters, &Cluster{
-				Upstream:           s,
-				LoadBalancerPolicy: loadBalancerPolicy(tcpproxy.LoadBalancerPolicy),
+				Upstream:                s,
+				LoadBalancerPolicy:      loadBalancerPolicy(tcpproxy.LoadBalancerPolicy),
+				LocalityPolicy:          localityPolicy(tcpproxy.LoadBalancerPolicy),
+				Protocol:                service.Protocol,
+				HealthCheckPolicy:       tcpHealthCheckPolicy(tcpproxy.HealthCheckPolicy),
+				OutlierDetection:        od,
+				CircuitBreakerPolicy:    circuitBreakerPolicy(service.CircuitBreakerPolicy),
+				ConnectionTimeoutPolicy: tp,
+				UpstreamValidation:      uv,
 			})
 		}
 		b.lookupSecureVirtualHost(host).TCPProxy = &proxy
@@ -2118,6 +2118,30 @@ func TestDAGInsert(t *testing.T) {
 		},
 	}
 
+	// proxy17h2 expects verification of an h2 upstream
+	proxy17h2 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example-com",
+			Namespace: "default",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name:     "kuard",
+					Port:     8080,
+					Protocol: "h2",
+					UpstreamValidation: &projcontour.UpstreamValidation{
+						CACertificate: cert1.Name,
+						SubjectName:   "example.com",
+					},
+				}},
+			}},
+		},
+	}
+
 	// proxy10 has a websocket route
 	proxy10 := &projcontour.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
@@ -2785,6 +2809,85 @@ func TestDAGInsert(t *testing.T) {
 		},
 	}
 
+	// upstream1 is a set of legacy hosts reached over TLS.
+	upstream1 := &projcontour.Upstream{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "legacy",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.UpstreamSpec{
+			Endpoints: []projcontour.UpstreamEndpoint{{
+				Address: "legacy1.example.com",
+			}, {
+				Address: "legacy2.example.com",
+				Port:    9443,
+			}},
+			TLS: &projcontour.UpstreamTLS{},
+		},
+	}
+
+	// proxy109 routes to upstream1.
+	proxy109 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "legacy",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: upstream1.Name,
+					Kind: "Upstream",
+					Port: 8443,
+				}},
+			}},
+		},
+	}
+
+	// upstream2 is a legacy host reached over TLS, verified by cert1.
+	upstream2 := &projcontour.Upstream{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "legacy-verified",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.UpstreamSpec{
+			Endpoints: []projcontour.UpstreamEndpoint{{
+				Address: "legacy1.example.com",
+			}},
+			TLS: &projcontour.UpstreamTLS{
+				Validation: &projcontour.UpstreamValidation{
+					CACertificate: cert1.Name,
+					SubjectName:   "legacy1.example.com",
+				},
+			},
+		},
+	}
+
+	// proxy110 tcp forwards to upstream2.
+	proxy110 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "legacy-tcp",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &projcontour.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &projcontour.TCPProxy{
+				Services: []projcontour.Service{{
+					Name: upstream2.Name,
+					Kind: "Upstream",
+					Port: 8443,
+				}},
+			},
+		},
+	}
+
 	proxy108a := &projcontour.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "blogteama",
@@ -3317,6 +3420,72 @@ func TestDAGInsert(t *testing.T) {
 				},
 			),
 		},
+		"insert httpproxy w/ upstream service": {
+			objs: []interface{}{
+				upstream1, proxy109,
+			},
+			want: listeners(
+				&Listener{
+					Port: 80,
+					VirtualHosts: virtualhosts(
+						virtualhost("example.com",
+							prefixroute("/", &Service{
+								Name:      upstream1.Name,
+								Namespace: upstream1.Namespace,
+								ServicePort: &v1.ServicePort{
+									Protocol: "TCP",
+									Port:     8443,
+								},
+								Protocol: "tls",
+								SNI:      "legacy1.example.com",
+								StaticEndpoints: []StaticEndpoint{
+									{Address: "legacy1.example.com", Port: 8443},
+									{Address: "legacy2.example.com", Port: 9443},
+								},
+							}),
+						),
+					),
+				},
+			),
+		},
+		"insert httpproxy w/ tcpproxy to a verified upstream": {
+			objs: []interface{}{
+				cert1, upstream2, proxy110,
+			},
+			want: listeners(
+				&Listener{
+					Port: 443,
+					VirtualHosts: virtualhosts(
+						&SecureVirtualHost{
+							VirtualHost: VirtualHost{
+								Name: "example.com",
+							},
+							TCPProxy: &TCPProxy{
+								Clusters: []*Cluster{{
+									Upstream: &Service{
+										Name:      upstream2.Name,
+										Namespace: upstream2.Namespace,
+										ServicePort: &v1.ServicePort{
+											Protocol: "TCP",
+											Port:     8443,
+										},
+										Protocol: "tls",
+										SNI:      "legacy1.example.com",
+										StaticEndpoints: []StaticEndpoint{
+											{Address: "legacy1.example.com", Port: 8443},
+										},
+									},
+									UpstreamValidation: &UpstreamValidation{
+										CACertificate: secret(cert1),
+										SubjectName:   "legacy1.example.com",
+									},
+								}},
+							},
+						},
+					),
+				},
+			),
+		},
 		"insert ingressroute w/ healthcheck": {
 			objs: []interface{}{
 				ir1e, s1,
@@ -4868,6 +5037,30 @@ func TestDAGInsert(t *testing.T) {
 				},
 			),
 		},
+		"insert httpproxy expecting verification of an h2 upstream": {
+			objs: []interface{}{
+				cert1, proxy17h2, s1,
+			},
+			want: listeners(
+				&Listener{
+					Port: 80,
+					VirtualHosts: virtualhosts(
+						virtualhost("example.com",
+							routeCluster("/",
+								&Cluster{
+									Upstream: service(s1),
+									Protocol: "h2",
+									UpstreamValidation: &UpstreamValidation{
+										CACertificate: secret(cert1),
+										SubjectName:   "example.com",
+									},
+								},
+							),
+						),
+					),
+				},
+			),
+		},
 		"insert httpproxy with invalid tcpproxy": {
 			objs: []interface{}{proxy37, s1},
 			want: listeners(),
@@ -5819,6 +6012,139 @@ func TestDAGRootNamespaces(t *testing.T) {
 	}
 }
 
+func TestDAGListenerClasses(t *testing.T) {
+	proxy := func(name, class, fqdn string) *projcontour.HTTPProxy {
+		p := &projcontour.HTTPProxy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: projcontour.HTTPProxySpec{
+				VirtualHost: &projcontour.VirtualHost{
+					Fqdn: fqdn,
+				},
+				Routes: []projcontour.Route{{
+					Services: []projcontour.Service{{
+						Name: "kuard",
+						Port: 8080,
+					}},
+				}},
+			},
+		}
+		if class != "" {
+			p.Annotations = map[string]string{
+				"projectcontour.io/ingress.class": class,
+			}
+		}
+		return p
+	}
+
+	s1 := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Protocol: "TCP",
+				Port:     8080,
+			}},
+		},
+	}
+
+	type listener struct {
+		class string
+		port  int
+	}
+
+	tests := map[string]struct {
+		listenerClasses []string
+		objs            []interface{}
+		want            map[listener][]string
+	}{
+		"no listener classes": {
+			objs: []interface{}{
+				s1,
+				proxy("external", "external", "external.example.com"),
+				proxy("internal", "internal", "internal.example.com"),
+				proxy("unannotated", "", "example.com"),
+			},
+			want: map[listener][]string{
+				{port: 80}: {"example.com", "external.example.com", "internal.example.com"},
+			},
+		},
+		"internal listener class": {
+			listenerClasses: []string{"internal"},
+			objs: []interface{}{
+				s1,
+				proxy("external", "external", "external.example.com"),
+				proxy("internal", "internal", "internal.example.com"),
+				proxy("unannotated", "", "example.com"),
+			},
+			want: map[listener][]string{
+				{port: 80}:                    {"example.com", "external.example.com"},
+				{class: "internal", port: 80}: {"internal.example.com"},
+			},
+		},
+		"same fqdn on different listeners": {
+			listenerClasses: []string{"internal"},
+			objs: []interface{}{
+				s1,
+				proxy("external", "external", "example.com"),
+				proxy("internal", "internal", "example.com"),
+			},
+			want: map[listener][]string{
+				{port: 80}:                    {"example.com"},
+				{class: "internal", port: 80}: {"example.com"},
+			},
+		},
+		"same fqdn on the default listeners": {
+			listenerClasses: []string{"internal"},
+			objs: []interface{}{
+				s1,
+				proxy("external", "external", "example.com"),
+				proxy("unannotated", "", "example.com"),
+			},
+			want: map[listener][]string{},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := Builder{
+				Source: KubernetesCache{
+					IngressClasses: []string{"external", "internal"},
+					FieldLogger:    testLogger(t),
+				},
+				ListenerClasses: tc.listenerClasses,
+			}
+
+			for _, o := range tc.objs {
+				builder.Source.Insert(o)
+			}
+			dag := builder.Build()
+
+			got := make(map[listener][]string)
+			dag.Visit(func(v Vertex) {
+				l, ok := v.(*Listener)
+				if !ok {
+					return
+				}
+				key := listener{class: l.IngressClass, port: l.Port}
+				l.Visit(func(v Vertex) {
+					if vh, ok := v.(*VirtualHost); ok {
+						got[key] = append(got[key], vh.Name)
+					}
+				})
+			})
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
 func TestMatchesPathPrefix(t *testing.T) {
 	tests := map[string]struct {
 		path    string
@@ -16,6 +16,8 @@ package dag
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
+	"sort"
 
 	"github.com/projectcontour/contour/internal/k8s"
 
@@ -25,6 +27,7 @@ import (
 	"k8s.io/client-go/tools/cache"
 
 	ingressroutev1 "github.com/projectcontour/contour/apis/contour/v1beta1"
+	gatewayapi "github.com/projectcontour/contour/apis/networking/v1alpha1"
 	projectcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
 	"github.com/sirupsen/logrus"
 )
@@ -39,9 +42,14 @@ type KubernetesCache struct {
 	// namespace.
 	RootNamespaces []string
 
-	// Contour's IngressClass.
+	// IngressClasses are the ingress classes Contour serves.
 	// If not set, defaults to DEFAULT_INGRESS_CLASS.
-	IngressClass string
+	IngressClasses []string
+
+	// GatewayController is the controller name of the Gateway API
+	// GatewayClasses Contour implements. If not set, Gateway API
+	// objects are ignored.
+	GatewayController string
 
 	ingresses            map[Meta]*v1beta1.Ingress
 	ingressroutes        map[Meta]*ingressroutev1.IngressRoute
@@ -49,11 +57,52 @@ type KubernetesCache struct {
 	secrets              map[Meta]*v1.Secret
 	irdelegations        map[Meta]*ingressroutev1.TLSCertificateDelegation
 	httpproxydelegations map[Meta]*projectcontour.TLSCertificateDelegation
+	servicedelegations   map[Meta]*projectcontour.ServiceDelegation
+	upstreams            map[Meta]*projectcontour.Upstream
 	services             map[Meta]*v1.Service
+	gatewayclasses       map[Meta]*gatewayapi.GatewayClass
+	gateways             map[Meta]*gatewayapi.Gateway
+	httproutes           map[Meta]*gatewayapi.HTTPRoute
+
+	// warnings holds the problems found with each object
+	// when it was last inserted.
+	warnings map[objectKey][]Warning
 
 	logrus.FieldLogger
 }
 
+// Warning is a problem found with an object when it was inserted
+// into the KubernetesCache which did not prevent its insertion, or
+// which caused it to be ignored without affecting other objects.
+type Warning struct {
+	Object Object
+
+	// Reason is a CamelCase reason for the warning, suitable
+	// for use as the reason of a Kubernetes Event.
+	Reason string
+
+	Message string
+}
+
+// objectKey identifies an object of any kind in the KubernetesCache.
+type objectKey struct {
+	kind string
+	Meta
+}
+
+// sortObjectKeys sorts keys by kind, namespace, and name.
+func sortObjectKeys(keys []objectKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].kind != keys[j].kind {
+			return keys[i].kind < keys[j].kind
+		}
+		if keys[i].namespace != keys[j].namespace {
+			return keys[i].namespace < keys[j].namespace
+		}
+		return keys[i].name < keys[j].name
+	})
+}
+
 // Meta holds the name and namespace of a Kubernetes object.
 type Meta struct {
 	name, namespace string
@@ -74,6 +123,7 @@ func toMeta(obj Object) Meta {
 func (kc *KubernetesCache) Insert(obj interface{}) bool {
 	if obj, ok := obj.(Object); ok {
 		kind := k8s.KindOf(obj)
+		delete(kc.warnings, objectKey{kind: kind, Meta: toMeta(obj)})
 		for key := range obj.GetObjectMeta().GetAnnotations() {
 			// Emit a warning if this is a known annotation that has
 			// been applied to an invalid object kind. Note that we
@@ -81,8 +131,6 @@ func (kc *KubernetesCache) Insert(obj interface{}) bool {
 			// allow users to add arbitrary orthogonal annotations
 			// to object that we inspect.
 			if annotationIsKnown(key) && !validAnnotationForKind(kind, key) {
-				// TODO(jpeach): this should be exposed
-				// to the user as a status condition.
 				om := obj.GetObjectMeta()
 				kc.WithField("name", om.GetName()).
 					WithField("namespace", om.GetNamespace()).
@@ -90,6 +138,7 @@ func (kc *KubernetesCache) Insert(obj interface{}) bool {
 					WithField("version", "v1").
 					WithField("annotation", key).
 					Error("ignoring invalid or unsupported annotation")
+				kc.warn(obj, "IgnoredAnnotation", fmt.Sprintf("ignoring invalid or unsupported annotation %q", key))
 			}
 		}
 	}
@@ -105,6 +154,13 @@ func (kc *KubernetesCache) Insert(obj interface{}) bool {
 					WithField("kind", "Secret").
 					WithField("version", "v1").
 					Error(err)
+				kc.warn(obj, "InvalidSecret", err.Error())
+
+				// rebuild if the certificate is in use so
+				// its users can report why it was rejected.
+				if obj.Type == v1.SecretTypeTLS {
+					return kc.secretTriggersRebuild(obj)
+				}
 			}
 			return false
 		}
@@ -123,8 +179,7 @@ func (kc *KubernetesCache) Insert(obj interface{}) bool {
 		kc.services[m] = obj
 		return kc.serviceTriggersRebuild(obj)
 	case *v1beta1.Ingress:
-		class := ingressClass(obj)
-		if class != "" && class != kc.ingressClass() {
+		if !MatchesIngressClass(obj, kc.IngressClasses...) {
 			return false
 		}
 		m := toMeta(obj)
@@ -144,8 +199,7 @@ func (kc *KubernetesCache) Insert(obj interface{}) bool {
 		}
 		return kc.Insert(ingress)
 	case *ingressroutev1.IngressRoute:
-		class := ingressClass(obj)
-		if class != "" && class != kc.ingressClass() {
+		if !MatchesIngressClass(obj, kc.IngressClasses...) {
 			return false
 		}
 		m := toMeta(obj)
@@ -155,8 +209,7 @@ func (kc *KubernetesCache) Insert(obj interface{}) bool {
 		kc.ingressroutes[m] = obj
 		return true
 	case *projectcontour.HTTPProxy:
-		class := ingressClass(obj)
-		if class != "" && class != kc.ingressClass() {
+		if !MatchesIngressClass(obj, kc.IngressClasses...) {
 			return false
 		}
 		m := toMeta(obj)
@@ -179,6 +232,56 @@ func (kc *KubernetesCache) Insert(obj interface{}) bool {
 		}
 		kc.httpproxydelegations[m] = obj
 		return true
+	case *projectcontour.ServiceDelegation:
+		m := toMeta(obj)
+		if kc.servicedelegations == nil {
+			kc.servicedelegations = make(map[Meta]*projectcontour.ServiceDelegation)
+		}
+		kc.servicedelegations[m] = obj
+		return true
+	case *projectcontour.Upstream:
+		m := toMeta(obj)
+		if kc.upstreams == nil {
+			kc.upstreams = make(map[Meta]*projectcontour.Upstream)
+		}
+		kc.upstreams[m] = obj
+		return true
+	case *gatewayapi.GatewayClass:
+		if kc.GatewayController == "" {
+			return false
+		}
+		m := toMeta(obj)
+		if obj.Spec.Controller != kc.GatewayController {
+			// the class may have been handed to another controller.
+			_, ok := kc.gatewayclasses[m]
+			delete(kc.gatewayclasses, m)
+			return ok
+		}
+		if kc.gatewayclasses == nil {
+			kc.gatewayclasses = make(map[Meta]*gatewayapi.GatewayClass)
+		}
+		kc.gatewayclasses[m] = obj
+		return true
+	case *gatewayapi.Gateway:
+		if kc.GatewayController == "" {
+			return false
+		}
+		m := toMeta(obj)
+		if kc.gateways == nil {
+			kc.gateways = make(map[Meta]*gatewayapi.Gateway)
+		}
+		kc.gateways[m] = obj
+		return true
+	case *gatewayapi.HTTPRoute:
+		if kc.GatewayController == "" {
+			return false
+		}
+		m := toMeta(obj)
+		if kc.httproutes == nil {
+			kc.httproutes = make(map[Meta]*gatewayapi.HTTPRoute)
+		}
+		kc.httproutes[m] = obj
+		return true
 
 	default:
 		// not an interesting object
@@ -187,10 +290,38 @@ func (kc *KubernetesCache) Insert(obj interface{}) bool {
 	}
 }
 
-// ingressClass returns the IngressClass
-// or DEFAULT_INGRESS_CLASS if not configured.
-func (kc *KubernetesCache) ingressClass() string {
-	return stringOrDefault(kc.IngressClass, DEFAULT_INGRESS_CLASS)
+// warn records a Warning for obj.
+func (kc *KubernetesCache) warn(obj Object, reason, msg string) {
+	if kc.warnings == nil {
+		kc.warnings = make(map[objectKey][]Warning)
+	}
+	key := objectKey{kind: k8s.KindOf(obj), Meta: toMeta(obj)}
+	kc.warnings[key] = append(kc.warnings[key], Warning{
+		Object:  obj,
+		Reason:  reason,
+		Message: msg,
+	})
+}
+
+// warningsFor returns the Warnings recorded for the named object of kind.
+func (kc *KubernetesCache) warningsFor(kind string, m Meta) []Warning {
+	return kc.warnings[objectKey{kind: kind, Meta: m}]
+}
+
+// Warnings returns the Warnings recorded for every object in the
+// KubernetesCache, ordered by kind, namespace, and name.
+func (kc *KubernetesCache) Warnings() []Warning {
+	keys := make([]objectKey, 0, len(kc.warnings))
+	for k := range kc.warnings {
+		keys = append(keys, k)
+	}
+	sortObjectKeys(keys)
+
+	var warnings []Warning
+	for _, k := range keys {
+		warnings = append(warnings, kc.warnings[k]...)
+	}
+	return warnings
 }
 
 // Remove removes obj from the KubernetesCache.
@@ -198,6 +329,9 @@ func (kc *KubernetesCache) ingressClass() string {
 func (kc *KubernetesCache) Remove(obj interface{}) bool {
 	switch obj := obj.(type) {
 	default:
+		if obj, ok := obj.(Object); ok {
+			delete(kc.warnings, objectKey{kind: k8s.KindOf(obj), Meta: toMeta(obj)})
+		}
 		return kc.remove(obj)
 	case cache.DeletedFinalStateUnknown:
 		return kc.Remove(obj.Obj) // recurse into ourselves with the tombstoned value
@@ -246,6 +380,31 @@ func (kc *KubernetesCache) remove(obj interface{}) bool {
 		_, ok := kc.httpproxydelegations[m]
 		delete(kc.httpproxydelegations, m)
 		return ok
+	case *projectcontour.ServiceDelegation:
+		m := toMeta(obj)
+		_, ok := kc.servicedelegations[m]
+		delete(kc.servicedelegations, m)
+		return ok
+	case *projectcontour.Upstream:
+		m := toMeta(obj)
+		_, ok := kc.upstreams[m]
+		delete(kc.upstreams, m)
+		return ok
+	case *gatewayapi.GatewayClass:
+		m := toMeta(obj)
+		_, ok := kc.gatewayclasses[m]
+		delete(kc.gatewayclasses, m)
+		return ok
+	case *gatewayapi.Gateway:
+		m := toMeta(obj)
+		_, ok := kc.gateways[m]
+		delete(kc.gateways, m)
+		return ok
+	case *gatewayapi.HTTPRoute:
+		m := toMeta(obj)
+		_, ok := kc.httproutes[m]
+		delete(kc.httproutes, m)
+		return ok
 	default:
 		// not interesting
 		kc.WithField("object", obj).Error("remove unknown object")
@@ -299,20 +458,34 @@ func (kc *KubernetesCache) serviceTriggersRebuild(service *v1.Service) bool {
 		}
 	}
 
-	for _, ir := range kc.httpproxies {
-		if ir.Namespace != service.Namespace {
-			continue
-		}
-		for _, route := range ir.Spec.Routes {
+	// HTTPProxy services may name a service in another namespace.
+	references := func(proxy *projectcontour.HTTPProxy, s projectcontour.Service) bool {
+		return s.Name == service.Name && serviceNamespace(proxy, s) == service.Namespace
+	}
+	for _, proxy := range kc.httpproxies {
+		for _, route := range proxy.Spec.Routes {
 			for _, s := range route.Services {
-				if s.Name == service.Name {
+				if references(proxy, s) {
 					return true
 				}
 			}
 		}
-		if tcpproxy := ir.Spec.TCPProxy; tcpproxy != nil {
+		if tcpproxy := proxy.Spec.TCPProxy; tcpproxy != nil {
 			for _, s := range tcpproxy.Services {
-				if s.Name == service.Name {
+				if references(proxy, s) {
+					return true
+				}
+			}
+		}
+	}
+
+	for _, route := range kc.httproutes {
+		if route.Namespace != service.Namespace {
+			continue
+		}
+		for _, rule := range route.Spec.Rules {
+			for _, fwd := range rule.ForwardTo {
+				if fwd.ServiceName == service.Name {
 					return true
 				}
 			}
@@ -432,6 +605,17 @@ func (kc *KubernetesCache) secretTriggersRebuild(secret *v1.Secret) bool {
 		}
 	}
 
+	for _, gateway := range kc.gateways {
+		if gateway.Namespace != secret.Namespace {
+			continue
+		}
+		for _, listener := range gateway.Spec.Listeners {
+			if tls := listener.TLS; tls != nil && tls.CertificateRef.Name == secret.Name {
+				return true
+			}
+		}
+	}
+
 	return false
 }
 
@@ -17,7 +17,9 @@ import (
 	"testing"
 
 	ingressroutev1 "github.com/projectcontour/contour/apis/contour/v1beta1"
+	gatewayapi "github.com/projectcontour/contour/apis/networking/v1alpha1"
 	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/assert"
 	"github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/api/networking/v1beta1"
@@ -26,9 +28,10 @@ import (
 
 func TestKubernetesCacheInsert(t *testing.T) {
 	tests := map[string]struct {
-		pre  []interface{}
-		obj  interface{}
-		want bool
+		gatewayController string
+		pre               []interface{}
+		obj               interface{}
+		want              bool
 	}{
 		"insert secret": {
 			obj: &v1.Secret{
@@ -585,7 +588,7 @@ func TestKubernetesCacheInsert(t *testing.T) {
 					Name:      "incorrect",
 					Namespace: "default",
 					Annotations: map[string]string{
-						"kubernetes.io/ingress.class": new(KubernetesCache).ingressClass(),
+						"kubernetes.io/ingress.class": DEFAULT_INGRESS_CLASS,
 					},
 				},
 			},
@@ -597,7 +600,7 @@ func TestKubernetesCacheInsert(t *testing.T) {
 					Name:      "incorrect",
 					Namespace: "default",
 					Annotations: map[string]string{
-						"contour.heptio.com/ingress.class": new(KubernetesCache).ingressClass(),
+						"contour.heptio.com/ingress.class": DEFAULT_INGRESS_CLASS,
 					},
 				},
 			},
@@ -642,7 +645,7 @@ func TestKubernetesCacheInsert(t *testing.T) {
 					Name:      "kuard",
 					Namespace: "default",
 					Annotations: map[string]string{
-						"contour.heptio.com/ingress.class": new(KubernetesCache).ingressClass(),
+						"contour.heptio.com/ingress.class": DEFAULT_INGRESS_CLASS,
 					},
 				},
 			},
@@ -654,7 +657,7 @@ func TestKubernetesCacheInsert(t *testing.T) {
 					Name:      "kuard",
 					Namespace: "default",
 					Annotations: map[string]string{
-						"kubernetes.io/ingress.class": new(KubernetesCache).ingressClass(),
+						"kubernetes.io/ingress.class": DEFAULT_INGRESS_CLASS,
 					},
 				},
 			},
@@ -699,7 +702,7 @@ func TestKubernetesCacheInsert(t *testing.T) {
 					Name:      "kuard",
 					Namespace: "default",
 					Annotations: map[string]string{
-						"contour.heptio.com/ingress.class": new(KubernetesCache).ingressClass(),
+						"contour.heptio.com/ingress.class": DEFAULT_INGRESS_CLASS,
 					},
 				},
 			},
@@ -711,7 +714,7 @@ func TestKubernetesCacheInsert(t *testing.T) {
 					Name:      "kuard",
 					Namespace: "default",
 					Annotations: map[string]string{
-						"kubernetes.io/ingress.class": new(KubernetesCache).ingressClass(),
+						"kubernetes.io/ingress.class": DEFAULT_INGRESS_CLASS,
 					},
 				},
 			},
@@ -735,6 +738,24 @@ func TestKubernetesCacheInsert(t *testing.T) {
 			},
 			want: true,
 		},
+		"insert service delegation": {
+			obj: &projcontour.ServiceDelegation{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "delegate",
+					Namespace: "default",
+				},
+			},
+			want: true,
+		},
+		"insert upstream": {
+			obj: &projcontour.Upstream{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "legacy",
+					Namespace: "default",
+				},
+			},
+			want: true,
+		},
 		"insert httpproxy": {
 			obj: &projcontour.HTTPProxy{
 				ObjectMeta: metav1.ObjectMeta{
@@ -897,12 +918,170 @@ func TestKubernetesCacheInsert(t *testing.T) {
 			},
 			want: true,
 		},
+		"insert service in another namespace referenced by httpproxy": {
+			pre: []interface{}{
+				&projcontour.HTTPProxy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "kuard",
+						Namespace: "default",
+					},
+					Spec: projcontour.HTTPProxySpec{
+						Routes: []projcontour.Route{{
+							Services: []projcontour.Service{{
+								Name:      "service",
+								Namespace: "platform",
+							}},
+						}},
+					},
+				},
+			},
+			obj: &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "service",
+					Namespace: "platform",
+				},
+			},
+			want: true,
+		},
+		"insert service in httpproxy namespace referenced in another namespace": {
+			pre: []interface{}{
+				&projcontour.HTTPProxy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "kuard",
+						Namespace: "default",
+					},
+					Spec: projcontour.HTTPProxySpec{
+						Routes: []projcontour.Route{{
+							Services: []projcontour.Service{{
+								Name:      "service",
+								Namespace: "platform",
+							}},
+						}},
+					},
+				},
+			},
+			obj: &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "service",
+					Namespace: "default",
+				},
+			},
+			want: false,
+		},
+		"insert gateway without a gateway controller": {
+			obj: &gatewayapi.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "gateway",
+					Namespace: "default",
+				},
+			},
+			want: false,
+		},
+		"insert gateway": {
+			gatewayController: "projectcontour.io/contour",
+			obj: &gatewayapi.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "gateway",
+					Namespace: "default",
+				},
+			},
+			want: true,
+		},
+		"insert gatewayclass of another controller": {
+			gatewayController: "projectcontour.io/contour",
+			obj: &gatewayapi.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "other",
+				},
+				Spec: gatewayapi.GatewayClassSpec{
+					Controller: "example.com/other",
+				},
+			},
+			want: false,
+		},
+		"insert gatewayclass handed to another controller": {
+			gatewayController: "projectcontour.io/contour",
+			pre: []interface{}{
+				&gatewayapi.GatewayClass{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "contour",
+					},
+					Spec: gatewayapi.GatewayClassSpec{
+						Controller: "projectcontour.io/contour",
+					},
+				},
+			},
+			obj: &gatewayapi.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "contour",
+				},
+				Spec: gatewayapi.GatewayClassSpec{
+					Controller: "example.com/other",
+				},
+			},
+			want: true,
+		},
+		"insert service referenced by httproute": {
+			gatewayController: "projectcontour.io/contour",
+			pre: []interface{}{
+				&gatewayapi.HTTPRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "route",
+						Namespace: "default",
+					},
+					Spec: gatewayapi.HTTPRouteSpec{
+						Rules: []gatewayapi.HTTPRouteRule{{
+							ForwardTo: []gatewayapi.HTTPRouteForwardTo{{
+								ServiceName: "service",
+								Port:        80,
+							}},
+						}},
+					},
+				},
+			},
+			obj: &v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "service",
+					Namespace: "default",
+				},
+			},
+			want: true,
+		},
+		"insert secret referenced by gateway": {
+			gatewayController: "projectcontour.io/contour",
+			pre: []interface{}{
+				&gatewayapi.Gateway{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "gateway",
+						Namespace: "default",
+					},
+					Spec: gatewayapi.GatewaySpec{
+						Listeners: []gatewayapi.Listener{{
+							Protocol: gatewayapi.HTTPSProtocolType,
+							TLS: &gatewayapi.GatewayTLSConfig{
+								CertificateRef: gatewayapi.LocalObjectReference{Name: "secret"},
+							},
+						}},
+					},
+				},
+			},
+			obj: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret",
+					Namespace: "default",
+				},
+				Type: v1.SecretTypeTLS,
+				Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+			},
+			want: true,
+		},
 	}
 
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			cache := KubernetesCache{
-				FieldLogger: testLogger(t),
+				GatewayController: tc.gatewayController,
+				FieldLogger:       testLogger(t),
 			}
 			for _, p := range tc.pre {
 				cache.Insert(p)
@@ -1039,6 +1218,36 @@ func TestKubernetesCacheRemove(t *testing.T) {
 			},
 			want: false,
 		},
+		"remove service delegation": {
+			cache: cache(&projcontour.ServiceDelegation{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "delegate",
+					Namespace: "default",
+				},
+			}),
+			obj: &projcontour.ServiceDelegation{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "delegate",
+					Namespace: "default",
+				},
+			},
+			want: true,
+		},
+		"remove upstream": {
+			cache: cache(&projcontour.Upstream{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "legacy",
+					Namespace: "default",
+				},
+			}),
+			obj: &projcontour.Upstream{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "legacy",
+					Namespace: "default",
+				},
+			},
+			want: true,
+		},
 		"remove httpproxy": {
 			cache: cache(&projcontour.HTTPProxy{
 				ObjectMeta: metav1.ObjectMeta{
@@ -1092,6 +1301,66 @@ func TestKubernetesCacheRemove(t *testing.T) {
 	}
 }
 
+func TestKubernetesCacheWarnings(t *testing.T) {
+	cache := KubernetesCache{
+		FieldLogger: testLogger(t),
+	}
+
+	proxy := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "simple",
+			Namespace: "default",
+			Annotations: map[string]string{
+				"projectcontour.io/num-retries": "3",
+			},
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &projcontour.TLS{
+					SecretName: "secret",
+				},
+			},
+		},
+	}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "secret",
+			Namespace: "default",
+		},
+		Type: v1.SecretTypeTLS,
+		Data: map[string][]byte{
+			v1.TLSCertKey: []byte(CERTIFICATE),
+		},
+	}
+
+	cache.Insert(proxy)
+	if !cache.Insert(secret) {
+		t.Fatal("expected insert of a rejected secret in use to trigger a rebuild")
+	}
+
+	want := []Warning{{
+		Object:  proxy,
+		Reason:  "IgnoredAnnotation",
+		Message: `ignoring invalid or unsupported annotation "projectcontour.io/num-retries"`,
+	}, {
+		Object:  secret,
+		Reason:  "InvalidSecret",
+		Message: "missing TLS private key",
+	}}
+	assert.Equal(t, want, cache.Warnings())
+
+	// replacing an object replaces its warnings.
+	fixed := proxy.DeepCopy()
+	fixed.Annotations = nil
+	cache.Insert(fixed)
+	assert.Equal(t, want[1:], cache.Warnings())
+
+	// removing an object removes its warnings.
+	cache.Remove(secret)
+	assert.Equal(t, []Warning(nil), cache.Warnings())
+}
+
 func testLogger(t *testing.T) logrus.FieldLogger {
 	log := logrus.New()
 	log.Out = &testWriter{t}
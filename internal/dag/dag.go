@@ -33,6 +33,9 @@ type DAG struct {
 
 	// status computed while building this dag.
 	statuses map[Meta]Status
+
+	// status of the Gateway API objects computed while building this dag.
+	gatewayStatuses map[objectKey]Status
 }
 
 // Visit calls fn on each root of this DAG.
@@ -48,6 +51,23 @@ func (d *DAG) Statuses() map[Meta]Status {
 	return d.statuses
 }
 
+// GatewayStatuses returns the Status of each Gateway API object
+// associated with the computation of this DAG, ordered by kind,
+// namespace, and name.
+func (d *DAG) GatewayStatuses() []Status {
+	keys := make([]objectKey, 0, len(d.gatewayStatuses))
+	for k := range d.gatewayStatuses {
+		keys = append(keys, k)
+	}
+	sortObjectKeys(keys)
+
+	statuses := make([]Status, 0, len(keys))
+	for _, k := range keys {
+		statuses = append(statuses, d.gatewayStatuses[k])
+	}
+	return statuses
+}
+
 type Condition interface {
 	fmt.Stringer
 }
@@ -260,6 +280,11 @@ type Listener struct {
 	// Port is the TCP port to listen on.
 	Port int
 
+	// IngressClass is the ingress class of the virtual hosts
+	// bound to this Listener, or empty for the default Listeners
+	// shared by every other ingress class.
+	IngressClass string
+
 	VirtualHosts []Vertex
 }
 
@@ -313,6 +338,21 @@ type Service struct {
 
 	// ExternalName is an optional field referencing a dns entry for Service type "ExternalName"
 	ExternalName string
+
+	// StaticEndpoints are the endpoints of a service, such as an
+	// Upstream, which are not discovered through EDS. They are
+	// either all IP addresses or all DNS names.
+	StaticEndpoints []StaticEndpoint
+
+	// SNI is the server name sent when connecting to the
+	// service over TLS.
+	SNI string
+}
+
+// StaticEndpoint is the address and port of a static endpoint.
+type StaticEndpoint struct {
+	Address string
+	Port    int
 }
 
 type servicemeta struct {
@@ -351,8 +391,32 @@ type Cluster struct {
 	// See https://www.envoyproxy.io/docs/envoy/latest/api-v2/api/v2/cds.proto#envoy-api-enum-cluster-lbpolicy
 	LoadBalancerPolicy string
 
+	// The locality aware load balancing to use between the
+	// zones of the cluster's endpoints, if any.
+	LocalityPolicy string
+
 	// Cluster health check policy.
 	*HealthCheckPolicy
+
+	// OutlierDetection defines how Envoy ejects failing
+	// endpoints of the Upstream.
+	OutlierDetection *OutlierDetection
+
+	// CircuitBreakerPolicy, if set, replaces the circuit
+	// breaking limits of the Upstream.
+	CircuitBreakerPolicy *CircuitBreakerPolicy
+
+	// Protocol is the layer 7 protocol of this cluster, one of
+	// "h1", "h2", "h2c" or "tls". If empty, the protocol of the
+	// Upstream service is used.
+	Protocol string
+
+	// HTTP2Settings tunes the HTTP/2 connections of this cluster.
+	HTTP2Settings *HTTP2Settings
+
+	// ConnectionTimeoutPolicy defines the timeouts of
+	// connections to the Upstream.
+	ConnectionTimeoutPolicy *ConnectionTimeoutPolicy
 }
 
 func (c Cluster) Visit(f func(Vertex)) {
@@ -386,10 +450,63 @@ func (s *Secret) PrivateKey() []byte {
 
 // Cluster health check policy.
 type HealthCheckPolicy struct {
+	// Protocol is the protocol of the health check, one of
+	// "http", the default if empty, "tcp", or "grpc".
+	Protocol string
+
 	Path               string
 	Host               string
 	Interval           time.Duration
 	Timeout            time.Duration
 	UnhealthyThreshold uint32
 	HealthyThreshold   uint32
+
+	// GRPCServiceName is the service checked by a grpc health check.
+	GRPCServiceName string
+
+	// Send and Receive are the payloads of a tcp health check.
+	Send    []byte
+	Receive []byte
+}
+
+// ConnectionTimeoutPolicy defines the timeouts of connections
+// to an upstream. A zero duration is not set.
+type ConnectionTimeoutPolicy struct {
+	ConnectTimeout        time.Duration
+	IdleConnectionTimeout time.Duration
+	MaxConnectionDuration time.Duration
+}
+
+// HTTP2Settings holds the HTTP/2 settings of a cluster's upstream
+// connections. Zero values use Envoy's defaults.
+type HTTP2Settings struct {
+	MaxConcurrentStreams        uint32
+	InitialStreamWindowSize     uint32
+	InitialConnectionWindowSize uint32
+}
+
+// CircuitBreakerPolicy holds the circuit breaking limits of a
+// Cluster for each routing priority. A nil priority has no limits
+// beyond Envoy's defaults.
+type CircuitBreakerPolicy struct {
+	Default *CircuitBreakerThresholds
+	High    *CircuitBreakerThresholds
+}
+
+// CircuitBreakerThresholds holds the circuit breaking limits of a
+// routing priority. Zero values use Envoy's defaults.
+type CircuitBreakerThresholds struct {
+	MaxConnections     uint32
+	MaxPendingRequests uint32
+	MaxRequests        uint32
+	MaxRetries         uint32
+}
+
+// OutlierDetection defines passive health checking of a Cluster.
+// Zero values use Envoy's defaults.
+type OutlierDetection struct {
+	Consecutive5xxErrors     uint32
+	ConsecutiveGatewayErrors uint32
+	BaseEjectionTime         time.Duration
+	MaxEjectionPercent       uint32
 }
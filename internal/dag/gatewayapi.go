@@ -0,0 +1,367 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dag
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	gatewayapi "github.com/projectcontour/contour/apis/networking/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Gateway listeners are attached to Envoy's HTTP and HTTPS listeners,
+// whose Service ports are 80 and 443; other ports cannot be served.
+const (
+	gatewayHTTPPort  = 80
+	gatewayHTTPSPort = 443
+)
+
+// boundRoute is an HTTPRoute bound to one or more Gateway listeners.
+type boundRoute struct {
+	sw     *ObjectStatusWriter
+	commit func()
+
+	// routes are the HTTPRoute's rules translated to Routes,
+	// or nil if the HTTPRoute is invalid.
+	routes []*Route
+}
+
+// computeGateways translates the Gateways of each GatewayClass
+// implemented by Contour, and the HTTPRoutes bound to them, into
+// VirtualHosts and SecureVirtualHosts.
+func (b *Builder) computeGateways() {
+	for _, class := range b.Source.gatewayclasses {
+		sw, commit := b.WithObject(class)
+		sw.SetValid()
+		commit()
+	}
+
+	var gateways []*gatewayapi.Gateway
+	for _, gateway := range b.Source.gateways {
+		if _, ok := b.Source.gatewayclasses[Meta{name: gateway.Spec.GatewayClassName}]; ok {
+			gateways = append(gateways, gateway)
+		}
+	}
+	sort.Slice(gateways, func(i, j int) bool {
+		return metaLess(toMeta(gateways[i]), toMeta(gateways[j]))
+	})
+
+	bound := make(map[Meta]*boundRoute)
+	for _, gateway := range gateways {
+		b.computeGateway(gateway, bound)
+	}
+
+	// an HTTPRoute's status is only recorded once every Gateway
+	// it is bound to has been computed.
+	for _, br := range bound {
+		if br.routes != nil {
+			br.sw.SetValid()
+		}
+		br.commit()
+	}
+}
+
+func (b *Builder) computeGateway(gateway *gatewayapi.Gateway, bound map[Meta]*boundRoute) {
+	sw, commit := b.WithObject(gateway)
+	defer commit()
+
+	if !b.rootAllowed(gateway.Namespace) {
+		sw.SetInvalid("Gateway cannot be defined in this namespace")
+		return
+	}
+
+	// every listener is checked before any is served, as the
+	// Gateway's status cannot describe each listener; an invalid
+	// Gateway serves none of its listeners.
+	secrets := make(map[string]*Secret)
+	var binds []func()
+	for i, listener := range gateway.Spec.Listeners {
+		if bind := b.computeListener(sw, gateway, i, listener, secrets, bound); bind != nil {
+			binds = append(binds, bind)
+		}
+	}
+	if sw.IsInvalid() {
+		return
+	}
+	for _, bind := range binds {
+		bind()
+	}
+	sw.SetValid()
+}
+
+// computeListener checks the listener, recording its problems against
+// the Gateway. If it is valid, computeListener returns a func which adds
+// the HTTPRoutes bound to the listener to its VirtualHosts or
+// SecureVirtualHosts. secrets holds the Secret of each hostname served
+// by the Gateway's HTTPS listeners.
+func (b *Builder) computeListener(sw *ObjectStatusWriter, gateway *gatewayapi.Gateway, i int, listener gatewayapi.Listener, secrets map[string]*Secret, bound map[Meta]*boundRoute) func() {
+	invalid := func(format string, args ...interface{}) func() {
+		sw.SetInvalid(fmt.Sprintf("listener %d: ", i) + fmt.Sprintf(format, args...))
+		return nil
+	}
+
+	if strings.Contains(listener.Hostname, "*") {
+		return invalid("hostname %q cannot use wildcards", listener.Hostname)
+	}
+	if kind := listener.Routes.Kind; kind != "" && kind != "HTTPRoute" {
+		return invalid("route kind %q is not supported", kind)
+	}
+
+	var secret *Secret
+	switch listener.Protocol {
+	case gatewayapi.HTTPProtocolType:
+		if listener.Port != gatewayHTTPPort {
+			return invalid("HTTP listeners must use port %d", gatewayHTTPPort)
+		}
+	case gatewayapi.HTTPSProtocolType:
+		if listener.Port != gatewayHTTPSPort {
+			return invalid("HTTPS listeners must use port %d", gatewayHTTPSPort)
+		}
+		if listener.Hostname == "" {
+			return invalid("HTTPS listeners must specify a hostname")
+		}
+		if listener.TLS == nil {
+			return invalid("HTTPS listeners must specify tls")
+		}
+		ref := listener.TLS.CertificateRef
+		if (ref.Group != "" && ref.Group != "core") || (ref.Kind != "" && ref.Kind != "Secret") {
+			return invalid("certificateRef must refer to a Secret")
+		}
+		m := Meta{name: ref.Name, namespace: gateway.Namespace}
+		secret = b.lookupSecret(m, validSecret)
+		if secret == nil {
+			for _, w := range b.Source.warningsFor("Secret", m) {
+				sw.AddWarning(fmt.Sprintf("listener %d: TLS Secret [%s] was rejected: %s", i, ref.Name, w.Message))
+			}
+			return invalid("TLS Secret [%s] not found or is malformed", ref.Name)
+		}
+		if svh, ok := b.securevirtualhosts[listenerHost{name: listener.Hostname}]; ok && svh.Secret != nil && toMeta(svh.Secret.Object) != toMeta(secret.Object) {
+			return invalid("hostname %q is already served with a different certificate", listener.Hostname)
+		}
+		if s, ok := secrets[listener.Hostname]; ok && toMeta(s.Object) != toMeta(secret.Object) {
+			return invalid("hostname %q is already served with a different certificate", listener.Hostname)
+		}
+		secrets[listener.Hostname] = secret
+	default:
+		return invalid("protocol %q is not supported", listener.Protocol)
+	}
+
+	selected, err := b.selectHTTPRoutes(gateway, listener.Routes)
+	if err != nil {
+		return invalid("%v", err)
+	}
+
+	return func() {
+		for _, route := range selected {
+			hosts := routeHosts(listener.Hostname, route.Spec.Hostnames)
+			if len(hosts) == 0 {
+				// the route does not match the listener's hostname.
+				continue
+			}
+
+			m := toMeta(route)
+			br, ok := bound[m]
+			if !ok {
+				br = b.computeHTTPRoute(route)
+				bound[m] = br
+			}
+
+			// Gateways are bound to the default Listeners.
+			for _, host := range hosts {
+				if secret == nil {
+					addRoutes(b.lookupVirtualHost(listenerHost{name: host}), br.routes)
+					continue
+				}
+				svh := b.lookupSecureVirtualHost(listenerHost{name: host})
+				svh.Secret = secret
+				svh.MinProtoVersion = MinProtoVersion("")
+				addRoutes(svh, br.routes)
+			}
+		}
+	}
+}
+
+// selectHTTPRoutes returns the HTTPRoutes selected by rbs,
+// ordered by namespace and name.
+func (b *Builder) selectHTTPRoutes(gateway *gatewayapi.Gateway, rbs gatewayapi.RouteBindingSelector) ([]*gatewayapi.HTTPRoute, error) {
+	from := rbs.Namespaces.From
+	switch from {
+	case "", gatewayapi.RouteSelectSame, gatewayapi.RouteSelectAll:
+	default:
+		return nil, fmt.Errorf("routes.namespaces.from %q is not supported", from)
+	}
+
+	selector := labels.Everything()
+	if rbs.Selector != nil {
+		var err error
+		if selector, err = metav1.LabelSelectorAsSelector(rbs.Selector); err != nil {
+			return nil, fmt.Errorf("routes.selector: %v", err)
+		}
+	}
+
+	var selected []*gatewayapi.HTTPRoute
+	for _, route := range b.Source.httproutes {
+		if from != gatewayapi.RouteSelectAll && route.Namespace != gateway.Namespace {
+			continue
+		}
+		if !selector.Matches(labels.Set(route.Labels)) {
+			continue
+		}
+		selected = append(selected, route)
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		return metaLess(toMeta(selected[i]), toMeta(selected[j]))
+	})
+	return selected, nil
+}
+
+// routeHosts returns the hosts an HTTPRoute with hostnames serves
+// on a listener with the given hostname. A route without hostnames
+// takes the listener's hostname, and a listener without a hostname
+// serves each of the route's hostnames.
+func routeHosts(hostname string, hostnames []string) []string {
+	if len(hostnames) == 0 {
+		return []string{stringOrDefault(hostname, "*")}
+	}
+	if hostname == "" {
+		return hostnames
+	}
+	for _, h := range hostnames {
+		if h == hostname {
+			return []string{hostname}
+		}
+	}
+	return nil
+}
+
+// computeHTTPRoute translates the rules of an HTTPRoute into Routes.
+// If the HTTPRoute is invalid, the problems are recorded in its status
+// and none of its rules are served.
+func (b *Builder) computeHTTPRoute(route *gatewayapi.HTTPRoute) *boundRoute {
+	sw, commit := b.WithObject(route)
+	br := &boundRoute{
+		sw:     sw,
+		commit: commit,
+	}
+
+	for _, h := range route.Spec.Hostnames {
+		if strings.Contains(h, "*") {
+			sw.SetInvalid(fmt.Sprintf("hostname %q cannot use wildcards", h))
+		}
+	}
+
+	var routes []*Route
+	for i, rule := range route.Spec.Rules {
+		routes = append(routes, b.computeHTTPRouteRule(sw, route, i, rule)...)
+	}
+	if sw.IsInvalid() {
+		return br
+	}
+	br.routes = routes
+	return br
+}
+
+func (b *Builder) computeHTTPRouteRule(sw *ObjectStatusWriter, route *gatewayapi.HTTPRoute, i int, rule gatewayapi.HTTPRouteRule) []*Route {
+	invalid := func(format string, args ...interface{}) {
+		sw.SetInvalid(fmt.Sprintf("rule %d: ", i) + fmt.Sprintf(format, args...))
+	}
+
+	if len(rule.ForwardTo) == 0 {
+		invalid("forwardTo must be specified")
+		return nil
+	}
+
+	var clusters []*Cluster
+	for _, fwd := range rule.ForwardTo {
+		if fwd.Port < 1 || fwd.Port > 65535 {
+			invalid("service %q: port must be in the range 1-65535", fwd.ServiceName)
+			return nil
+		}
+		if fwd.Weight < 0 {
+			invalid("service %q: weight must not be negative", fwd.ServiceName)
+			return nil
+		}
+		m := Meta{name: fwd.ServiceName, namespace: route.Namespace}
+		s := b.lookupService(m, intstr.FromInt(int(fwd.Port)))
+		if s == nil {
+			sw.SetInvalid(fmt.Sprintf("Service [%s:%d] is invalid or missing", fwd.ServiceName, fwd.Port))
+			return nil
+		}
+		clusters = append(clusters, &Cluster{
+			Upstream: s,
+			Weight:   uint32(fwd.Weight),
+		})
+	}
+
+	matches := rule.Matches
+	if len(matches) == 0 {
+		// a rule without matches matches every request.
+		matches = []gatewayapi.HTTPRouteMatch{{}}
+	}
+
+	var routes []*Route
+	for _, match := range matches {
+		r := &Route{
+			PathCondition: &PrefixCondition{Prefix: "/"},
+			Clusters:      clusters,
+		}
+		if path := match.Path; path != nil {
+			if !strings.HasPrefix(path.Value, "/") {
+				invalid("path %q must start with /", path.Value)
+				return nil
+			}
+			switch path.Type {
+			case "", gatewayapi.PathMatchPrefix:
+				r.PathCondition = &PrefixCondition{Prefix: path.Value}
+			case gatewayapi.PathMatchExact:
+				// Envoy's regex matches the whole path.
+				r.PathCondition = &RegexCondition{Regex: regexp.QuoteMeta(path.Value)}
+			default:
+				invalid("path match type %q is not supported", path.Type)
+				return nil
+			}
+		}
+		if headers := match.Headers; headers != nil {
+			if headers.Type != "" && headers.Type != gatewayapi.HeaderMatchExact {
+				invalid("header match type %q is not supported", headers.Type)
+				return nil
+			}
+			for name, value := range headers.Values {
+				r.HeaderConditions = append(r.HeaderConditions, HeaderCondition{
+					Name:      name,
+					Value:     value,
+					MatchType: "exact",
+				})
+			}
+			sort.Slice(r.HeaderConditions, func(i, j int) bool {
+				return r.HeaderConditions[i].Name < r.HeaderConditions[j].Name
+			})
+		}
+		routes = append(routes, r)
+	}
+	return routes
+}
+
+// metaLess orders Metas by namespace and name.
+func metaLess(a, b Meta) bool {
+	if a.namespace != b.namespace {
+		return a.namespace < b.namespace
+	}
+	return a.name < b.name
+}
@@ -0,0 +1,611 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dag
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	gatewayapi "github.com/projectcontour/contour/apis/networking/v1alpha1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDAGInsertGatewayAPI(t *testing.T) {
+	class := &gatewayapi.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "contour",
+		},
+		Spec: gatewayapi.GatewayClassSpec{
+			Controller: "projectcontour.io/contour",
+		},
+	}
+
+	otherclass := &gatewayapi.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "other",
+		},
+		Spec: gatewayapi.GatewayClassSpec{
+			Controller: "example.com/other",
+		},
+	}
+
+	sec1 := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "secret",
+			Namespace: "default",
+		},
+		Type: v1.SecretTypeTLS,
+		Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+	}
+
+	s1 := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Name:     "http",
+				Protocol: "TCP",
+				Port:     8080,
+			}},
+		},
+	}
+
+	s2 := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "other",
+		},
+		Spec: s1.Spec,
+	}
+
+	httpListener := gatewayapi.Listener{
+		Port:     80,
+		Protocol: gatewayapi.HTTPProtocolType,
+	}
+
+	gateway := func(class string, listeners ...gatewayapi.Listener) *gatewayapi.Gateway {
+		return &gatewayapi.Gateway{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "gateway",
+				Namespace: "default",
+			},
+			Spec: gatewayapi.GatewaySpec{
+				GatewayClassName: class,
+				Listeners:        listeners,
+			},
+		}
+	}
+
+	route1 := &gatewayapi.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+			Labels: map[string]string{
+				"app": "kuard",
+			},
+		},
+		Spec: gatewayapi.HTTPRouteSpec{
+			Hostnames: []string{"kuard.example.com"},
+			Rules: []gatewayapi.HTTPRouteRule{{
+				ForwardTo: []gatewayapi.HTTPRouteForwardTo{{
+					ServiceName: "kuard",
+					Port:        8080,
+				}},
+			}},
+		},
+	}
+
+	// route2 has no hostnames and matches on path and headers.
+	route2 := &gatewayapi.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: gatewayapi.HTTPRouteSpec{
+			Rules: []gatewayapi.HTTPRouteRule{{
+				Matches: []gatewayapi.HTTPRouteMatch{{
+					Path: &gatewayapi.HTTPPathMatch{
+						Type:  gatewayapi.PathMatchExact,
+						Value: "/healthz",
+					},
+				}, {
+					Path: &gatewayapi.HTTPPathMatch{
+						Value: "/api",
+					},
+					Headers: &gatewayapi.HTTPHeaderMatch{
+						Values: map[string]string{
+							"x-env": "prod",
+						},
+					},
+				}},
+				ForwardTo: []gatewayapi.HTTPRouteForwardTo{{
+					ServiceName: "kuard",
+					Port:        8080,
+					Weight:      3,
+				}},
+			}},
+		},
+	}
+
+	// route3 lives in another namespace.
+	route3 := &gatewayapi.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "other",
+		},
+		Spec: gatewayapi.HTTPRouteSpec{
+			Hostnames: []string{"other.example.com"},
+			Rules:     route1.Spec.Rules,
+		},
+	}
+
+	// route4 refers to a missing service.
+	route4 := &gatewayapi.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "missing",
+			Namespace: "default",
+		},
+		Spec: gatewayapi.HTTPRouteSpec{
+			Hostnames: []string{"missing.example.com"},
+			Rules: []gatewayapi.HTTPRouteRule{{
+				ForwardTo: []gatewayapi.HTTPRouteForwardTo{{
+					ServiceName: "missing",
+					Port:        8080,
+				}},
+			}},
+		},
+	}
+
+	tests := map[string]struct {
+		objs []interface{}
+		want []Vertex
+	}{
+		"http listener": {
+			objs: []interface{}{class, gateway("contour", httpListener), route1, s1},
+			want: listeners(
+				&Listener{
+					Port:         80,
+					VirtualHosts: virtualhosts(virtualhost("kuard.example.com", prefixroute("/", service(s1)))),
+				},
+			),
+		},
+		"gateway of another class": {
+			objs: []interface{}{class, otherclass, gateway("other", httpListener), route1, s1},
+			want: listeners(),
+		},
+		"gateway of a missing class": {
+			objs: []interface{}{gateway("contour", httpListener), route1, s1},
+			want: listeners(),
+		},
+		"route without hostnames takes the listener's hostname": {
+			objs: []interface{}{
+				class,
+				gateway("contour", gatewayapi.Listener{
+					Hostname: "www.example.com",
+					Port:     80,
+					Protocol: gatewayapi.HTTPProtocolType,
+				}),
+				route2, s1,
+			},
+			want: listeners(
+				&Listener{
+					Port: 80,
+					VirtualHosts: virtualhosts(
+						virtualhost("www.example.com",
+							&Route{
+								PathCondition: regex("/healthz"),
+								Clusters:      []*Cluster{{Upstream: service(s1), Weight: 3}},
+							},
+							&Route{
+								PathCondition:    prefix("/api"),
+								HeaderConditions: []HeaderCondition{{Name: "x-env", Value: "prod", MatchType: "exact"}},
+								Clusters:         []*Cluster{{Upstream: service(s1), Weight: 3}},
+							},
+						),
+					),
+				},
+			),
+		},
+		"route without hostnames on a listener without a hostname": {
+			objs: []interface{}{class, gateway("contour", httpListener), route2, s1},
+			want: listeners(
+				&Listener{
+					Port: 80,
+					VirtualHosts: virtualhosts(
+						virtualhost("*",
+							&Route{
+								PathCondition: regex("/healthz"),
+								Clusters:      []*Cluster{{Upstream: service(s1), Weight: 3}},
+							},
+							&Route{
+								PathCondition:    prefix("/api"),
+								HeaderConditions: []HeaderCondition{{Name: "x-env", Value: "prod", MatchType: "exact"}},
+								Clusters:         []*Cluster{{Upstream: service(s1), Weight: 3}},
+							},
+						),
+					),
+				},
+			),
+		},
+		"route hostname does not match listener hostname": {
+			objs: []interface{}{
+				class,
+				gateway("contour", gatewayapi.Listener{
+					Hostname: "www.example.com",
+					Port:     80,
+					Protocol: gatewayapi.HTTPProtocolType,
+				}),
+				route1, s1,
+			},
+			want: listeners(),
+		},
+		"https listener": {
+			objs: []interface{}{
+				class,
+				gateway("contour", gatewayapi.Listener{
+					Hostname: "kuard.example.com",
+					Port:     443,
+					Protocol: gatewayapi.HTTPSProtocolType,
+					TLS: &gatewayapi.GatewayTLSConfig{
+						CertificateRef: gatewayapi.LocalObjectReference{Name: sec1.Name},
+					},
+				}),
+				route1, s1, sec1,
+			},
+			want: listeners(
+				&Listener{
+					Port:         443,
+					VirtualHosts: virtualhosts(securevirtualhost("kuard.example.com", sec1, prefixroute("/", service(s1)))),
+				},
+			),
+		},
+		"gateway with an invalid listener is not served": {
+			objs: []interface{}{
+				class,
+				gateway("contour", gatewayapi.Listener{
+					Hostname: "kuard.example.com",
+					Port:     443,
+					Protocol: gatewayapi.HTTPSProtocolType,
+					TLS: &gatewayapi.GatewayTLSConfig{
+						CertificateRef: gatewayapi.LocalObjectReference{Name: sec1.Name},
+					},
+				}, httpListener),
+				route1, s1,
+			},
+			want: listeners(),
+		},
+		"routes are selected by label": {
+			objs: []interface{}{
+				class,
+				gateway("contour", gatewayapi.Listener{
+					Port:     80,
+					Protocol: gatewayapi.HTTPProtocolType,
+					Routes: gatewayapi.RouteBindingSelector{
+						Selector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"app": "kuard"},
+						},
+					},
+				}),
+				route1, route4, s1,
+			},
+			want: listeners(
+				&Listener{
+					Port:         80,
+					VirtualHosts: virtualhosts(virtualhost("kuard.example.com", prefixroute("/", service(s1)))),
+				},
+			),
+		},
+		"routes in other namespaces are not selected by default": {
+			objs: []interface{}{class, gateway("contour", httpListener), route3, s2},
+			want: listeners(),
+		},
+		"routes in other namespaces are selected from all namespaces": {
+			objs: []interface{}{
+				class,
+				gateway("contour", gatewayapi.Listener{
+					Port:     80,
+					Protocol: gatewayapi.HTTPProtocolType,
+					Routes: gatewayapi.RouteBindingSelector{
+						Namespaces: gatewayapi.RouteNamespaces{From: gatewayapi.RouteSelectAll},
+					},
+				}),
+				route3, s2,
+			},
+			want: listeners(
+				&Listener{
+					Port:         80,
+					VirtualHosts: virtualhosts(virtualhost("other.example.com", prefixroute("/", service(s2)))),
+				},
+			),
+		},
+		"invalid route is not served": {
+			objs: []interface{}{class, gateway("contour", httpListener), route1, route4, s1},
+			want: listeners(
+				&Listener{
+					Port:         80,
+					VirtualHosts: virtualhosts(virtualhost("kuard.example.com", prefixroute("/", service(s1)))),
+				},
+			),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := Builder{
+				Source: KubernetesCache{
+					GatewayController: "projectcontour.io/contour",
+					FieldLogger:       testLogger(t),
+				},
+			}
+			for _, o := range tc.objs {
+				builder.Source.Insert(o)
+			}
+			dag := builder.Build()
+
+			got := make(map[int]*Listener)
+			dag.Visit(listenerMap(got).Visit)
+
+			want := make(map[int]*Listener)
+			for _, v := range tc.want {
+				if l, ok := v.(*Listener); ok {
+					want[l.Port] = l
+				}
+			}
+			opts := []cmp.Option{
+				cmp.AllowUnexported(VirtualHost{}),
+			}
+			if diff := cmp.Diff(want, got, opts...); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestDAGGatewayAPIStatus(t *testing.T) {
+	class := &gatewayapi.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "contour",
+		},
+		Spec: gatewayapi.GatewayClassSpec{
+			Controller: "projectcontour.io/contour",
+		},
+	}
+
+	s1 := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Name:     "http",
+				Protocol: "TCP",
+				Port:     8080,
+			}},
+		},
+	}
+
+	gateway1 := &gatewayapi.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gateway",
+			Namespace: "default",
+		},
+		Spec: gatewayapi.GatewaySpec{
+			GatewayClassName: "contour",
+			Listeners: []gatewayapi.Listener{{
+				Port:     80,
+				Protocol: gatewayapi.HTTPProtocolType,
+			}},
+		},
+	}
+
+	// gateway2's second, third, and fourth listeners are invalid,
+	// so none of its listeners are served and route1 is not bound.
+	gateway2 := &gatewayapi.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gateway",
+			Namespace: "default",
+		},
+		Spec: gatewayapi.GatewaySpec{
+			GatewayClassName: "contour",
+			Listeners: []gatewayapi.Listener{{
+				Port:     80,
+				Protocol: gatewayapi.HTTPProtocolType,
+			}, {
+				Hostname: "kuard.example.com",
+				Port:     443,
+				Protocol: gatewayapi.HTTPSProtocolType,
+				TLS: &gatewayapi.GatewayTLSConfig{
+					CertificateRef: gatewayapi.LocalObjectReference{Name: "missing"},
+				},
+			}, {
+				Port:     8080,
+				Protocol: "TCP",
+			}, {
+				Port:     8080,
+				Protocol: gatewayapi.HTTPProtocolType,
+			}},
+		},
+	}
+
+	sec1 := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "secret",
+			Namespace: "default",
+		},
+		Type: v1.SecretTypeTLS,
+		Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+	}
+
+	sec2 := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "other",
+			Namespace: "default",
+		},
+		Type: v1.SecretTypeTLS,
+		Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+	}
+
+	// gateway3 serves one hostname with two certificates.
+	gateway3 := &gatewayapi.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gateway",
+			Namespace: "default",
+		},
+		Spec: gatewayapi.GatewaySpec{
+			GatewayClassName: "contour",
+			Listeners: []gatewayapi.Listener{{
+				Hostname: "kuard.example.com",
+				Port:     443,
+				Protocol: gatewayapi.HTTPSProtocolType,
+				TLS: &gatewayapi.GatewayTLSConfig{
+					CertificateRef: gatewayapi.LocalObjectReference{Name: sec1.Name},
+				},
+			}, {
+				Hostname: "kuard.example.com",
+				Port:     443,
+				Protocol: gatewayapi.HTTPSProtocolType,
+				TLS: &gatewayapi.GatewayTLSConfig{
+					CertificateRef: gatewayapi.LocalObjectReference{Name: sec2.Name},
+				},
+			}},
+		},
+	}
+
+	route1 := &gatewayapi.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: gatewayapi.HTTPRouteSpec{
+			Rules: []gatewayapi.HTTPRouteRule{{
+				ForwardTo: []gatewayapi.HTTPRouteForwardTo{{
+					ServiceName: "kuard",
+					Port:        8080,
+				}},
+			}},
+		},
+	}
+
+	// route2 has the same name as route1 and several problems.
+	route2 := &gatewayapi.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: gatewayapi.HTTPRouteSpec{
+			Hostnames: []string{"*.example.com"},
+			Rules: []gatewayapi.HTTPRouteRule{{
+				Matches: []gatewayapi.HTTPRouteMatch{{
+					Path: &gatewayapi.HTTPPathMatch{Value: "api"},
+				}},
+				ForwardTo: []gatewayapi.HTTPRouteForwardTo{{
+					ServiceName: "kuard",
+					Port:        8080,
+				}},
+			}, {
+				ForwardTo: []gatewayapi.HTTPRouteForwardTo{{
+					ServiceName: "missing",
+					Port:        8080,
+				}},
+			}},
+		},
+	}
+
+	tests := map[string]struct {
+		objs []interface{}
+		want []Status
+	}{
+		"valid": {
+			objs: []interface{}{class, gateway1, route1, s1},
+			want: []Status{
+				{Object: gateway1, Status: StatusValid, Description: "valid Gateway"},
+				{Object: class, Status: StatusValid, Description: "valid GatewayClass"},
+				{Object: route1, Status: StatusValid, Description: "valid HTTPRoute"},
+			},
+		},
+		"unbound route has no status": {
+			objs: []interface{}{class, route1, s1},
+			want: []Status{
+				{Object: class, Status: StatusValid, Description: "valid GatewayClass"},
+			},
+		},
+		"invalid listeners": {
+			objs: []interface{}{class, gateway2, route1, s1},
+			want: []Status{{
+				Object:      gateway2,
+				Status:      StatusInvalid,
+				Description: "listener 1: TLS Secret [missing] not found or is malformed",
+				Errors: []string{
+					"listener 1: TLS Secret [missing] not found or is malformed",
+					`listener 2: protocol "TCP" is not supported`,
+					"listener 3: HTTP listeners must use port 80",
+				},
+			}, {
+				Object: class, Status: StatusValid, Description: "valid GatewayClass",
+			}},
+		},
+		"hostname with two certificates": {
+			objs: []interface{}{class, gateway3, route1, s1, sec1, sec2},
+			want: []Status{{
+				Object:      gateway3,
+				Status:      StatusInvalid,
+				Description: `listener 1: hostname "kuard.example.com" is already served with a different certificate`,
+				Errors: []string{
+					`listener 1: hostname "kuard.example.com" is already served with a different certificate`,
+				},
+			}, {
+				Object: class, Status: StatusValid, Description: "valid GatewayClass",
+			}},
+		},
+		"invalid route": {
+			objs: []interface{}{class, gateway1, route2, s1},
+			want: []Status{{
+				Object: gateway1, Status: StatusValid, Description: "valid Gateway",
+			}, {
+				Object: class, Status: StatusValid, Description: "valid GatewayClass",
+			}, {
+				Object:      route2,
+				Status:      StatusInvalid,
+				Description: `hostname "*.example.com" cannot use wildcards`,
+				Errors: []string{
+					`hostname "*.example.com" cannot use wildcards`,
+					`rule 0: path "api" must start with /`,
+					"Service [missing:8080] is invalid or missing",
+				},
+			}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := Builder{
+				Source: KubernetesCache{
+					GatewayController: "projectcontour.io/contour",
+					FieldLogger:       testLogger(t),
+				},
+			}
+			for _, o := range tc.objs {
+				builder.Source.Insert(o)
+			}
+			got := builder.Build().GatewayStatuses()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
@@ -106,11 +106,13 @@ func ingressrouteHealthCheckPolicy(hc *ingressroutev1.HealthCheck) *HealthCheckP
 	}
 }
 
-func healthCheckPolicy(hc *projcontour.HTTPHealthCheckPolicy) *HealthCheckPolicy {
+// healthCheckPolicy returns the HealthCheckPolicy for hc, or an
+// error if hc is invalid.
+func healthCheckPolicy(hc *projcontour.HTTPHealthCheckPolicy) (*HealthCheckPolicy, error) {
 	if hc == nil {
-		return nil
+		return nil, nil
 	}
-	return &HealthCheckPolicy{
+	policy := &HealthCheckPolicy{
 		Path:               hc.Path,
 		Host:               hc.Host,
 		Interval:           time.Duration(hc.IntervalSeconds) * time.Second,
@@ -118,6 +120,169 @@ func healthCheckPolicy(hc *projcontour.HTTPHealthCheckPolicy) *HealthCheckPolicy
 		UnhealthyThreshold: hc.UnhealthyThresholdCount,
 		HealthyThreshold:   hc.HealthyThresholdCount,
 	}
+	if hc.GRPC != nil {
+		policy.Protocol = "grpc"
+		policy.Path = ""
+		policy.GRPCServiceName = hc.GRPC.ServiceName
+		return policy, nil
+	}
+	if hc.Path == "" {
+		return nil, fmt.Errorf("healthCheckPolicy: path is required")
+	}
+	return policy, nil
+}
+
+func tcpHealthCheckPolicy(hc *projcontour.TCPHealthCheckPolicy) *HealthCheckPolicy {
+	if hc == nil {
+		return nil
+	}
+	policy := &HealthCheckPolicy{
+		Protocol:           "tcp",
+		Interval:           time.Duration(hc.IntervalSeconds) * time.Second,
+		Timeout:            time.Duration(hc.TimeoutSeconds) * time.Second,
+		UnhealthyThreshold: hc.UnhealthyThresholdCount,
+		HealthyThreshold:   hc.HealthyThresholdCount,
+	}
+	if hc.Send != "" {
+		policy.Send = []byte(hc.Send)
+	}
+	if hc.Receive != "" {
+		policy.Receive = []byte(hc.Receive)
+	}
+	return policy
+}
+
+// outlierDetection returns the OutlierDetection for od, or an error
+// if od is invalid.
+func outlierDetection(od *projcontour.OutlierDetection) (*OutlierDetection, error) {
+	if od == nil {
+		return nil, nil
+	}
+	var baseEjectionTime time.Duration
+	if od.BaseEjectionTime != "" {
+		d, err := time.ParseDuration(od.BaseEjectionTime)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("outlierDetection: invalid baseEjectionTime %q", od.BaseEjectionTime)
+		}
+		baseEjectionTime = d
+	}
+	if od.MaxEjectionPercent > 100 {
+		return nil, fmt.Errorf("outlierDetection: maxEjectionPercent must be at most 100")
+	}
+	return &OutlierDetection{
+		Consecutive5xxErrors:     od.Consecutive5xxErrors,
+		ConsecutiveGatewayErrors: od.ConsecutiveGatewayErrors,
+		BaseEjectionTime:         baseEjectionTime,
+		MaxEjectionPercent:       od.MaxEjectionPercent,
+	}, nil
+}
+
+// connectionTimeoutPolicy returns the ConnectionTimeoutPolicy
+// for tp, or an error if any of its durations are invalid.
+func connectionTimeoutPolicy(tp *projcontour.ConnectionTimeoutPolicy) (*ConnectionTimeoutPolicy, error) {
+	if tp == nil {
+		return nil, nil
+	}
+	parse := func(field, value string) (time.Duration, error) {
+		if value == "" {
+			return 0, nil
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return 0, fmt.Errorf("connectionTimeoutPolicy: invalid %s %q", field, value)
+		}
+		return d, nil
+	}
+
+	connect, err := parse("connectTimeout", tp.ConnectTimeout)
+	if err != nil {
+		return nil, err
+	}
+	idle, err := parse("idleConnectionTimeout", tp.IdleConnectionTimeout)
+	if err != nil {
+		return nil, err
+	}
+	max, err := parse("maxConnectionDuration", tp.MaxConnectionDuration)
+	if err != nil {
+		return nil, err
+	}
+	return &ConnectionTimeoutPolicy{
+		ConnectTimeout:        connect,
+		IdleConnectionTimeout: idle,
+		MaxConnectionDuration: max,
+	}, nil
+}
+
+// connectionTimeouts returns tp with any durations it does not set
+// taken from defaults, or nil if no durations are set. If tcp is true
+// only the connect timeout is kept, as the idle timeout and maximum
+// duration of a connection do not apply to TCP proxying.
+func connectionTimeouts(tp *ConnectionTimeoutPolicy, defaults ConnectionTimeoutPolicy, tcp bool) *ConnectionTimeoutPolicy {
+	merged := defaults
+	if tp != nil {
+		merged = *tp
+		if merged.ConnectTimeout == 0 {
+			merged.ConnectTimeout = defaults.ConnectTimeout
+		}
+		if merged.IdleConnectionTimeout == 0 {
+			merged.IdleConnectionTimeout = defaults.IdleConnectionTimeout
+		}
+		if merged.MaxConnectionDuration == 0 {
+			merged.MaxConnectionDuration = defaults.MaxConnectionDuration
+		}
+	}
+	if tcp {
+		merged.IdleConnectionTimeout = 0
+		merged.MaxConnectionDuration = 0
+	}
+	if merged == (ConnectionTimeoutPolicy{}) {
+		return nil
+	}
+	return &merged
+}
+
+// http2Settings returns the HTTP2Settings for hs, or an error
+// if any of its settings are out of range.
+func http2Settings(hs *projcontour.HTTP2Settings) (*HTTP2Settings, error) {
+	if hs == nil {
+		return nil, nil
+	}
+	const maxWindow = 1<<31 - 1
+	if hs.MaxConcurrentStreams > maxWindow {
+		return nil, fmt.Errorf("http2: maxConcurrentStreams must be at most %d", maxWindow)
+	}
+	if hs.InitialStreamWindowSize != 0 && (hs.InitialStreamWindowSize < 65535 || hs.InitialStreamWindowSize > maxWindow) {
+		return nil, fmt.Errorf("http2: initialStreamWindowSize must be in the range 65535-%d", maxWindow)
+	}
+	if hs.InitialConnectionWindowSize != 0 && (hs.InitialConnectionWindowSize < 65535 || hs.InitialConnectionWindowSize > maxWindow) {
+		return nil, fmt.Errorf("http2: initialConnectionWindowSize must be in the range 65535-%d", maxWindow)
+	}
+	return &HTTP2Settings{
+		MaxConcurrentStreams:        hs.MaxConcurrentStreams,
+		InitialStreamWindowSize:     hs.InitialStreamWindowSize,
+		InitialConnectionWindowSize: hs.InitialConnectionWindowSize,
+	}, nil
+}
+
+func circuitBreakerPolicy(cb *projcontour.CircuitBreakerPolicy) *CircuitBreakerPolicy {
+	if cb == nil {
+		return nil
+	}
+	thresholds := func(t *projcontour.CircuitBreakerThresholds) *CircuitBreakerThresholds {
+		if t == nil {
+			return nil
+		}
+		return &CircuitBreakerThresholds{
+			MaxConnections:     t.MaxConnections,
+			MaxPendingRequests: t.MaxPendingRequests,
+			MaxRequests:        t.MaxRequests,
+			MaxRetries:         t.MaxRetries,
+		}
+	}
+	return &CircuitBreakerPolicy{
+		Default: thresholds(cb.Default),
+		High:    thresholds(cb.High),
+	}
 }
 
 // loadBalancerPolicy returns the load balancer strategy or
@@ -138,6 +303,22 @@ func loadBalancerPolicy(lbp *projcontour.LoadBalancerPolicy) string {
 	}
 }
 
+// localityPolicy returns the locality load balancing strategy or
+// blank if no valid strategy is supplied.
+func localityPolicy(lbp *projcontour.LoadBalancerPolicy) string {
+	if lbp == nil {
+		return ""
+	}
+	switch lbp.LocalityStrategy {
+	case "ZoneAware":
+		return "ZoneAware"
+	case "LocalityWeighted":
+		return "LocalityWeighted"
+	default:
+		return ""
+	}
+}
+
 func parseTimeout(timeout string) time.Duration {
 	if timeout == "" {
 		// Blank is interpreted as no timeout specified, use envoy defaults
@@ -387,6 +387,365 @@ func TestLoadBalancerPolicy(t *testing.T) {
 	}
 }
 
+func TestLocalityPolicy(t *testing.T) {
+	tests := map[string]struct {
+		lbp  *projcontour.LoadBalancerPolicy
+		want string
+	}{
+		"nil": {
+			lbp:  nil,
+			want: "",
+		},
+		"empty": {
+			lbp:  &projcontour.LoadBalancerPolicy{},
+			want: "",
+		},
+		"ZoneAware": {
+			lbp: &projcontour.LoadBalancerPolicy{
+				LocalityStrategy: "ZoneAware",
+			},
+			want: "ZoneAware",
+		},
+		"LocalityWeighted": {
+			lbp: &projcontour.LoadBalancerPolicy{
+				Strategy:         "Random",
+				LocalityStrategy: "LocalityWeighted",
+			},
+			want: "LocalityWeighted",
+		},
+		"unknown": {
+			lbp: &projcontour.LoadBalancerPolicy{
+				LocalityStrategy: "nearby",
+			},
+			want: "",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := localityPolicy(tc.lbp)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestOutlierDetection(t *testing.T) {
+	tests := map[string]struct {
+		od      *projcontour.OutlierDetection
+		want    *OutlierDetection
+		wantErr string
+	}{
+		"nil": {
+			od:   nil,
+			want: nil,
+		},
+		"empty": {
+			od:   &projcontour.OutlierDetection{},
+			want: &OutlierDetection{},
+		},
+		"all fields": {
+			od: &projcontour.OutlierDetection{
+				Consecutive5xxErrors:     3,
+				ConsecutiveGatewayErrors: 2,
+				BaseEjectionTime:         "1m",
+				MaxEjectionPercent:       100,
+			},
+			want: &OutlierDetection{
+				Consecutive5xxErrors:     3,
+				ConsecutiveGatewayErrors: 2,
+				BaseEjectionTime:         time.Minute,
+				MaxEjectionPercent:       100,
+			},
+		},
+		"invalid base ejection time": {
+			od: &projcontour.OutlierDetection{
+				BaseEjectionTime: "forever",
+			},
+			wantErr: `outlierDetection: invalid baseEjectionTime "forever"`,
+		},
+		"negative base ejection time": {
+			od: &projcontour.OutlierDetection{
+				BaseEjectionTime: "-10s",
+			},
+			wantErr: `outlierDetection: invalid baseEjectionTime "-10s"`,
+		},
+		"max ejection percent over 100": {
+			od: &projcontour.OutlierDetection{
+				MaxEjectionPercent: 101,
+			},
+			wantErr: "outlierDetection: maxEjectionPercent must be at most 100",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := outlierDetection(tc.od)
+			if tc.wantErr != "" {
+				if err == nil {
+					t.Fatalf("expected error %q, got nil", tc.wantErr)
+				}
+				assert.Equal(t, tc.wantErr, err.Error())
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestConnectionTimeoutPolicy(t *testing.T) {
+	tests := map[string]struct {
+		tp      *projcontour.ConnectionTimeoutPolicy
+		want    *ConnectionTimeoutPolicy
+		wantErr string
+	}{
+		"nil": {
+			tp:   nil,
+			want: nil,
+		},
+		"empty": {
+			tp:   &projcontour.ConnectionTimeoutPolicy{},
+			want: &ConnectionTimeoutPolicy{},
+		},
+		"all fields": {
+			tp: &projcontour.ConnectionTimeoutPolicy{
+				ConnectTimeout:        "2s",
+				IdleConnectionTimeout: "1m",
+				MaxConnectionDuration: "1h",
+			},
+			want: &ConnectionTimeoutPolicy{
+				ConnectTimeout:        2 * time.Second,
+				IdleConnectionTimeout: time.Minute,
+				MaxConnectionDuration: time.Hour,
+			},
+		},
+		"invalid connect timeout": {
+			tp: &projcontour.ConnectionTimeoutPolicy{
+				ConnectTimeout: "soon",
+			},
+			wantErr: `connectionTimeoutPolicy: invalid connectTimeout "soon"`,
+		},
+		"zero idle connection timeout": {
+			tp: &projcontour.ConnectionTimeoutPolicy{
+				IdleConnectionTimeout: "0s",
+			},
+			wantErr: `connectionTimeoutPolicy: invalid idleConnectionTimeout "0s"`,
+		},
+		"negative max connection duration": {
+			tp: &projcontour.ConnectionTimeoutPolicy{
+				MaxConnectionDuration: "-1h",
+			},
+			wantErr: `connectionTimeoutPolicy: invalid maxConnectionDuration "-1h"`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := connectionTimeoutPolicy(tc.tp)
+			if tc.wantErr != "" {
+				if err == nil {
+					t.Fatalf("expected error %q, got nil", tc.wantErr)
+				}
+				assert.Equal(t, tc.wantErr, err.Error())
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestConnectionTimeouts(t *testing.T) {
+	defaults := ConnectionTimeoutPolicy{
+		ConnectTimeout:        time.Second,
+		IdleConnectionTimeout: time.Minute,
+	}
+	tests := map[string]struct {
+		tp       *ConnectionTimeoutPolicy
+		defaults ConnectionTimeoutPolicy
+		tcp      bool
+		want     *ConnectionTimeoutPolicy
+	}{
+		"no policy or defaults": {
+			tp:   nil,
+			want: nil,
+		},
+		"empty policy without defaults": {
+			tp:   &ConnectionTimeoutPolicy{},
+			want: nil,
+		},
+		"defaults only": {
+			tp:       nil,
+			defaults: defaults,
+			want: &ConnectionTimeoutPolicy{
+				ConnectTimeout:        time.Second,
+				IdleConnectionTimeout: time.Minute,
+			},
+		},
+		"policy overrides defaults": {
+			tp: &ConnectionTimeoutPolicy{
+				ConnectTimeout:        2 * time.Second,
+				MaxConnectionDuration: time.Hour,
+			},
+			defaults: defaults,
+			want: &ConnectionTimeoutPolicy{
+				ConnectTimeout:        2 * time.Second,
+				IdleConnectionTimeout: time.Minute,
+				MaxConnectionDuration: time.Hour,
+			},
+		},
+		"tcp keeps only the connect timeout": {
+			tp: &ConnectionTimeoutPolicy{
+				MaxConnectionDuration: time.Hour,
+			},
+			defaults: defaults,
+			tcp:      true,
+			want: &ConnectionTimeoutPolicy{
+				ConnectTimeout: time.Second,
+			},
+		},
+		"tcp without a connect timeout": {
+			tp: &ConnectionTimeoutPolicy{
+				IdleConnectionTimeout: time.Minute,
+			},
+			tcp:  true,
+			want: nil,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := connectionTimeouts(tc.tp, tc.defaults, tc.tcp)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestHTTP2Settings(t *testing.T) {
+	tests := map[string]struct {
+		hs      *projcontour.HTTP2Settings
+		want    *HTTP2Settings
+		wantErr string
+	}{
+		"nil": {
+			hs:   nil,
+			want: nil,
+		},
+		"empty": {
+			hs:   &projcontour.HTTP2Settings{},
+			want: &HTTP2Settings{},
+		},
+		"all fields": {
+			hs: &projcontour.HTTP2Settings{
+				MaxConcurrentStreams:        100,
+				InitialStreamWindowSize:     65535,
+				InitialConnectionWindowSize: 1 << 20,
+			},
+			want: &HTTP2Settings{
+				MaxConcurrentStreams:        100,
+				InitialStreamWindowSize:     65535,
+				InitialConnectionWindowSize: 1 << 20,
+			},
+		},
+		"max concurrent streams too large": {
+			hs: &projcontour.HTTP2Settings{
+				MaxConcurrentStreams: 1 << 31,
+			},
+			wantErr: "http2: maxConcurrentStreams must be at most 2147483647",
+		},
+		"stream window too small": {
+			hs: &projcontour.HTTP2Settings{
+				InitialStreamWindowSize: 1024,
+			},
+			wantErr: "http2: initialStreamWindowSize must be in the range 65535-2147483647",
+		},
+		"connection window too large": {
+			hs: &projcontour.HTTP2Settings{
+				InitialConnectionWindowSize: 1 << 31,
+			},
+			wantErr: "http2: initialConnectionWindowSize must be in the range 65535-2147483647",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := http2Settings(tc.hs)
+			if tc.wantErr != "" {
+				if err == nil {
+					t.Fatalf("expected error %q, got nil", tc.wantErr)
+				}
+				assert.Equal(t, tc.wantErr, err.Error())
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestCircuitBreakerPolicy(t *testing.T) {
+	tests := map[string]struct {
+		cb   *projcontour.CircuitBreakerPolicy
+		want *CircuitBreakerPolicy
+	}{
+		"nil": {
+			cb:   nil,
+			want: nil,
+		},
+		"empty": {
+			cb:   &projcontour.CircuitBreakerPolicy{},
+			want: &CircuitBreakerPolicy{},
+		},
+		"default only": {
+			cb: &projcontour.CircuitBreakerPolicy{
+				Default: &projcontour.CircuitBreakerThresholds{
+					MaxConnections:     1000,
+					MaxPendingRequests: 500,
+				},
+			},
+			want: &CircuitBreakerPolicy{
+				Default: &CircuitBreakerThresholds{
+					MaxConnections:     1000,
+					MaxPendingRequests: 500,
+				},
+			},
+		},
+		"default and high": {
+			cb: &projcontour.CircuitBreakerPolicy{
+				Default: &projcontour.CircuitBreakerThresholds{
+					MaxRequests: 100,
+				},
+				High: &projcontour.CircuitBreakerThresholds{
+					MaxRequests: 200,
+					MaxRetries:  10,
+				},
+			},
+			want: &CircuitBreakerPolicy{
+				Default: &CircuitBreakerThresholds{
+					MaxRequests: 100,
+				},
+				High: &CircuitBreakerThresholds{
+					MaxRequests: 200,
+					MaxRetries:  10,
+				},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := circuitBreakerPolicy(tc.cb)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
 func TestParseTimeout(t *testing.T) {
 	tests := map[string]struct {
 		duration string
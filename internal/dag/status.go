@@ -15,7 +15,9 @@ package dag
 
 import (
 	ingressroutev1 "github.com/projectcontour/contour/apis/contour/v1beta1"
+	gatewayapi "github.com/projectcontour/contour/apis/networking/v1alpha1"
 	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/k8s"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -31,10 +33,22 @@ type Status struct {
 	Status      string
 	Description string
 	Vhost       string
+
+	// Errors holds each problem which made the object invalid.
+	// The first is also reported as the Description.
+	Errors []string
+
+	// Warnings holds problems which did not make the object invalid.
+	Warnings []string
 }
 
 type StatusWriter struct {
 	statuses map[Meta]Status
+
+	// gatewayStatuses holds the status of each Gateway API object.
+	// They are kept apart as their names may be shared with an
+	// IngressRoute or HTTPProxy.
+	gatewayStatuses map[objectKey]Status
 }
 
 type Object interface {
@@ -42,9 +56,11 @@ type Object interface {
 }
 
 type ObjectStatusWriter struct {
-	sw     *StatusWriter
-	obj    Object
-	values map[string]string
+	sw       *StatusWriter
+	obj      Object
+	values   map[string]string
+	errors   []string
+	warnings []string
 }
 
 // WithObject returns an ObjectStatusWriter that can be used to set the state of
@@ -75,13 +91,25 @@ func (sw *StatusWriter) commit(osw *ObjectStatusWriter) {
 		name:      osw.obj.GetObjectMeta().GetName(),
 		namespace: osw.obj.GetObjectMeta().GetNamespace(),
 	}
-	if _, ok := sw.statuses[m]; !ok {
-		// only record the first status event
-		sw.statuses[m] = Status{
-			Object:      osw.obj,
-			Status:      osw.values["status"],
-			Description: osw.values["description"],
-			Vhost:       osw.values["vhost"],
+	st := Status{
+		Object:      osw.obj,
+		Status:      osw.values["status"],
+		Description: osw.values["description"],
+		Vhost:       osw.values["vhost"],
+		Errors:      osw.errors,
+		Warnings:    osw.warnings,
+	}
+
+	switch osw.obj.(type) {
+	case *gatewayapi.GatewayClass, *gatewayapi.Gateway, *gatewayapi.HTTPRoute:
+		key := objectKey{kind: k8s.KindOf(osw.obj), Meta: m}
+		if _, ok := sw.gatewayStatuses[key]; !ok {
+			sw.gatewayStatuses[key] = st
+		}
+	default:
+		if _, ok := sw.statuses[m]; !ok {
+			// only record the first status event
+			sw.statuses[m] = st
 		}
 	}
 }
@@ -90,23 +118,51 @@ func (osw *ObjectStatusWriter) WithValue(key, val string) *ObjectStatusWriter {
 	return osw
 }
 
+// SetInvalid marks the object invalid, recording desc as one of its
+// errors. The first error recorded becomes the object's description.
 func (osw *ObjectStatusWriter) SetInvalid(desc string) {
-	osw.WithValue("description", desc).WithValue("status", StatusInvalid)
+	if len(osw.errors) == 0 {
+		osw.WithValue("description", desc)
+	}
+	osw.errors = append(osw.errors, desc)
+	osw.WithValue("status", StatusInvalid)
+}
+
+// AddWarning records a problem with the object which does not
+// make it invalid.
+func (osw *ObjectStatusWriter) AddWarning(msg string) {
+	osw.warnings = append(osw.warnings, msg)
 }
 
+// IsInvalid returns true if any errors have been recorded.
+func (osw *ObjectStatusWriter) IsInvalid() bool {
+	return len(osw.errors) > 0
+}
+
+// SetValid marks the object valid, unless errors have been recorded.
 func (osw *ObjectStatusWriter) SetValid() {
+	if osw.IsInvalid() {
+		return
+	}
 	switch osw.obj.(type) {
 	case *projcontour.HTTPProxy:
 		osw.WithValue("description", "valid HTTPProxy").WithValue("status", StatusValid)
 	case *ingressroutev1.IngressRoute:
 		osw.WithValue("description", "valid IngressRoute").WithValue("status", StatusValid)
+	case *gatewayapi.GatewayClass:
+		osw.WithValue("description", "valid GatewayClass").WithValue("status", StatusValid)
+	case *gatewayapi.Gateway:
+		osw.WithValue("description", "valid Gateway").WithValue("status", StatusValid)
+	case *gatewayapi.HTTPRoute:
+		osw.WithValue("description", "valid HTTPRoute").WithValue("status", StatusValid)
 	default:
 		// not a supported type
 	}
 }
 
 // WithObject returns a new ObjectStatusWriter with a copy of the current
-// ObjectStatusWriter's values, including its status if set. This is convenient if
+// ObjectStatusWriter's values, including its status if set. Errors and
+// warnings are not copied as they belong to the current object. This is convenient if
 // the object shares a relationship with its parent. The caller should arrange for
 // the commit function to be called to write the final status of the object.
 func (osw *ObjectStatusWriter) WithObject(obj Object) (_ *ObjectStatusWriter, commit func()) {
@@ -1807,6 +1807,338 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 		},
 	}
 
+	// proxy48 has more than one invalid route
+	proxy48 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "many-errors",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{
+					{Name: "missing", Port: 9000},
+				},
+			}, {
+				Conditions: []projcontour.Condition{{
+					Prefix: "api",
+				}},
+				Services: []projcontour.Service{
+					{Name: s1.Name, Port: 8080},
+				},
+			}, {
+				Services: []projcontour.Service{
+					{Name: s1.Name, Port: 80000},
+				},
+			}},
+		},
+	}
+
+	// proxy49 has a websocket route with more than one service
+	proxy49 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "websockets",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{
+					{Name: s1.Name, Port: 8080},
+				},
+			}, {
+				Conditions: []projcontour.Condition{{
+					Prefix: "/ws",
+				}},
+				EnableWebsockets: true,
+				Services: []projcontour.Service{
+					{Name: s1.Name, Port: 8080},
+					{Name: s1.Name, Port: 8080},
+				},
+			}},
+		},
+	}
+
+	// proxy50 has an annotation which is not supported on HTTPProxy
+	proxy50 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "annotated",
+			Namespace: s1.Namespace,
+			Annotations: map[string]string{
+				"projectcontour.io/websocket-routes": "/",
+			},
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{
+					{Name: s1.Name, Port: 8080},
+				},
+			}},
+		},
+	}
+
+	// invalidSecret is a TLS secret without a private key
+	invalidSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "invalid-cert",
+			Namespace: s1.Namespace,
+		},
+		Type: v1.SecretTypeTLS,
+		Data: map[string][]byte{
+			v1.TLSCertKey: []byte(CERTIFICATE),
+		},
+	}
+
+	// proxy51 refers to invalidSecret
+	proxy51 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "invalid-cert",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &projcontour.TLS{
+					SecretName: invalidSecret.Name,
+				},
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{
+					{Name: s1.Name, Port: 8080},
+				},
+			}},
+		},
+	}
+
+	s13 := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "auth",
+			Namespace: "platform",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Name:       "http",
+				Protocol:   "TCP",
+				Port:       8080,
+				TargetPort: intstr.FromInt(8080),
+			}},
+		},
+	}
+
+	// proxy52 routes to a service in another namespace
+	proxy52 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cross-namespace",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{
+					{Name: s13.Name, Namespace: s13.Namespace, Port: 8080},
+				},
+			}},
+		},
+	}
+
+	// proxy53 is a tcpproxy routing to a service in another namespace
+	proxy53 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cross-namespace-tcpproxy",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "tcpproxy.example.com",
+				TLS: &projcontour.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &projcontour.TCPProxy{
+				Services: []projcontour.Service{
+					{Name: s13.Name, Namespace: s13.Namespace, Port: 8080},
+				},
+			},
+		},
+	}
+
+	// proxy54 has a health check policy without a path
+	proxy54 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "healthcheck-missing-path",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				HealthCheckPolicy: &projcontour.HTTPHealthCheckPolicy{
+					IntervalSeconds: 5,
+				},
+				Services: []projcontour.Service{
+					{Name: s1.Name, Port: 8080},
+				},
+			}},
+		},
+	}
+
+	// proxy55 has a grpc health check on a service without h2 or h2c
+	proxy55 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "grpc-healthcheck",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				HealthCheckPolicy: &projcontour.HTTPHealthCheckPolicy{
+					GRPC: &projcontour.GRPCHealthCheck{},
+				},
+				Services: []projcontour.Service{
+					{Name: s1.Name, Port: 8080},
+				},
+			}},
+		},
+	}
+
+	// proxy56 is valid because its service sets the h2c protocol
+	// required by its grpc health check.
+	proxy56 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "grpc-healthcheck",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				HealthCheckPolicy: &projcontour.HTTPHealthCheckPolicy{
+					GRPC: &projcontour.GRPCHealthCheck{},
+				},
+				Services: []projcontour.Service{
+					{Name: s1.Name, Port: 8080, Protocol: "h2c"},
+				},
+			}},
+		},
+	}
+
+	// proxy57 is invalid because http2 settings require an
+	// HTTP/2 protocol.
+	proxy57 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "http2-settings",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name:     s1.Name,
+					Port:     8080,
+					Protocol: "h1",
+					HTTP2: &projcontour.HTTP2Settings{
+						MaxConcurrentStreams: 100,
+					},
+				}},
+			}},
+		},
+	}
+
+	// proxy58 is invalid because tcpproxy services cannot use h2.
+	proxy58 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "simple",
+			Namespace: "roots",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "passthrough.example.com",
+				TLS: &projcontour.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &projcontour.TCPProxy{
+				Services: []projcontour.Service{{
+					Name:     s1.Name,
+					Port:     8080,
+					Protocol: "h2",
+				}},
+			},
+		},
+	}
+
+	upstream := func(addrs ...string) *projcontour.Upstream {
+		u := &projcontour.Upstream{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "legacy",
+				Namespace: s1.Namespace,
+			},
+		}
+		for _, addr := range addrs {
+			u.Spec.Endpoints = append(u.Spec.Endpoints, projcontour.UpstreamEndpoint{Address: addr})
+		}
+		return u
+	}
+
+	upstreamProxy := func(namespace string) *projcontour.HTTPProxy {
+		return &projcontour.HTTPProxy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "legacy",
+				Namespace: s1.Namespace,
+			},
+			Spec: projcontour.HTTPProxySpec{
+				VirtualHost: &projcontour.VirtualHost{
+					Fqdn: "example.com",
+				},
+				Routes: []projcontour.Route{{
+					Services: []projcontour.Service{{
+						Name:      "legacy",
+						Namespace: namespace,
+						Kind:      "Upstream",
+						Port:      8080,
+					}},
+				}},
+			},
+		}
+	}
+
+	// proxy59 routes to an Upstream in its own namespace.
+	proxy59 := upstreamProxy("")
+
+	// proxy60 is invalid because upstreams cannot be referenced
+	// across namespaces.
+	proxy60 := upstreamProxy("other")
+
+	serviceDelegation := func(targets ...string) *projcontour.ServiceDelegation {
+		return &projcontour.ServiceDelegation{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "delegation",
+				Namespace: s13.Namespace,
+			},
+			Spec: projcontour.ServiceDelegationSpec{
+				Delegations: []projcontour.DelegatedService{{
+					ServiceName:      s13.Name,
+					TargetNamespaces: targets,
+				}},
+			},
+		}
+	}
+
 	tests := map[string]struct {
 		objs []interface{}
 		want map[Meta]Status
@@ -1820,26 +2152,26 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 		"invalid port in service": {
 			objs: []interface{}{ir2},
 			want: map[Meta]Status{
-				{name: ir2.Name, namespace: ir2.Namespace}: {Object: ir2, Status: "invalid", Description: `route "/foo": service "home": port must be in the range 1-65535`, Vhost: "example.com"},
+				{name: ir2.Name, namespace: ir2.Namespace}: {Object: ir2, Status: "invalid", Description: `route "/foo": service "home": port must be in the range 1-65535`, Errors: []string{`route "/foo": service "home": port must be in the range 1-65535`}, Vhost: "example.com"},
 			},
 		},
 		"root ingressroute outside of roots namespace": {
 			objs: []interface{}{ir3},
 			want: map[Meta]Status{
-				{name: ir3.Name, namespace: ir3.Namespace}: {Object: ir3, Status: "invalid", Description: "root IngressRoute cannot be defined in this namespace"},
+				{name: ir3.Name, namespace: ir3.Namespace}: {Object: ir3, Status: "invalid", Description: "root IngressRoute cannot be defined in this namespace", Errors: []string{"root IngressRoute cannot be defined in this namespace"}},
 			},
 		},
 		"delegated route's match prefix does not match parent's prefix": {
 			objs: []interface{}{ir1, ir4, s4},
 			want: map[Meta]Status{
 				{name: ir1.Name, namespace: ir1.Namespace}: {Object: ir1, Status: "valid", Description: "valid IngressRoute", Vhost: "example.com"},
-				{name: ir4.Name, namespace: ir4.Namespace}: {Object: ir4, Status: "invalid", Description: `the path prefix "/doesnotmatch" does not match the parent's path prefix "/prefix"`},
+				{name: ir4.Name, namespace: ir4.Namespace}: {Object: ir4, Status: "invalid", Description: `the path prefix "/doesnotmatch" does not match the parent's path prefix "/prefix"`, Errors: []string{`the path prefix "/doesnotmatch" does not match the parent's path prefix "/prefix"`}},
 			},
 		},
 		"root ingressroute does not specify FQDN": {
 			objs: []interface{}{ir13},
 			want: map[Meta]Status{
-				{name: ir13.Name, namespace: ir13.Namespace}: {Object: ir13, Status: "invalid", Description: "Spec.VirtualHost.Fqdn must be specified"},
+				{name: ir13.Name, namespace: ir13.Namespace}: {Object: ir13, Status: "invalid", Description: "Spec.VirtualHost.Fqdn must be specified", Errors: []string{"Spec.VirtualHost.Fqdn must be specified"}},
 			},
 		},
 		"self-edge produces a cycle": {
@@ -1849,6 +2181,7 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					Object:      ir6,
 					Status:      "invalid",
 					Description: "root ingressroute cannot delegate to another root ingressroute",
+					Errors:      []string{"root ingressroute cannot delegate to another root ingressroute"},
 					Vhost:       "example.com",
 				},
 			},
@@ -1866,13 +2199,14 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					Object:      ir8,
 					Status:      "invalid",
 					Description: "route creates a delegation cycle: roots/parent -> roots/child -> roots/child",
+					Errors:      []string{"route creates a delegation cycle: roots/parent -> roots/child -> roots/child"},
 				},
 			},
 		},
 		"route has a list of services and also delegates": {
 			objs: []interface{}{ir9},
 			want: map[Meta]Status{
-				{name: ir9.Name, namespace: ir9.Namespace}: {Object: ir9, Status: "invalid", Description: `route "/foo": cannot specify services and delegate in the same route`, Vhost: "example.com"},
+				{name: ir9.Name, namespace: ir9.Namespace}: {Object: ir9, Status: "invalid", Description: `route "/foo": cannot specify services and delegate in the same route`, Errors: []string{`route "/foo": cannot specify services and delegate in the same route`}, Vhost: "example.com"},
 			},
 		},
 		"ingressroute is an orphaned route": {
@@ -1885,21 +2219,21 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 			objs: []interface{}{ir10, ir11, ir12, s6, s7},
 			want: map[Meta]Status{
 				{name: ir11.Name, namespace: ir11.Namespace}: {Object: ir11, Status: "valid", Description: "valid IngressRoute"},
-				{name: ir12.Name, namespace: ir12.Namespace}: {Object: ir12, Status: "invalid", Description: `route "/bar": service "foo3": port must be in the range 1-65535`},
+				{name: ir12.Name, namespace: ir12.Namespace}: {Object: ir12, Status: "invalid", Description: `route "/bar": service "foo3": port must be in the range 1-65535`, Errors: []string{`route "/bar": service "foo3": port must be in the range 1-65535`}},
 				{name: ir10.Name, namespace: ir10.Namespace}: {Object: ir10, Status: "valid", Description: "valid IngressRoute", Vhost: "example.com"},
 			},
 		},
 		"invalid parent orphans children": {
 			objs: []interface{}{ir14, ir11},
 			want: map[Meta]Status{
-				{name: ir14.Name, namespace: ir14.Namespace}: {Object: ir14, Status: "invalid", Description: "Spec.VirtualHost.Fqdn must be specified"},
+				{name: ir14.Name, namespace: ir14.Namespace}: {Object: ir14, Status: "invalid", Description: "Spec.VirtualHost.Fqdn must be specified", Errors: []string{"Spec.VirtualHost.Fqdn must be specified"}},
 				{name: ir11.Name, namespace: ir11.Namespace}: {Object: ir11, Status: "orphaned", Description: "this IngressRoute is not part of a delegation chain from a root IngressRoute"},
 			},
 		},
 		"multi-parent children is not orphaned when one of the parents is invalid": {
 			objs: []interface{}{ir14, ir11, ir10, s5, s6},
 			want: map[Meta]Status{
-				{name: ir14.Name, namespace: ir14.Namespace}: {Object: ir14, Status: "invalid", Description: "Spec.VirtualHost.Fqdn must be specified"},
+				{name: ir14.Name, namespace: ir14.Namespace}: {Object: ir14, Status: "invalid", Description: "Spec.VirtualHost.Fqdn must be specified", Errors: []string{"Spec.VirtualHost.Fqdn must be specified"}},
 				{name: ir11.Name, namespace: ir11.Namespace}: {Object: ir11, Status: "valid", Description: "valid IngressRoute"},
 				{name: ir10.Name, namespace: ir10.Namespace}: {Object: ir10, Status: "valid", Description: "valid IngressRoute", Vhost: "example.com"},
 			},
@@ -1907,7 +2241,7 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 		"invalid FQDN contains wildcard": {
 			objs: []interface{}{ir15},
 			want: map[Meta]Status{
-				{name: ir15.Name, namespace: ir15.Namespace}: {Object: ir15, Status: "invalid", Description: `Spec.VirtualHost.Fqdn "example.*.com" cannot use wildcards`, Vhost: "example.*.com"},
+				{name: ir15.Name, namespace: ir15.Namespace}: {Object: ir15, Status: "invalid", Description: `Spec.VirtualHost.Fqdn "example.*.com" cannot use wildcards`, Errors: []string{`Spec.VirtualHost.Fqdn "example.*.com" cannot use wildcards`}, Vhost: "example.*.com"},
 			},
 		},
 		"missing service shows invalid status": {
@@ -1917,6 +2251,7 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					Object:      ir16,
 					Status:      "invalid",
 					Description: `Service [invalid:8080] is invalid or missing`,
+					Errors:      []string{`Service [invalid:8080] is invalid or missing`},
 					Vhost:       ir16.Spec.VirtualHost.Fqdn,
 				},
 			},
@@ -1939,12 +2274,14 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					Object:      ir17,
 					Status:      StatusInvalid,
 					Description: `fqdn "example.com" is used in multiple IngressRoutes: roots/example-com, roots/other-example`,
+					Errors:      []string{`fqdn "example.com" is used in multiple IngressRoutes: roots/example-com, roots/other-example`},
 					Vhost:       "example.com",
 				},
 				{name: ir18.Name, namespace: ir18.Namespace}: {
 					Object:      ir18,
 					Status:      StatusInvalid,
 					Description: `fqdn "example.com" is used in multiple IngressRoutes: roots/example-com, roots/other-example`,
+					Errors:      []string{`fqdn "example.com" is used in multiple IngressRoutes: roots/example-com, roots/other-example`},
 					Vhost:       "example.com",
 				},
 			},
@@ -1956,12 +2293,14 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					Object:      ir20,
 					Status:      StatusInvalid,
 					Description: `fqdn "blog.containersteve.com" is used in multiple IngressRoutes: marketing/blog, roots/root-blog`,
+					Errors:      []string{`fqdn "blog.containersteve.com" is used in multiple IngressRoutes: marketing/blog, roots/root-blog`},
 					Vhost:       "blog.containersteve.com",
 				},
 				{name: ir21.Name, namespace: ir21.Namespace}: {
 					Object:      ir21,
 					Status:      StatusInvalid,
 					Description: `fqdn "blog.containersteve.com" is used in multiple IngressRoutes: marketing/blog, roots/root-blog`,
+					Errors:      []string{`fqdn "blog.containersteve.com" is used in multiple IngressRoutes: marketing/blog, roots/root-blog`},
 					Vhost:       "blog.containersteve.com",
 				},
 			},
@@ -1973,6 +2312,7 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					Object:      ir22,
 					Status:      StatusInvalid,
 					Description: "root ingressroute cannot delegate to another root ingressroute",
+					Errors:      []string{"root ingressroute cannot delegate to another root ingressroute"},
 					Vhost:       "blog.containersteve.com",
 				},
 				{name: ir23.Name, namespace: ir23.Namespace}: {
@@ -2008,6 +2348,7 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					Object:      ir25,
 					Status:      StatusInvalid,
 					Description: sec2.Namespace + "/" + sec2.Name + ": certificate delegation not permitted",
+					Errors:      []string{sec2.Namespace + "/" + sec2.Name + ": certificate delegation not permitted"},
 					Vhost:       ir25.Spec.VirtualHost.Fqdn,
 				},
 			},
@@ -2023,6 +2364,7 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					Object:      ir26,
 					Status:      StatusInvalid,
 					Description: sec2.Namespace + "/" + sec2.Name + ": certificate delegation not permitted",
+					Errors:      []string{sec2.Namespace + "/" + sec2.Name + ": certificate delegation not permitted"},
 					Vhost:       ir26.Spec.VirtualHost.Fqdn,
 				},
 			},
@@ -2038,6 +2380,7 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					Object:      proxy19,
 					Status:      StatusInvalid,
 					Description: sec2.Namespace + "/" + sec2.Name + ": certificate delegation not permitted",
+					Errors:      []string{sec2.Namespace + "/" + sec2.Name + ": certificate delegation not permitted"},
 					Vhost:       proxy19.Spec.VirtualHost.Fqdn,
 				},
 			},
@@ -2068,6 +2411,7 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					Object:      ir28,
 					Status:      StatusInvalid,
 					Description: "TLS Secret [heptio-contour/ssl-cert] not found or is malformed",
+					Errors:      []string{"TLS Secret [heptio-contour/ssl-cert] not found or is malformed"},
 					Vhost:       ir28.Spec.VirtualHost.Fqdn,
 				},
 			},
@@ -2105,19 +2449,19 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 		"proxy invalid port in service": {
 			objs: []interface{}{proxy2},
 			want: map[Meta]Status{
-				{name: proxy2.Name, namespace: proxy2.Namespace}: {Object: proxy2, Status: "invalid", Description: `service "home": port must be in the range 1-65535`, Vhost: "example.com"},
+				{name: proxy2.Name, namespace: proxy2.Namespace}: {Object: proxy2, Status: "invalid", Description: `service "home": port must be in the range 1-65535`, Errors: []string{`service "home": port must be in the range 1-65535`}, Vhost: "example.com"},
 			},
 		},
 		"root proxy outside of roots namespace": {
 			objs: []interface{}{proxy3},
 			want: map[Meta]Status{
-				{name: proxy3.Name, namespace: proxy3.Namespace}: {Object: proxy3, Status: "invalid", Description: "root HTTPProxy cannot be defined in this namespace"},
+				{name: proxy3.Name, namespace: proxy3.Namespace}: {Object: proxy3, Status: "invalid", Description: "root HTTPProxy cannot be defined in this namespace", Errors: []string{"root HTTPProxy cannot be defined in this namespace"}},
 			},
 		},
 		"root proxy does not specify FQDN": {
 			objs: []interface{}{proxy13},
 			want: map[Meta]Status{
-				{name: proxy13.Name, namespace: proxy13.Namespace}: {Object: proxy13, Status: "invalid", Description: "Spec.VirtualHost.Fqdn must be specified"},
+				{name: proxy13.Name, namespace: proxy13.Namespace}: {Object: proxy13, Status: "invalid", Description: "Spec.VirtualHost.Fqdn must be specified", Errors: []string{"Spec.VirtualHost.Fqdn must be specified"}},
 			},
 		},
 		"proxy self-edge produces a cycle": {
@@ -2127,7 +2471,11 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					Object:      proxy6,
 					Status:      "invalid",
 					Description: "root httpproxy cannot delegate to another root httpproxy",
-					Vhost:       "example.com",
+					Errors: []string{
+						"root httpproxy cannot delegate to another root httpproxy",
+						"Service [green:80] is invalid or missing",
+					},
+					Vhost: "example.com",
 				},
 			},
 		},
@@ -2144,6 +2492,7 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					Object:      proxy8,
 					Status:      "invalid",
 					Description: "include creates a delegation cycle: roots/parent -> roots/child -> roots/child",
+					Errors:      []string{"include creates a delegation cycle: roots/parent -> roots/child -> roots/child"},
 				},
 			},
 		},
@@ -2156,14 +2505,14 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 		"proxy invalid parent orphans children": {
 			objs: []interface{}{proxy14, proxy11},
 			want: map[Meta]Status{
-				{name: proxy14.Name, namespace: proxy14.Namespace}: {Object: proxy14, Status: "invalid", Description: "Spec.VirtualHost.Fqdn must be specified"},
+				{name: proxy14.Name, namespace: proxy14.Namespace}: {Object: proxy14, Status: "invalid", Description: "Spec.VirtualHost.Fqdn must be specified", Errors: []string{"Spec.VirtualHost.Fqdn must be specified"}},
 				{name: proxy11.Name, namespace: proxy11.Namespace}: {Object: proxy11, Status: "orphaned", Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy"},
 			},
 		},
 		"proxy invalid FQDN contains wildcard": {
 			objs: []interface{}{proxy15},
 			want: map[Meta]Status{
-				{name: proxy15.Name, namespace: proxy15.Namespace}: {Object: proxy15, Status: "invalid", Description: `Spec.VirtualHost.Fqdn "example.*.com" cannot use wildcards`, Vhost: "example.*.com"},
+				{name: proxy15.Name, namespace: proxy15.Namespace}: {Object: proxy15, Status: "invalid", Description: `Spec.VirtualHost.Fqdn "example.*.com" cannot use wildcards`, Errors: []string{`Spec.VirtualHost.Fqdn "example.*.com" cannot use wildcards`}, Vhost: "example.*.com"},
 			},
 		},
 		"proxy missing service shows invalid status": {
@@ -2173,6 +2522,7 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					Object:      proxy16,
 					Status:      "invalid",
 					Description: `Service [invalid:8080] is invalid or missing`,
+					Errors:      []string{`Service [invalid:8080] is invalid or missing`},
 					Vhost:       proxy16.Spec.VirtualHost.Fqdn,
 				},
 			},
@@ -2184,12 +2534,14 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					Object:      proxy17,
 					Status:      StatusInvalid,
 					Description: `fqdn "example.com" is used in multiple HTTPProxies: roots/example-com, roots/other-example`,
+					Errors:      []string{`fqdn "example.com" is used in multiple HTTPProxies: roots/example-com, roots/other-example`},
 					Vhost:       "example.com",
 				},
 				{name: proxy18.Name, namespace: proxy18.Namespace}: {
 					Object:      proxy18,
 					Status:      StatusInvalid,
 					Description: `fqdn "example.com" is used in multiple HTTPProxies: roots/example-com, roots/other-example`,
+					Errors:      []string{`fqdn "example.com" is used in multiple HTTPProxies: roots/example-com, roots/other-example`},
 					Vhost:       "example.com",
 				},
 			},
@@ -2201,12 +2553,14 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					Object:      proxy20,
 					Status:      StatusInvalid,
 					Description: `fqdn "blog.containersteve.com" is used in multiple HTTPProxies: marketing/blog, roots/root-blog`,
+					Errors:      []string{`fqdn "blog.containersteve.com" is used in multiple HTTPProxies: marketing/blog, roots/root-blog`},
 					Vhost:       "blog.containersteve.com",
 				},
 				{name: proxy21.Name, namespace: proxy21.Namespace}: {
 					Object:      proxy21,
 					Status:      StatusInvalid,
 					Description: `fqdn "blog.containersteve.com" is used in multiple HTTPProxies: marketing/blog, roots/root-blog`,
+					Errors:      []string{`fqdn "blog.containersteve.com" is used in multiple HTTPProxies: marketing/blog, roots/root-blog`},
 					Vhost:       "blog.containersteve.com",
 				},
 			},
@@ -2218,6 +2572,7 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					Object:      proxy22,
 					Status:      StatusInvalid,
 					Description: "root httpproxy cannot delegate to another root httpproxy",
+					Errors:      []string{"root httpproxy cannot delegate to another root httpproxy"},
 					Vhost:       "blog.containersteve.com",
 				},
 				{name: proxy23.Name, namespace: proxy23.Namespace}: {
@@ -2262,6 +2617,7 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					Object:      proxy27,
 					Status:      "invalid",
 					Description: "only one service per route may be nominated as mirror",
+					Errors:      []string{"only one service per route may be nominated as mirror"},
 					Vhost:       "example.com",
 				},
 			},
@@ -2273,6 +2629,7 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					Object:      proxy32,
 					Status:      "invalid",
 					Description: "route: More than one prefix is not allowed in a condition block",
+					Errors:      []string{"route: More than one prefix is not allowed in a condition block"},
 					Vhost:       "example.com",
 				},
 			},
@@ -2284,6 +2641,7 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					Object:      proxy33,
 					Status:      "invalid",
 					Description: "include: More than one prefix is not allowed in a condition block",
+					Errors:      []string{"include: More than one prefix is not allowed in a condition block"},
 					Vhost:       "example.com",
 				}, {name: proxy34.Name, namespace: proxy34.Namespace}: {
 					Object:      proxy34,
@@ -2299,6 +2657,7 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					Object:      proxy35,
 					Status:      "invalid",
 					Description: "route: Prefix conditions must start with /, api was supplied",
+					Errors:      []string{"route: Prefix conditions must start with /, api was supplied"},
 					Vhost:       "example.com",
 				},
 			},
@@ -2310,6 +2669,7 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					Object:      proxy36,
 					Status:      "invalid",
 					Description: "include: Prefix conditions must start with /, api was supplied",
+					Errors:      []string{"include: Prefix conditions must start with /, api was supplied"},
 					Vhost:       "example.com",
 				}, {name: proxy34.Name, namespace: proxy34.Namespace}: {
 					Object:      proxy34,
@@ -2321,7 +2681,7 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 		"duplicate route condition headers": {
 			objs: []interface{}{proxy28, s4},
 			want: map[Meta]Status{
-				{name: proxy28.Name, namespace: proxy28.Namespace}: {Object: proxy28, Status: "invalid", Description: "cannot specify duplicate header 'exact match' conditions in the same route", Vhost: "example.com"},
+				{name: proxy28.Name, namespace: proxy28.Namespace}: {Object: proxy28, Status: "invalid", Description: "cannot specify duplicate header 'exact match' conditions in the same route", Errors: []string{"cannot specify duplicate header 'exact match' conditions in the same route"}, Vhost: "example.com"},
 			},
 		},
 		"duplicate valid route condition headers": {
@@ -2334,13 +2694,13 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 			objs: []interface{}{proxy29, proxy30, s4},
 			want: map[Meta]Status{
 				{name: proxy29.Name, namespace: proxy29.Namespace}: {Object: proxy29, Status: "valid", Description: "valid HTTPProxy", Vhost: "example.com"},
-				{name: proxy30.Name, namespace: proxy30.Namespace}: {Object: proxy30, Status: "invalid", Description: "cannot specify duplicate header 'exact match' conditions in the same route", Vhost: ""},
+				{name: proxy30.Name, namespace: proxy30.Namespace}: {Object: proxy30, Status: "invalid", Description: "cannot specify duplicate header 'exact match' conditions in the same route", Errors: []string{"cannot specify duplicate header 'exact match' conditions in the same route"}, Vhost: ""},
 			},
 		},
 		"duplicate path conditions on an include": {
 			objs: []interface{}{proxy41, proxy41a, proxy41b, s4, s11, s12},
 			want: map[Meta]Status{
-				{name: proxy41.Name, namespace: proxy41.Namespace}:   {Object: proxy41, Status: "invalid", Description: "duplicate conditions defined on an include", Vhost: "example.com"},
+				{name: proxy41.Name, namespace: proxy41.Namespace}:   {Object: proxy41, Status: "invalid", Description: "duplicate conditions defined on an include", Errors: []string{"duplicate conditions defined on an include"}, Vhost: "example.com"},
 				{name: proxy41a.Name, namespace: proxy41a.Namespace}: {Object: proxy41a, Status: "orphaned", Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy", Vhost: ""},
 				{name: proxy41b.Name, namespace: proxy41b.Namespace}: {Object: proxy41b, Status: "orphaned", Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy", Vhost: ""},
 			},
@@ -2348,7 +2708,7 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 		"duplicate header conditions on an include": {
 			objs: []interface{}{proxy42, proxy41a, proxy41b, s4, s11, s12},
 			want: map[Meta]Status{
-				{name: proxy42.Name, namespace: proxy42.Namespace}:   {Object: proxy42, Status: "invalid", Description: "duplicate conditions defined on an include", Vhost: "example.com"},
+				{name: proxy42.Name, namespace: proxy42.Namespace}:   {Object: proxy42, Status: "invalid", Description: "duplicate conditions defined on an include", Errors: []string{"duplicate conditions defined on an include"}, Vhost: "example.com"},
 				{name: proxy41a.Name, namespace: proxy41a.Namespace}: {Object: proxy41a, Status: "orphaned", Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy", Vhost: ""},
 				{name: proxy41b.Name, namespace: proxy41b.Namespace}: {Object: proxy41b, Status: "orphaned", Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy", Vhost: ""},
 			},
@@ -2356,7 +2716,7 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 		"duplicate header+path conditions on an include": {
 			objs: []interface{}{proxy43, proxy41a, proxy41b, s4, s11, s12},
 			want: map[Meta]Status{
-				{name: proxy43.Name, namespace: proxy43.Namespace}:   {Object: proxy43, Status: "invalid", Description: "duplicate conditions defined on an include", Vhost: "example.com"},
+				{name: proxy43.Name, namespace: proxy43.Namespace}:   {Object: proxy43, Status: "invalid", Description: "duplicate conditions defined on an include", Errors: []string{"duplicate conditions defined on an include"}, Vhost: "example.com"},
 				{name: proxy41a.Name, namespace: proxy41a.Namespace}: {Object: proxy41a, Status: "orphaned", Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy", Vhost: ""},
 				{name: proxy41b.Name, namespace: proxy41b.Namespace}: {Object: proxy41b, Status: "orphaned", Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy", Vhost: ""},
 			},
@@ -2368,6 +2728,7 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					Object:      proxy37,
 					Status:      "invalid",
 					Description: "tcpproxy: cannot specify services and include in the same httpproxy",
+					Errors:      []string{"tcpproxy: cannot specify services and include in the same httpproxy"},
 					Vhost:       "passthrough.example.com",
 				},
 			},
@@ -2379,6 +2740,7 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					Object:      proxy37a,
 					Status:      "invalid",
 					Description: "tcpproxy: either services or inclusion must be specified",
+					Errors:      []string{"tcpproxy: either services or inclusion must be specified"},
 					Vhost:       "passthrough.example.com",
 				},
 			},
@@ -2390,6 +2752,7 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					Object:      proxy38,
 					Status:      "invalid",
 					Description: "tcpproxy: include roots/foo not found",
+					Errors:      []string{"tcpproxy: include roots/foo not found"},
 					Vhost:       "passthrough.example.com",
 				},
 			},
@@ -2401,6 +2764,7 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					Object:      proxy38,
 					Status:      "invalid",
 					Description: "root httpproxy cannot delegate to another root httpproxy",
+					Errors:      []string{"root httpproxy cannot delegate to another root httpproxy"},
 					Vhost:       "passthrough.example.com",
 				},
 				{name: proxy39.Name, namespace: proxy39.Namespace}: {
@@ -2435,6 +2799,7 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					Object:      proxy44,
 					Status:      "invalid",
 					Description: "include roots/child not found",
+					Errors:      []string{"include roots/child not found"},
 					Vhost:       "example.com",
 				},
 			},
@@ -2446,6 +2811,7 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					Object:      proxy45,
 					Status:      "invalid",
 					Description: "tcpproxy: service roots/not-found/8080: not found",
+					Errors:      []string{"tcpproxy: service roots/not-found/8080: not found"},
 					Vhost:       "tcpproxy.example.com",
 				},
 			},
@@ -2457,6 +2823,7 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					Object:      proxy46,
 					Status:      "invalid",
 					Description: "tcpproxy: missing tls.passthrough or tls.secretName",
+					Errors:      []string{"tcpproxy: missing tls.passthrough or tls.secretName"},
 					Vhost:       "tcpproxy.example.com",
 				},
 			},
@@ -2468,10 +2835,224 @@ func TestDAGIngressRouteStatus(t *testing.T) {
 					Object:      proxy47,
 					Status:      "invalid",
 					Description: "Service [missing:9000] is invalid or missing",
+					Errors:      []string{"Service [missing:9000] is invalid or missing"},
+					Vhost:       "tcpproxy.example.com",
+				},
+			},
+		},
+		"httpproxy reports every invalid route": {
+			objs: []interface{}{s1, proxy48},
+			want: map[Meta]Status{
+				{name: proxy48.Name, namespace: proxy48.Namespace}: {
+					Object:      proxy48,
+					Status:      "invalid",
+					Description: "Service [missing:9000] is invalid or missing",
+					Errors: []string{
+						"Service [missing:9000] is invalid or missing",
+						"route: Prefix conditions must start with /, api was supplied",
+						`service "kuard": port must be in the range 1-65535`,
+					},
+					Vhost: "example.com",
+				},
+			},
+		},
+		"httpproxy with an unsupported annotation": {
+			objs: []interface{}{s1, proxy50},
+			want: map[Meta]Status{
+				{name: proxy50.Name, namespace: proxy50.Namespace}: {
+					Object:      proxy50,
+					Status:      "valid",
+					Description: "valid HTTPProxy",
+					Warnings:    []string{`ignoring invalid or unsupported annotation "projectcontour.io/websocket-routes"`},
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"httpproxy with a rejected TLS secret": {
+			objs: []interface{}{s1, invalidSecret, proxy51},
+			want: map[Meta]Status{
+				{name: proxy51.Name, namespace: proxy51.Namespace}: {
+					Object:      proxy51,
+					Status:      "invalid",
+					Description: "TLS Secret [invalid-cert] not found or is malformed",
+					Errors:      []string{"TLS Secret [invalid-cert] not found or is malformed"},
+					Warnings:    []string{"TLS Secret [invalid-cert] was rejected: missing TLS private key"},
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"httpproxy w/ service in another namespace without delegation": {
+			objs: []interface{}{s13, proxy52},
+			want: map[Meta]Status{
+				{name: proxy52.Name, namespace: proxy52.Namespace}: {
+					Object:      proxy52,
+					Status:      "invalid",
+					Description: "Service [platform/auth:8080]: delegation not permitted",
+					Errors:      []string{"Service [platform/auth:8080]: delegation not permitted"},
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"httpproxy w/ service in another namespace delegated to another namespace": {
+			objs: []interface{}{s13, serviceDelegation("marketing"), proxy52},
+			want: map[Meta]Status{
+				{name: proxy52.Name, namespace: proxy52.Namespace}: {
+					Object:      proxy52,
+					Status:      "invalid",
+					Description: "Service [platform/auth:8080]: delegation not permitted",
+					Errors:      []string{"Service [platform/auth:8080]: delegation not permitted"},
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"httpproxy w/ service in another namespace delegated to its namespace": {
+			objs: []interface{}{s13, serviceDelegation(proxy52.Namespace), proxy52},
+			want: map[Meta]Status{
+				{name: proxy52.Name, namespace: proxy52.Namespace}: {Object: proxy52, Status: "valid", Description: "valid HTTPProxy", Vhost: "example.com"},
+			},
+		},
+		"httpproxy w/ service in another namespace delegated to all namespaces": {
+			objs: []interface{}{s13, serviceDelegation("*"), proxy52},
+			want: map[Meta]Status{
+				{name: proxy52.Name, namespace: proxy52.Namespace}: {Object: proxy52, Status: "valid", Description: "valid HTTPProxy", Vhost: "example.com"},
+			},
+		},
+		"httpproxy w/ tcpproxy w/ service in another namespace without delegation": {
+			objs: []interface{}{s13, proxy53},
+			want: map[Meta]Status{
+				{name: proxy53.Name, namespace: proxy53.Namespace}: {
+					Object:      proxy53,
+					Status:      "invalid",
+					Description: "tcpproxy: service platform/auth/8080: delegation not permitted",
+					Errors:      []string{"tcpproxy: service platform/auth/8080: delegation not permitted"},
 					Vhost:       "tcpproxy.example.com",
 				},
 			},
 		},
+		"httpproxy w/ tcpproxy w/ service in another namespace delegated to its namespace": {
+			objs: []interface{}{s13, serviceDelegation(proxy53.Namespace), proxy53},
+			want: map[Meta]Status{
+				{name: proxy53.Name, namespace: proxy53.Namespace}: {Object: proxy53, Status: "valid", Description: "valid HTTPProxy", Vhost: "tcpproxy.example.com"},
+			},
+		},
+		"httpproxy w/ health check policy without a path": {
+			objs: []interface{}{s1, proxy54},
+			want: map[Meta]Status{
+				{name: proxy54.Name, namespace: proxy54.Namespace}: {
+					Object:      proxy54,
+					Status:      "invalid",
+					Description: "healthCheckPolicy: path is required",
+					Errors:      []string{"healthCheckPolicy: path is required"},
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"httpproxy w/ grpc health check on an http/1.1 service": {
+			objs: []interface{}{s1, proxy55},
+			want: map[Meta]Status{
+				{name: proxy55.Name, namespace: proxy55.Namespace}: {
+					Object:      proxy55,
+					Status:      "invalid",
+					Description: `service "kuard": grpc health checks require the h2 or h2c protocol`,
+					Errors:      []string{`service "kuard": grpc health checks require the h2 or h2c protocol`},
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"httpproxy w/ grpc health check on an h2c protocol service": {
+			objs: []interface{}{s1, proxy56},
+			want: map[Meta]Status{
+				{name: proxy56.Name, namespace: proxy56.Namespace}: {
+					Object:      proxy56,
+					Status:      "valid",
+					Description: "valid HTTPProxy",
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"httpproxy w/ http2 settings on an h1 protocol service": {
+			objs: []interface{}{s1, proxy57},
+			want: map[Meta]Status{
+				{name: proxy57.Name, namespace: proxy57.Namespace}: {
+					Object:      proxy57,
+					Status:      "invalid",
+					Description: `service "kuard": http2 settings require the h2 or h2c protocol`,
+					Errors:      []string{`service "kuard": http2 settings require the h2 or h2c protocol`},
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"httpproxy w/ tcpproxy w/ h2 protocol service": {
+			objs: []interface{}{s1, proxy58},
+			want: map[Meta]Status{
+				{name: proxy58.Name, namespace: proxy58.Namespace}: {
+					Object:      proxy58,
+					Status:      "invalid",
+					Description: `tcpproxy: service roots/kuard/8080: unsupported protocol "h2"`,
+					Errors:      []string{`tcpproxy: service roots/kuard/8080: unsupported protocol "h2"`},
+					Vhost:       "passthrough.example.com",
+				},
+			},
+		},
+		"httpproxy w/ upstream service": {
+			objs: []interface{}{upstream("10.1.0.1", "10.1.0.2"), proxy59},
+			want: map[Meta]Status{
+				{name: proxy59.Name, namespace: proxy59.Namespace}: {
+					Object:      proxy59,
+					Status:      "valid",
+					Description: "valid HTTPProxy",
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"httpproxy w/ missing upstream": {
+			objs: []interface{}{proxy59},
+			want: map[Meta]Status{
+				{name: proxy59.Name, namespace: proxy59.Namespace}: {
+					Object:      proxy59,
+					Status:      "invalid",
+					Description: `service "legacy": upstream not found`,
+					Errors:      []string{`service "legacy": upstream not found`},
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"httpproxy w/ upstream mixing ip and dns endpoints": {
+			objs: []interface{}{upstream("10.1.0.1", "legacy.example.com"), proxy59},
+			want: map[Meta]Status{
+				{name: proxy59.Name, namespace: proxy59.Namespace}: {
+					Object:      proxy59,
+					Status:      "invalid",
+					Description: `service "legacy": upstream endpoints must be all IP addresses or all DNS names`,
+					Errors:      []string{`service "legacy": upstream endpoints must be all IP addresses or all DNS names`},
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"httpproxy w/ upstream in another namespace": {
+			objs: []interface{}{upstream("10.1.0.1"), proxy60},
+			want: map[Meta]Status{
+				{name: proxy60.Name, namespace: proxy60.Namespace}: {
+					Object:      proxy60,
+					Status:      "invalid",
+					Description: `service "legacy": upstreams must be in the HTTPProxy's namespace`,
+					Errors:      []string{`service "legacy": upstreams must be in the HTTPProxy's namespace`},
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"httpproxy websocket route with multiple services is dropped with a warning": {
+			objs: []interface{}{s1, proxy49},
+			want: map[Meta]Status{
+				{name: proxy49.Name, namespace: proxy49.Namespace}: {
+					Object:      proxy49,
+					Status:      "valid",
+					Description: "valid HTTPProxy",
+					Warnings:    []string{"route: cannot specify multiple services and enable websockets"},
+					Vhost:       "example.com",
+				},
+			},
+		},
 	}
 
 	for name, tc := range tests {
@@ -12,7 +12,7 @@
 // limitations under the License.
 
 // Package debug provides http endpoints for healthcheck, metrics,
-// and pprof debugging.
+// pprof debugging, and unary xDS fetches.
 package debug
 
 import (
@@ -20,7 +20,9 @@ import (
 	"net/http/pprof"
 
 	"github.com/projectcontour/contour/internal/dag"
+	cgrpc "github.com/projectcontour/contour/internal/grpc"
 	"github.com/projectcontour/contour/internal/httpsvc"
+	"k8s.io/client-go/tools/cache"
 )
 
 // Service serves various http endpoints including /debug/pprof.
@@ -28,6 +30,14 @@ type Service struct {
 	httpsvc.Service
 
 	Builder *dag.Builder
+
+	// Resources, if supplied, are served over the
+	// Envoy v2 REST-JSON xDS API.
+	Resources map[string]cgrpc.Resource
+
+	// Synced, if supplied, are waited for before the Resources
+	// are served, so they are not served empty during startup.
+	Synced []cache.InformerSynced
 }
 
 // Start fulfills the g.Start contract.
@@ -35,6 +45,13 @@ type Service struct {
 func (svc *Service) Start(stop <-chan struct{}) error {
 	registerProfile(&svc.ServeMux)
 	registerDotWriter(&svc.ServeMux, svc.Builder)
+	if svc.Resources != nil {
+		go func() {
+			if cache.WaitForCacheSync(stop, svc.Synced...) {
+				cgrpc.RegisterREST(&svc.ServeMux, svc.FieldLogger, svc.Resources)
+			}
+		}()
+	}
 	return svc.Service.Start(stop)
 }
 
@@ -73,14 +73,17 @@ func setup(t *testing.T, opts ...func(*contour.EventHandler)) (cache.ResourceEve
 	log := logrus.New()
 	log.Out = &testWriter{t}
 
+	versions := new(contour.Versions)
 	et := &contour.EndpointsTranslator{
 		FieldLogger: log,
+		Versions:    versions,
 	}
 
 	r := prometheus.NewRegistry()
 	ch := &contour.CacheHandler{
 		Metrics:       metrics.NewMetrics(r),
 		ListenerCache: contour.NewListenerCache(statsAddress, statsPort),
+		Versions:      versions,
 		FieldLogger:   log,
 	}
 
@@ -116,7 +119,7 @@ func setup(t *testing.T, opts ...func(*contour.EventHandler)) (cache.ResourceEve
 		ch.ListenerCache.TypeURL(): &ch.ListenerCache,
 		ch.SecretCache.TypeURL():   &ch.SecretCache,
 		et.TypeURL():               et,
-	}, r)
+	}, r, ch.Metrics, nil)
 
 	var g workgroup.Group
 
@@ -1170,7 +1170,7 @@ func TestRDSIngressRouteOutsideRootNamespaces(t *testing.T) {
 // tested in internal/contour/route_test.go
 func TestRDSIngressClassAnnotation(t *testing.T) {
 	rh, cc, done := setup(t, func(reh *contour.EventHandler) {
-		reh.Builder.Source.IngressClass = "linkerd"
+		reh.Builder.Source.IngressClasses = []string{"linkerd"}
 	})
 	defer done()
 
@@ -92,6 +92,30 @@ func Bootstrap(c *BootstrapConfig) *bootstrap.Bootstrap {
 		},
 	}
 
+	if c.LoadReporting {
+		b.ClusterManager = &bootstrap.ClusterManager{
+			LoadStatsConfig: ConfigSource("contour").GetApiConfigSource(),
+		}
+	}
+
+	if c.LocalCluster != "" {
+		// Envoy's own endpoints are discovered from Contour
+		// as its local cluster, required for zone aware routing.
+		b.StaticResources.Clusters = append(b.StaticResources.Clusters, &api.Cluster{
+			Name:                 "local",
+			ConnectTimeout:       protobuf.Duration(250 * time.Millisecond),
+			ClusterDiscoveryType: ClusterDiscoveryType(api.Cluster_EDS),
+			EdsClusterConfig: &api.Cluster_EdsClusterConfig{
+				EdsConfig:   ConfigSource("contour"),
+				ServiceName: c.LocalCluster,
+			},
+		})
+		if b.ClusterManager == nil {
+			b.ClusterManager = new(bootstrap.ClusterManager)
+		}
+		b.ClusterManager.LocalClusterName = "local"
+	}
+
 	if c.GrpcClientCert != "" || c.GrpcClientKey != "" || c.GrpcCABundle != "" {
 		// If one of the two TLS options is not empty, they all must be not empty
 		if !(c.GrpcClientCert != "" && c.GrpcClientKey != "" && c.GrpcCABundle != "") {
@@ -171,6 +195,15 @@ type BootstrapConfig struct {
 
 	// GrpcClientKey is the filename that contains a client key for secure gRPC with TLS.
 	GrpcClientKey string
+
+	// LoadReporting enables reporting of upstream load to the
+	// management server via the Load Reporting Service.
+	LoadReporting bool
+
+	// LocalCluster is the EDS name of the Service fronting Envoy,
+	// e.g. projectcontour/envoy/http. If set, Envoy discovers its
+	// own endpoints as its local cluster for zone aware routing.
+	LocalCluster string
 }
 
 func (c *BootstrapConfig) xdsAddress() string   { return stringOrDefault(c.XDSAddress, "127.0.0.1") }
@@ -17,6 +17,7 @@ import (
 	"crypto/sha1"
 	"crypto/sha256"
 	"fmt"
+	"net"
 	"strconv"
 	"strings"
 	"time"
@@ -49,10 +50,20 @@ func Cluster(c *dag.Cluster) *v2.Cluster {
 	cluster.Name = Clustername(c)
 	cluster.AltStatName = altStatName(service)
 	cluster.LbPolicy = lbPolicy(c.LoadBalancerPolicy)
+	cluster.CommonLbConfig = commonLbConfig(c.LocalityPolicy)
 	cluster.HealthChecks = edshealthcheck(c)
+	cluster.OutlierDetection = outlierDetection(c.OutlierDetection)
 
-	switch len(service.ExternalName) {
-	case 0:
+	switch {
+	case len(service.StaticEndpoints) > 0:
+		// static endpoints, resolved by DNS unless they are IP addresses
+		if net.ParseIP(service.StaticEndpoints[0].Address) != nil {
+			cluster.ClusterDiscoveryType = ClusterDiscoveryType(v2.Cluster_STATIC)
+		} else {
+			cluster.ClusterDiscoveryType = ClusterDiscoveryType(v2.Cluster_STRICT_DNS)
+		}
+		cluster.LoadAssignment = StaticClusterLoadAssignment(service)
+	case len(service.ExternalName) == 0:
 		// external name not set, cluster will be discovered via EDS
 		cluster.ClusterDiscoveryType = ClusterDiscoveryType(v2.Cluster_EDS)
 		cluster.EdsClusterConfig = edsconfig("contour", service)
@@ -62,12 +73,27 @@ func Cluster(c *dag.Cluster) *v2.Cluster {
 		cluster.LoadAssignment = StaticClusterLoadAssignment(service)
 	}
 
+	if tp := c.ConnectionTimeoutPolicy; tp != nil {
+		if tp.ConnectTimeout > 0 {
+			cluster.ConnectTimeout = protobuf.Duration(tp.ConnectTimeout)
+		}
+		if tp.IdleConnectionTimeout > 0 || tp.MaxConnectionDuration > 0 {
+			cluster.CommonHttpProtocolOptions = &envoy_api_v2_core.HttpProtocolOptions{
+				IdleTimeout:           timeout(tp.IdleConnectionTimeout),
+				MaxConnectionDuration: timeout(tp.MaxConnectionDuration),
+			}
+		}
+	}
+
 	// Drain connections immediately if using healthchecks and the endpoint is known to be removed
 	if c.HealthCheckPolicy != nil {
 		cluster.DrainConnectionsOnHostRemoval = true
 	}
 
-	if anyPositive(service.MaxConnections, service.MaxPendingRequests, service.MaxRequests, service.MaxRetries) {
+	switch {
+	case c.CircuitBreakerPolicy != nil:
+		cluster.CircuitBreakers = circuitBreakers(c.CircuitBreakerPolicy)
+	case anyPositive(service.MaxConnections, service.MaxPendingRequests, service.MaxRequests, service.MaxRetries):
 		cluster.CircuitBreakers = &envoy_cluster.CircuitBreakers{
 			Thresholds: []*envoy_cluster.CircuitBreakers_Thresholds{{
 				MaxConnections:     u32nil(service.MaxConnections),
@@ -78,30 +104,47 @@ func Cluster(c *dag.Cluster) *v2.Cluster {
 		}
 	}
 
-	switch c.Upstream.Protocol {
+	protocol := c.Protocol
+	if protocol == "" {
+		protocol = c.Upstream.Protocol
+	}
+
+	switch protocol {
 	case "tls":
-		cluster.TransportSocket = UpstreamTLSTransportSocket(
-			UpstreamTLSContext(
-				upstreamValidationCACert(c),
-				upstreamValidationSubjectAltName(c),
-			),
-		)
+		cluster.TransportSocket = upstreamTLSTransportSocket(c)
 	case "h2":
-		cluster.TransportSocket = UpstreamTLSTransportSocket(
-			UpstreamTLSContext(
-				upstreamValidationCACert(c),
-				upstreamValidationSubjectAltName(c),
-				"h2",
-			),
-		)
+		cluster.TransportSocket = upstreamTLSTransportSocket(c, "h2")
 		fallthrough
 	case "h2c":
-		cluster.Http2ProtocolOptions = &envoy_api_v2_core.Http2ProtocolOptions{}
+		cluster.Http2ProtocolOptions = http2ProtocolOptions(c.HTTP2Settings)
 	}
 
 	return cluster
 }
 
+// upstreamTLSTransportSocket returns the TLS transport socket of c,
+// offering alpnProtocols.
+func upstreamTLSTransportSocket(c *dag.Cluster, alpnProtocols ...string) *envoy_api_v2_core.TransportSocket {
+	tls := UpstreamTLSContext(
+		upstreamValidationCACert(c),
+		upstreamValidationSubjectAltName(c),
+		alpnProtocols...,
+	)
+	tls.Sni = c.Upstream.SNI
+	return UpstreamTLSTransportSocket(tls)
+}
+
+func http2ProtocolOptions(settings *dag.HTTP2Settings) *envoy_api_v2_core.Http2ProtocolOptions {
+	if settings == nil {
+		return &envoy_api_v2_core.Http2ProtocolOptions{}
+	}
+	return &envoy_api_v2_core.Http2ProtocolOptions{
+		MaxConcurrentStreams:        u32nil(settings.MaxConcurrentStreams),
+		InitialStreamWindowSize:     u32nil(settings.InitialStreamWindowSize),
+		InitialConnectionWindowSize: u32nil(settings.InitialConnectionWindowSize),
+	}
+}
+
 func upstreamValidationCACert(c *dag.Cluster) []byte {
 	if c.UpstreamValidation == nil {
 		// No validation required
@@ -126,11 +169,16 @@ func StaticClusterLoadAssignment(service *dag.Service) *v2.ClusterLoadAssignment
 		service.ServicePort.Name,
 	}
 
-	addr := SocketAddress(service.ExternalName, int(service.ServicePort.Port))
-	return &v2.ClusterLoadAssignment{
-		ClusterName: strings.Join(name, "/"),
-		Endpoints:   Endpoints(addr),
+	if len(service.StaticEndpoints) > 0 {
+		addrs := make([]*envoy_api_v2_core.Address, 0, len(service.StaticEndpoints))
+		for _, ep := range service.StaticEndpoints {
+			addrs = append(addrs, SocketAddress(ep.Address, ep.Port))
+		}
+		return ClusterLoadAssignment(strings.Join(name, "/"), addrs...)
 	}
+
+	addr := SocketAddress(service.ExternalName, int(service.ServicePort.Port))
+	return ClusterLoadAssignment(strings.Join(name, "/"), addr)
 }
 
 func edsconfig(cluster string, service *dag.Service) *v2.Cluster_EdsClusterConfig {
@@ -170,10 +218,58 @@ func edshealthcheck(c *dag.Cluster) []*envoy_api_v2_core.HealthCheck {
 	}
 }
 
+// circuitBreakers returns the *envoy_cluster.CircuitBreakers for cb,
+// or nil if cb sets no limits.
+func circuitBreakers(cb *dag.CircuitBreakerPolicy) *envoy_cluster.CircuitBreakers {
+	var thresholds []*envoy_cluster.CircuitBreakers_Thresholds
+	add := func(priority envoy_api_v2_core.RoutingPriority, t *dag.CircuitBreakerThresholds) {
+		if t == nil || !anyPositive(t.MaxConnections, t.MaxPendingRequests, t.MaxRequests, t.MaxRetries) {
+			return
+		}
+		thresholds = append(thresholds, &envoy_cluster.CircuitBreakers_Thresholds{
+			Priority:           priority,
+			MaxConnections:     u32nil(t.MaxConnections),
+			MaxPendingRequests: u32nil(t.MaxPendingRequests),
+			MaxRequests:        u32nil(t.MaxRequests),
+			MaxRetries:         u32nil(t.MaxRetries),
+		})
+	}
+	add(envoy_api_v2_core.RoutingPriority_DEFAULT, cb.Default)
+	add(envoy_api_v2_core.RoutingPriority_HIGH, cb.High)
+	if len(thresholds) == 0 {
+		return nil
+	}
+	return &envoy_cluster.CircuitBreakers{
+		Thresholds: thresholds,
+	}
+}
+
+// outlierDetection returns the *envoy_cluster.OutlierDetection for od.
+// Unset values are left to Envoy's defaults, except that ejection for
+// gateway errors, which Envoy does not enforce by default, is enforced
+// if it is configured.
+func outlierDetection(od *dag.OutlierDetection) *envoy_cluster.OutlierDetection {
+	if od == nil {
+		return nil
+	}
+	outlier := &envoy_cluster.OutlierDetection{
+		Consecutive_5Xx:    u32nil(od.Consecutive5xxErrors),
+		MaxEjectionPercent: u32nil(od.MaxEjectionPercent),
+	}
+	if od.BaseEjectionTime > 0 {
+		outlier.BaseEjectionTime = protobuf.Duration(od.BaseEjectionTime)
+	}
+	if od.ConsecutiveGatewayErrors > 0 {
+		outlier.ConsecutiveGatewayFailure = protobuf.UInt32(od.ConsecutiveGatewayErrors)
+		outlier.EnforcingConsecutiveGatewayFailure = protobuf.UInt32(100)
+	}
+	return outlier
+}
+
 // Clustername returns the name of the CDS cluster for this service.
 func Clustername(cluster *dag.Cluster) string {
 	service := cluster.Upstream
-	buf := cluster.LoadBalancerPolicy
+	buf := cluster.LoadBalancerPolicy + cluster.LocalityPolicy
 	if hc := cluster.HealthCheckPolicy; hc != nil {
 		if hc.Timeout > 0 {
 			buf += hc.Timeout.String()
@@ -188,11 +284,38 @@ func Clustername(cluster *dag.Cluster) string {
 			buf += strconv.Itoa(int(hc.HealthyThreshold))
 		}
 		buf += hc.Path
+		if hc.Protocol != "" {
+			buf += fmt.Sprintf("hc%s/%q/%q/%q", hc.Protocol, hc.GRPCServiceName, hc.Send, hc.Receive)
+		}
 	}
 	if uv := cluster.UpstreamValidation; uv != nil {
 		buf += uv.CACertificate.Object.ObjectMeta.Name
 		buf += uv.SubjectName
 	}
+	if cb := cluster.CircuitBreakerPolicy; cb != nil {
+		for _, t := range []*dag.CircuitBreakerThresholds{cb.Default, cb.High} {
+			if t != nil {
+				buf += fmt.Sprintf("cb%d/%d/%d/%d", t.MaxConnections, t.MaxPendingRequests, t.MaxRequests, t.MaxRetries)
+			}
+			buf += ";"
+		}
+	}
+	if od := cluster.OutlierDetection; od != nil {
+		buf += fmt.Sprintf("outlier%d/%d/%s/%d", od.Consecutive5xxErrors, od.ConsecutiveGatewayErrors, od.BaseEjectionTime, od.MaxEjectionPercent)
+	}
+	if tp := cluster.ConnectionTimeoutPolicy; tp != nil {
+		buf += fmt.Sprintf("timeout%s/%s/%s", tp.ConnectTimeout, tp.IdleConnectionTimeout, tp.MaxConnectionDuration)
+	}
+	if cluster.Protocol != "" {
+		buf += "protocol" + cluster.Protocol
+	}
+	if len(service.StaticEndpoints) > 0 {
+		// distinguish an Upstream from a Service of the same name.
+		buf += "upstream"
+	}
+	if h2 := cluster.HTTP2Settings; h2 != nil {
+		buf += fmt.Sprintf("http2%d/%d/%d", h2.MaxConcurrentStreams, h2.InitialStreamWindowSize, h2.InitialConnectionWindowSize)
+	}
 
 	hash := sha1.Sum([]byte(buf))
 	ns := service.Namespace
@@ -287,6 +410,23 @@ func ClusterCommonLBConfig() *v2.Cluster_CommonLbConfig {
 	}
 }
 
+// commonLbConfig returns the ClusterCommonLBConfig with the
+// locality aware load balancing of policy, if any.
+func commonLbConfig(policy string) *v2.Cluster_CommonLbConfig {
+	config := ClusterCommonLBConfig()
+	switch policy {
+	case "ZoneAware":
+		config.LocalityConfigSpecifier = &v2.Cluster_CommonLbConfig_ZoneAwareLbConfig_{
+			ZoneAwareLbConfig: new(v2.Cluster_CommonLbConfig_ZoneAwareLbConfig),
+		}
+	case "LocalityWeighted":
+		config.LocalityConfigSpecifier = &v2.Cluster_CommonLbConfig_LocalityWeightedLbConfig_{
+			LocalityWeightedLbConfig: new(v2.Cluster_CommonLbConfig_LocalityWeightedLbConfig),
+		}
+	}
+	return config
+}
+
 // ConfigSource returns a *envoy_api_v2_core.ConfigSource for cluster.
 func ConfigSource(cluster string) *envoy_api_v2_core.ConfigSource {
 	return &envoy_api_v2_core.ConfigSource{
@@ -18,6 +18,7 @@ import (
 	"time"
 
 	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	envoy_api_v2_auth "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
 	envoy_cluster "github.com/envoyproxy/go-control-plane/envoy/api/v2/cluster"
 	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
 	envoy_type "github.com/envoyproxy/go-control-plane/envoy/type"
@@ -114,6 +115,44 @@ func TestCluster(t *testing.T) {
 				Http2ProtocolOptions: &envoy_api_v2_core.Http2ProtocolOptions{},
 			},
 		},
+		"h2c protocol with http2 settings": {
+			cluster: &dag.Cluster{
+				Upstream: service(s1),
+				Protocol: "h2c",
+				HTTP2Settings: &dag.HTTP2Settings{
+					MaxConcurrentStreams:    100,
+					InitialStreamWindowSize: 65535,
+				},
+			},
+			want: &v2.Cluster{
+				Name:                 "default/kuard/443/70b6a276bf",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(v2.Cluster_EDS),
+				EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				Http2ProtocolOptions: &envoy_api_v2_core.Http2ProtocolOptions{
+					MaxConcurrentStreams:    protobuf.UInt32(100),
+					InitialStreamWindowSize: protobuf.UInt32(65535),
+				},
+			},
+		},
+		"h1 protocol overrides h2 upstream": {
+			cluster: &dag.Cluster{
+				Upstream: service(s1, "h2"),
+				Protocol: "h1",
+			},
+			want: &v2.Cluster{
+				Name:                 "default/kuard/443/5207d18d8e",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(v2.Cluster_EDS),
+				EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+			},
+		},
 		"externalName service": {
 			cluster: &dag.Cluster{
 				Upstream: service(s2),
@@ -125,6 +164,54 @@ func TestCluster(t *testing.T) {
 				LoadAssignment:       StaticClusterLoadAssignment(service(s2)),
 			},
 		},
+		"upstream with static ip endpoints": {
+			cluster: &dag.Cluster{
+				Upstream: &dag.Service{
+					Name: "legacy", Namespace: "default",
+					ServicePort: &v1.ServicePort{Protocol: "TCP", Port: 8080},
+					StaticEndpoints: []dag.StaticEndpoint{
+						{Address: "10.1.0.1", Port: 8080},
+						{Address: "10.1.0.2", Port: 9090},
+					},
+				},
+			},
+			want: &v2.Cluster{
+				Name:                 "default/legacy/8080/fd54d5aa01",
+				AltStatName:          "default_legacy_8080",
+				ClusterDiscoveryType: ClusterDiscoveryType(v2.Cluster_STATIC),
+				LoadAssignment: ClusterLoadAssignment("default/legacy/",
+					SocketAddress("10.1.0.1", 8080),
+					SocketAddress("10.1.0.2", 9090),
+				),
+			},
+		},
+		"upstream with dns endpoints and tls": {
+			cluster: &dag.Cluster{
+				Upstream: &dag.Service{
+					Name: "legacy", Namespace: "default",
+					ServicePort: &v1.ServicePort{Protocol: "TCP", Port: 443},
+					Protocol:    "tls",
+					SNI:         "legacy.example.com",
+					StaticEndpoints: []dag.StaticEndpoint{
+						{Address: "legacy.example.com", Port: 443},
+					},
+				},
+			},
+			want: &v2.Cluster{
+				Name:                 "default/legacy/443/fd54d5aa01",
+				AltStatName:          "default_legacy_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(v2.Cluster_STRICT_DNS),
+				LoadAssignment: ClusterLoadAssignment("default/legacy/",
+					SocketAddress("legacy.example.com", 443),
+				),
+				TransportSocket: UpstreamTLSTransportSocket(
+					&envoy_api_v2_auth.UpstreamTlsContext{
+						CommonTlsContext: &envoy_api_v2_auth.CommonTlsContext{},
+						Sni:              "legacy.example.com",
+					},
+				),
+			},
+		},
 		"tls upstream": {
 			cluster: &dag.Cluster{
 				Upstream: service(s1, "tls"),
@@ -173,6 +260,39 @@ func TestCluster(t *testing.T) {
 				),
 			},
 		},
+		"verify h2 upstream with san": {
+			cluster: &dag.Cluster{
+				Upstream: service(s1),
+				Protocol: "h2",
+				UpstreamValidation: &dag.UpstreamValidation{
+					CACertificate: &dag.Secret{
+						Object: &v1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      "secret",
+								Namespace: "default",
+							},
+							Data: map[string][]byte{
+								"ca.crt": []byte("cacert"),
+							},
+						},
+					},
+					SubjectName: "foo.bar.io",
+				},
+			},
+			want: &v2.Cluster{
+				Name:                 "default/kuard/443/dbd50b933d",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(v2.Cluster_EDS),
+				EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				TransportSocket: UpstreamTLSTransportSocket(
+					UpstreamTLSContext([]byte("cacert"), "foo.bar.io", "h2"),
+				),
+				Http2ProtocolOptions: &envoy_api_v2_core.Http2ProtocolOptions{},
+			},
+		},
 		"projectcontour.io/max-connections": {
 			cluster: &dag.Cluster{
 				Upstream: &dag.Service{
@@ -196,6 +316,63 @@ func TestCluster(t *testing.T) {
 				},
 			},
 		},
+		"circuit breaker policy replaces annotations": {
+			cluster: &dag.Cluster{
+				Upstream: &dag.Service{
+					Name: s1.Name, Namespace: s1.Namespace,
+					ServicePort:    &s1.Spec.Ports[0],
+					MaxConnections: 9000,
+					MaxRetries:     5,
+				},
+				CircuitBreakerPolicy: &dag.CircuitBreakerPolicy{
+					Default: &dag.CircuitBreakerThresholds{
+						MaxConnections: 100,
+						MaxRequests:    200,
+					},
+					High: &dag.CircuitBreakerThresholds{
+						MaxRetries: 10,
+					},
+				},
+			},
+			want: &v2.Cluster{
+				Name:                 "default/kuard/443/dfdddfe318",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(v2.Cluster_EDS),
+				EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				CircuitBreakers: &envoy_cluster.CircuitBreakers{
+					Thresholds: []*envoy_cluster.CircuitBreakers_Thresholds{{
+						Priority:       envoy_api_v2_core.RoutingPriority_DEFAULT,
+						MaxConnections: protobuf.UInt32(100),
+						MaxRequests:    protobuf.UInt32(200),
+					}, {
+						Priority:   envoy_api_v2_core.RoutingPriority_HIGH,
+						MaxRetries: protobuf.UInt32(10),
+					}},
+				},
+			},
+		},
+		"empty circuit breaker policy": {
+			cluster: &dag.Cluster{
+				Upstream: &dag.Service{
+					Name: s1.Name, Namespace: s1.Namespace,
+					ServicePort:    &s1.Spec.Ports[0],
+					MaxConnections: 9000,
+				},
+				CircuitBreakerPolicy: &dag.CircuitBreakerPolicy{},
+			},
+			want: &v2.Cluster{
+				Name:                 "default/kuard/443/17ada2900d",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(v2.Cluster_EDS),
+				EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+			},
+		},
 		"projectcontour.io/max-pending-requests": {
 			cluster: &dag.Cluster{
 				Upstream: &dag.Service{
@@ -297,6 +474,90 @@ func TestCluster(t *testing.T) {
 				LbPolicy: v2.Cluster_RING_HASH,
 			},
 		},
+		"cluster with zone aware locality policy": {
+			cluster: &dag.Cluster{
+				Upstream:       service(s1),
+				LocalityPolicy: "ZoneAware",
+			},
+			want: &v2.Cluster{
+				Name:                 "default/kuard/443/f1a79cadea",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(v2.Cluster_EDS),
+				EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				CommonLbConfig: &v2.Cluster_CommonLbConfig{
+					LocalityConfigSpecifier: &v2.Cluster_CommonLbConfig_ZoneAwareLbConfig_{
+						ZoneAwareLbConfig: new(v2.Cluster_CommonLbConfig_ZoneAwareLbConfig),
+					},
+				},
+			},
+		},
+		"cluster with locality weighted locality policy": {
+			cluster: &dag.Cluster{
+				Upstream:       service(s1),
+				LocalityPolicy: "LocalityWeighted",
+			},
+			want: &v2.Cluster{
+				Name:                 "default/kuard/443/531ef9517c",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(v2.Cluster_EDS),
+				EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				CommonLbConfig: &v2.Cluster_CommonLbConfig{
+					LocalityConfigSpecifier: &v2.Cluster_CommonLbConfig_LocalityWeightedLbConfig_{
+						LocalityWeightedLbConfig: new(v2.Cluster_CommonLbConfig_LocalityWeightedLbConfig),
+					},
+				},
+			},
+		},
+		"cluster with connection timeout policy": {
+			cluster: &dag.Cluster{
+				Upstream: service(s1),
+				ConnectionTimeoutPolicy: &dag.ConnectionTimeoutPolicy{
+					ConnectTimeout:        1500 * time.Millisecond,
+					IdleConnectionTimeout: time.Minute,
+					MaxConnectionDuration: time.Hour,
+				},
+			},
+			want: &v2.Cluster{
+				Name:                 "default/kuard/443/6a8b5fa9e7",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(v2.Cluster_EDS),
+				EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				ConnectTimeout: protobuf.Duration(1500 * time.Millisecond),
+				CommonHttpProtocolOptions: &envoy_api_v2_core.HttpProtocolOptions{
+					IdleTimeout:           protobuf.Duration(time.Minute),
+					MaxConnectionDuration: protobuf.Duration(time.Hour),
+				},
+			},
+		},
+		"cluster with idle connection timeout only": {
+			cluster: &dag.Cluster{
+				Upstream: service(s1),
+				ConnectionTimeoutPolicy: &dag.ConnectionTimeoutPolicy{
+					IdleConnectionTimeout: time.Minute,
+				},
+			},
+			want: &v2.Cluster{
+				Name:                 "default/kuard/443/27dc9c1da5",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(v2.Cluster_EDS),
+				EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				CommonHttpProtocolOptions: &envoy_api_v2_core.HttpProtocolOptions{
+					IdleTimeout: protobuf.Duration(time.Minute),
+				},
+			},
+		},
 
 		"tcp service": {
 			cluster: &dag.Cluster{
@@ -342,6 +603,49 @@ func TestCluster(t *testing.T) {
 				}},
 			},
 		},
+		"service with outlier detection": {
+			cluster: &dag.Cluster{
+				Upstream: service(s1),
+				OutlierDetection: &dag.OutlierDetection{
+					Consecutive5xxErrors:     3,
+					ConsecutiveGatewayErrors: 2,
+					BaseEjectionTime:         10 * time.Second,
+					MaxEjectionPercent:       50,
+				},
+			},
+			want: &v2.Cluster{
+				Name:                 "default/kuard/443/fb1c346e8d",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(v2.Cluster_EDS),
+				EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				OutlierDetection: &envoy_cluster.OutlierDetection{
+					Consecutive_5Xx:                    protobuf.UInt32(3),
+					ConsecutiveGatewayFailure:          protobuf.UInt32(2),
+					EnforcingConsecutiveGatewayFailure: protobuf.UInt32(100),
+					BaseEjectionTime:                   protobuf.Duration(10 * time.Second),
+					MaxEjectionPercent:                 protobuf.UInt32(50),
+				},
+			},
+		},
+		"service with default outlier detection": {
+			cluster: &dag.Cluster{
+				Upstream:         service(s1),
+				OutlierDetection: &dag.OutlierDetection{},
+			},
+			want: &v2.Cluster{
+				Name:                 "default/kuard/443/0e823b159b",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(v2.Cluster_EDS),
+				EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				OutlierDetection: &envoy_cluster.OutlierDetection{},
+			},
+		},
 	}
 
 	for name, tc := range tests {
@@ -414,6 +718,65 @@ func TestClustername(t *testing.T) {
 			},
 			want: "default/backend/80/5c26077e1d",
 		},
+		"grpc healthcheck": {
+			cluster: &dag.Cluster{
+				Upstream: &dag.Service{
+					Name:      "backend",
+					Namespace: "default",
+					ServicePort: &v1.ServicePort{
+						Name:       "http",
+						Protocol:   "TCP",
+						Port:       80,
+						TargetPort: intstr.FromInt(6502),
+					},
+				},
+				HealthCheckPolicy: &dag.HealthCheckPolicy{
+					Protocol:        "grpc",
+					GRPCServiceName: "helloworld.Greeter",
+				},
+			},
+			want: "default/backend/80/f151d8463f",
+		},
+		"tcp healthcheck": {
+			cluster: &dag.Cluster{
+				Upstream: &dag.Service{
+					Name:      "backend",
+					Namespace: "default",
+					ServicePort: &v1.ServicePort{
+						Name:       "http",
+						Protocol:   "TCP",
+						Port:       80,
+						TargetPort: intstr.FromInt(6502),
+					},
+				},
+				HealthCheckPolicy: &dag.HealthCheckPolicy{
+					Protocol: "tcp",
+					Send:     []byte("PING"),
+					Receive:  []byte("PONG"),
+				},
+			},
+			want: "default/backend/80/f539085156",
+		},
+		"tcp healthcheck payloads split differently": {
+			cluster: &dag.Cluster{
+				Upstream: &dag.Service{
+					Name:      "backend",
+					Namespace: "default",
+					ServicePort: &v1.ServicePort{
+						Name:       "http",
+						Protocol:   "TCP",
+						Port:       80,
+						TargetPort: intstr.FromInt(6502),
+					},
+				},
+				HealthCheckPolicy: &dag.HealthCheckPolicy{
+					Protocol: "tcp",
+					Send:     []byte("PINGP"),
+					Receive:  []byte("ONG"),
+				},
+			},
+			want: "default/backend/80/2d3aa27097",
+		},
 		"upstream tls validation with subject alt name": {
 			cluster: &dag.Cluster{
 				Upstream: &dag.Service{
@@ -444,6 +807,24 @@ func TestClustername(t *testing.T) {
 			},
 			want: "default/backend/80/6bf46b7b3a",
 		},
+		"outlier detection": {
+			cluster: &dag.Cluster{
+				Upstream: &dag.Service{
+					Name:      "backend",
+					Namespace: "default",
+					ServicePort: &v1.ServicePort{
+						Name:       "http",
+						Protocol:   "TCP",
+						Port:       80,
+						TargetPort: intstr.FromInt(6502),
+					},
+				},
+				OutlierDetection: &dag.OutlierDetection{
+					Consecutive5xxErrors: 3,
+				},
+			},
+			want: "default/backend/80/f506720945",
+		},
 	}
 
 	for name, tc := range tests {
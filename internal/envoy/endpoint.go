@@ -17,6 +17,7 @@ import (
 	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
 	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
 	envoy_api_v2_endpoint "github.com/envoyproxy/go-control-plane/envoy/api/v2/endpoint"
+	"github.com/projectcontour/contour/internal/protobuf"
 )
 
 // LBEndpoint creates a new LbEndpoint.
@@ -50,13 +51,17 @@ func Endpoints(addrs ...*envoy_api_v2_core.Address) []*envoy_api_v2_endpoint.Loc
 }
 
 // ClusterLoadAssignment returns a *v2.ClusterLoadAssignment with a single
-// LocalityLbEndpoints of the supplied addresses.
+// LocalityLbEndpoints of the supplied addresses. The LocalityLbEndpoints
+// is weighted by the number of addresses, as Envoy sends no traffic to an
+// unweighted locality when a cluster uses the LocalityWeighted policy.
 func ClusterLoadAssignment(name string, addrs ...*envoy_api_v2_core.Address) *v2.ClusterLoadAssignment {
 	if len(addrs) == 0 {
 		return &v2.ClusterLoadAssignment{ClusterName: name}
 	}
+	endpoints := Endpoints(addrs...)
+	endpoints[0].LoadBalancingWeight = protobuf.UInt32(uint32(len(addrs)))
 	return &v2.ClusterLoadAssignment{
 		ClusterName: name,
-		Endpoints:   Endpoints(addrs...),
+		Endpoints:   endpoints,
 	}
 }
@@ -19,6 +19,7 @@ import (
 	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
 	envoy_api_v2_endpoint "github.com/envoyproxy/go-control-plane/envoy/api/v2/endpoint"
 	"github.com/google/go-cmp/cmp"
+	"github.com/projectcontour/contour/internal/protobuf"
 )
 
 func TestLBEndpoint(t *testing.T) {
@@ -73,7 +74,12 @@ func TestClusterLoadAssignment(t *testing.T) {
 	got = ClusterLoadAssignment("one addr", SocketAddress("microsoft.com", 81))
 	want = &v2.ClusterLoadAssignment{
 		ClusterName: "one addr",
-		Endpoints:   Endpoints(SocketAddress("microsoft.com", 81)),
+		Endpoints: []*envoy_api_v2_endpoint.LocalityLbEndpoints{{
+			LbEndpoints: []*envoy_api_v2_endpoint.LbEndpoint{
+				LBEndpoint(SocketAddress("microsoft.com", 81)),
+			},
+			LoadBalancingWeight: protobuf.UInt32(1),
+		}},
 	}
 
 	if diff := cmp.Diff(want, got); diff != "" {
@@ -86,10 +92,13 @@ func TestClusterLoadAssignment(t *testing.T) {
 	)
 	want = &v2.ClusterLoadAssignment{
 		ClusterName: "two addrs",
-		Endpoints: Endpoints(
-			SocketAddress("microsoft.com", 81),
-			SocketAddress("github.com", 443),
-		),
+		Endpoints: []*envoy_api_v2_endpoint.LocalityLbEndpoints{{
+			LbEndpoints: []*envoy_api_v2_endpoint.LbEndpoint{
+				LBEndpoint(SocketAddress("microsoft.com", 81)),
+				LBEndpoint(SocketAddress("github.com", 443)),
+			},
+			LoadBalancingWeight: protobuf.UInt32(2),
+		}},
 	}
 
 	if diff := cmp.Diff(want, got); diff != "" {
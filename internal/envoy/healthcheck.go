@@ -35,23 +35,54 @@ const (
 // healthCheck returns a *envoy_api_v2_core.HealthCheck value.
 func healthCheck(cluster *dag.Cluster) *envoy_api_v2_core.HealthCheck {
 	hc := cluster.HealthCheckPolicy
-	host := hcHost
-	if hc.Host != "" {
-		host = hc.Host
-	}
 
 	// TODO(dfc) why do we need to specify our own default, what is the default
 	// that envoy applies if these fields are left nil?
-	return &envoy_api_v2_core.HealthCheck{
+	check := &envoy_api_v2_core.HealthCheck{
 		Timeout:            durationOrDefault(hc.Timeout, hcTimeout),
 		Interval:           durationOrDefault(hc.Interval, hcInterval),
 		UnhealthyThreshold: countOrDefault(hc.UnhealthyThreshold, hcUnhealthyThreshold),
 		HealthyThreshold:   countOrDefault(hc.HealthyThreshold, hcHealthyThreshold),
-		HealthChecker: &envoy_api_v2_core.HealthCheck_HttpHealthCheck_{
+	}
+
+	switch hc.Protocol {
+	case "tcp":
+		tcp := &envoy_api_v2_core.HealthCheck_TcpHealthCheck{}
+		if len(hc.Send) > 0 {
+			tcp.Send = payload(hc.Send)
+		}
+		if len(hc.Receive) > 0 {
+			tcp.Receive = []*envoy_api_v2_core.HealthCheck_Payload{payload(hc.Receive)}
+		}
+		check.HealthChecker = &envoy_api_v2_core.HealthCheck_TcpHealthCheck_{
+			TcpHealthCheck: tcp,
+		}
+	case "grpc":
+		check.HealthChecker = &envoy_api_v2_core.HealthCheck_GrpcHealthCheck_{
+			GrpcHealthCheck: &envoy_api_v2_core.HealthCheck_GrpcHealthCheck{
+				ServiceName: hc.GRPCServiceName,
+				Authority:   hc.Host,
+			},
+		}
+	default:
+		host := hcHost
+		if hc.Host != "" {
+			host = hc.Host
+		}
+		check.HealthChecker = &envoy_api_v2_core.HealthCheck_HttpHealthCheck_{
 			HttpHealthCheck: &envoy_api_v2_core.HealthCheck_HttpHealthCheck{
 				Path: hc.Path,
 				Host: host,
 			},
+		}
+	}
+	return check
+}
+
+func payload(data []byte) *envoy_api_v2_core.HealthCheck_Payload {
+	return &envoy_api_v2_core.HealthCheck_Payload{
+		Payload: &envoy_api_v2_core.HealthCheck_Payload_Binary{
+			Binary: data,
 		},
 	}
 }
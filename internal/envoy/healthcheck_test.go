@@ -90,6 +90,72 @@ func TestHealthCheck(t *testing.T) {
 				},
 			},
 		},
+		"tcp connect healthcheck": {
+			cluster: &dag.Cluster{
+				HealthCheckPolicy: &dag.HealthCheckPolicy{
+					Protocol: "tcp",
+				},
+			},
+			want: &envoy_api_v2_core.HealthCheck{
+				Timeout:            protobuf.Duration(hcTimeout),
+				Interval:           protobuf.Duration(hcInterval),
+				UnhealthyThreshold: protobuf.UInt32(3),
+				HealthyThreshold:   protobuf.UInt32(2),
+				HealthChecker: &envoy_api_v2_core.HealthCheck_TcpHealthCheck_{
+					TcpHealthCheck: &envoy_api_v2_core.HealthCheck_TcpHealthCheck{},
+				},
+			},
+		},
+		"tcp send and receive healthcheck": {
+			cluster: &dag.Cluster{
+				HealthCheckPolicy: &dag.HealthCheckPolicy{
+					Protocol: "tcp",
+					Send:     []byte("PING\r\n"),
+					Receive:  []byte("PONG"),
+				},
+			},
+			want: &envoy_api_v2_core.HealthCheck{
+				Timeout:            protobuf.Duration(hcTimeout),
+				Interval:           protobuf.Duration(hcInterval),
+				UnhealthyThreshold: protobuf.UInt32(3),
+				HealthyThreshold:   protobuf.UInt32(2),
+				HealthChecker: &envoy_api_v2_core.HealthCheck_TcpHealthCheck_{
+					TcpHealthCheck: &envoy_api_v2_core.HealthCheck_TcpHealthCheck{
+						Send: &envoy_api_v2_core.HealthCheck_Payload{
+							Payload: &envoy_api_v2_core.HealthCheck_Payload_Binary{
+								Binary: []byte("PING\r\n"),
+							},
+						},
+						Receive: []*envoy_api_v2_core.HealthCheck_Payload{{
+							Payload: &envoy_api_v2_core.HealthCheck_Payload_Binary{
+								Binary: []byte("PONG"),
+							},
+						}},
+					},
+				},
+			},
+		},
+		"grpc healthcheck": {
+			cluster: &dag.Cluster{
+				HealthCheckPolicy: &dag.HealthCheckPolicy{
+					Protocol:        "grpc",
+					Host:            "grpc.example.com",
+					GRPCServiceName: "helloworld.Greeter",
+				},
+			},
+			want: &envoy_api_v2_core.HealthCheck{
+				Timeout:            protobuf.Duration(hcTimeout),
+				Interval:           protobuf.Duration(hcInterval),
+				UnhealthyThreshold: protobuf.UInt32(3),
+				HealthyThreshold:   protobuf.UInt32(2),
+				HealthChecker: &envoy_api_v2_core.HealthCheck_GrpcHealthCheck_{
+					GrpcHealthCheck: &envoy_api_v2_core.HealthCheck_GrpcHealthCheck{
+						ServiceName: "helloworld.Greeter",
+						Authority:   "grpc.example.com",
+					},
+				},
+			},
+		},
 	}
 
 	for name, tc := range tests {
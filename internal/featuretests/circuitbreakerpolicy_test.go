@@ -0,0 +1,104 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package featuretests
+
+import (
+	"testing"
+
+	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	envoy_cluster "github.com/envoyproxy/go-control-plane/envoy/api/v2/cluster"
+	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/protobuf"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestCircuitBreakerPolicy(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	s1 := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "default",
+			Annotations: map[string]string{
+				"projectcontour.io/max-connections": "9000",
+			},
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Protocol:   "TCP",
+				Port:       80,
+				TargetPort: intstr.FromInt(8080),
+			}},
+		},
+	}
+	rh.OnAdd(s1)
+
+	// the same service has different limits on each route.
+	proxy1 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "simple",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{Fqdn: "www.example.com"},
+			Routes: []projcontour.Route{{
+				Conditions: conditions(prefixCondition("/")),
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 80,
+				}},
+			}, {
+				Conditions: conditions(prefixCondition("/batch")),
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 80,
+					CircuitBreakerPolicy: &projcontour.CircuitBreakerPolicy{
+						Default: &projcontour.CircuitBreakerThresholds{
+							MaxRequests: 50,
+						},
+						High: &projcontour.CircuitBreakerThresholds{
+							MaxRequests: 100,
+						},
+					},
+				}},
+			}},
+		},
+	}
+	rh.OnAdd(proxy1)
+
+	c1 := cluster("default/app/80/da39a3ee5e", "default/app", "default_app_80")
+	c1.CircuitBreakers = &envoy_cluster.CircuitBreakers{
+		Thresholds: []*envoy_cluster.CircuitBreakers_Thresholds{{
+			MaxConnections: protobuf.UInt32(9000),
+		}},
+	}
+	c2 := cluster("default/app/80/39b95c5a46", "default/app", "default_app_80")
+	c2.CircuitBreakers = &envoy_cluster.CircuitBreakers{
+		Thresholds: []*envoy_cluster.CircuitBreakers_Thresholds{{
+			MaxRequests: protobuf.UInt32(50),
+		}, {
+			Priority:    envoy_api_v2_core.RoutingPriority_HIGH,
+			MaxRequests: protobuf.UInt32(100),
+		}},
+	}
+
+	c.Request(clusterType).Equals(&v2.DiscoveryResponse{
+		Resources: resources(t, c2, c1),
+		TypeUrl:   clusterType,
+	})
+}
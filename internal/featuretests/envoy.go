@@ -51,12 +51,9 @@ func externalNameCluster(name, servicename, statName, externalName string, port
 		Name:                 name,
 		ClusterDiscoveryType: envoy.ClusterDiscoveryType(v2.Cluster_STRICT_DNS),
 		AltStatName:          statName,
-		LoadAssignment: &v2.ClusterLoadAssignment{
-			ClusterName: servicename,
-			Endpoints: envoy.Endpoints(
-				envoy.SocketAddress(externalName, port),
-			),
-		},
+		LoadAssignment: envoy.ClusterLoadAssignment(servicename,
+			envoy.SocketAddress(externalName, port),
+		),
 	})
 }
 
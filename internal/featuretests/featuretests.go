@@ -65,14 +65,17 @@ func setup(t *testing.T, opts ...func(*contour.EventHandler)) (cache.ResourceEve
 	log := logrus.New()
 	log.Out = new(discardWriter)
 
+	versions := new(contour.Versions)
 	et := &contour.EndpointsTranslator{
 		FieldLogger: log,
+		Versions:    versions,
 	}
 
 	r := prometheus.NewRegistry()
 	ch := &contour.CacheHandler{
 		Metrics:       metrics.NewMetrics(r),
 		ListenerCache: contour.NewListenerCache(statsAddress, statsPort),
+		Versions:      versions,
 		FieldLogger:   log,
 	}
 
@@ -114,7 +117,7 @@ func setup(t *testing.T, opts ...func(*contour.EventHandler)) (cache.ResourceEve
 		ch.ListenerCache.TypeURL(): &ch.ListenerCache,
 		ch.SecretCache.TypeURL():   &ch.SecretCache,
 		et.TypeURL():               et,
-	}, r)
+	}, r, ch.Metrics, nil)
 
 	var g workgroup.Group
 
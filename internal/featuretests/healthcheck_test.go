@@ -0,0 +1,183 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package featuretests
+
+import (
+	"testing"
+	"time"
+
+	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/protobuf"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestTCPProxyHealthCheck(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "redis",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Protocol:   "TCP",
+				Port:       6379,
+				TargetPort: intstr.FromInt(6379),
+			}},
+		},
+	}
+	rh.OnAdd(svc)
+
+	hp1 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "redis",
+			Namespace: svc.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "redis.example.com",
+				TLS: &projcontour.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &projcontour.TCPProxy{
+				HealthCheckPolicy: &projcontour.TCPHealthCheckPolicy{
+					IntervalSeconds: 5,
+					Send:            "PING\r\n",
+					Receive:         "+PONG",
+				},
+				Services: []projcontour.Service{{
+					Name: svc.Name,
+					Port: 6379,
+				}},
+			},
+		},
+	}
+	rh.OnAdd(hp1)
+
+	c1 := cluster("default/redis/6379/00777e2eb0", "default/redis", "default_redis_6379")
+	c1.DrainConnectionsOnHostRemoval = true
+	c1.HealthChecks = []*envoy_api_v2_core.HealthCheck{{
+		Timeout:            protobuf.Duration(2 * time.Second),
+		Interval:           protobuf.Duration(5 * time.Second),
+		UnhealthyThreshold: protobuf.UInt32(3),
+		HealthyThreshold:   protobuf.UInt32(2),
+		HealthChecker: &envoy_api_v2_core.HealthCheck_TcpHealthCheck_{
+			TcpHealthCheck: &envoy_api_v2_core.HealthCheck_TcpHealthCheck{
+				Send: &envoy_api_v2_core.HealthCheck_Payload{
+					Payload: &envoy_api_v2_core.HealthCheck_Payload_Binary{
+						Binary: []byte("PING\r\n"),
+					},
+				},
+				Receive: []*envoy_api_v2_core.HealthCheck_Payload{{
+					Payload: &envoy_api_v2_core.HealthCheck_Payload_Binary{
+						Binary: []byte("+PONG"),
+					},
+				}},
+			},
+		},
+	}}
+
+	c.Request(clusterType).Equals(&v2.DiscoveryResponse{
+		Resources: resources(t, c1),
+		TypeUrl:   clusterType,
+	})
+}
+
+func TestGRPCHealthCheck(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "greeter",
+			Namespace: "default",
+			Annotations: map[string]string{
+				"projectcontour.io/upstream-protocol.h2c": "grpc",
+			},
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Name:       "grpc",
+				Protocol:   "TCP",
+				Port:       50051,
+				TargetPort: intstr.FromInt(50051),
+			}},
+		},
+	}
+	rh.OnAdd(svc)
+
+	hp1 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "greeter",
+			Namespace: svc.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "greeter.example.com",
+			},
+			Routes: []projcontour.Route{{
+				HealthCheckPolicy: &projcontour.HTTPHealthCheckPolicy{
+					GRPC: &projcontour.GRPCHealthCheck{
+						ServiceName: "helloworld.Greeter",
+					},
+				},
+				Services: []projcontour.Service{{
+					Name: svc.Name,
+					Port: 50051,
+				}},
+			}},
+		},
+	}
+	rh.OnAdd(hp1)
+
+	c1 := h2cCluster(cluster("default/greeter/50051/f151d8463f", "default/greeter/grpc", "default_greeter_50051"))
+	c1.DrainConnectionsOnHostRemoval = true
+	c1.HealthChecks = []*envoy_api_v2_core.HealthCheck{{
+		Timeout:            protobuf.Duration(2 * time.Second),
+		Interval:           protobuf.Duration(10 * time.Second),
+		UnhealthyThreshold: protobuf.UInt32(3),
+		HealthyThreshold:   protobuf.UInt32(2),
+		HealthChecker: &envoy_api_v2_core.HealthCheck_GrpcHealthCheck_{
+			GrpcHealthCheck: &envoy_api_v2_core.HealthCheck_GrpcHealthCheck{
+				ServiceName: "helloworld.Greeter",
+			},
+		},
+	}}
+
+	c.Request(clusterType).Equals(&v2.DiscoveryResponse{
+		Resources: resources(t, c1),
+		TypeUrl:   clusterType,
+	})
+
+	// grpc health checks are only valid for h2 and h2c services
+	svc2 := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      svc.Name,
+			Namespace: svc.Namespace,
+		},
+		Spec: svc.Spec,
+	}
+	rh.OnUpdate(svc, svc2)
+
+	c.Request(clusterType).Equals(&v2.DiscoveryResponse{
+		TypeUrl: clusterType,
+	})
+}
@@ -29,7 +29,7 @@ import (
 
 func TestIngressClassAnnotation(t *testing.T) {
 	rh, c, done := setup(t, func(reh *contour.EventHandler) {
-		reh.Builder.Source.IngressClass = "linkerd"
+		reh.Builder.Source.IngressClasses = []string{"linkerd"}
 	})
 	defer done()
 
@@ -27,7 +27,7 @@ import (
 
 func TestMirrorPolicy(t *testing.T) {
 	rh, c, done := setup(t, func(reh *contour.EventHandler) {
-		reh.Builder.Source.IngressClass = "linkerd"
+		reh.Builder.Source.IngressClasses = []string{"linkerd"}
 	})
 	defer done()
 
@@ -0,0 +1,122 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package featuretests
+
+import (
+	"testing"
+	"time"
+
+	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	envoy_cluster "github.com/envoyproxy/go-control-plane/envoy/api/v2/cluster"
+	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/protobuf"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestOutlierDetection(t *testing.T) {
+	rh, c, done := setup(t)
+	defer done()
+
+	s1 := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Protocol:   "TCP",
+				Port:       80,
+				TargetPort: intstr.FromInt(8080),
+			}, {
+				Protocol:   "TCP",
+				Port:       8080,
+				TargetPort: intstr.FromInt(8080),
+			}},
+		},
+	}
+	rh.OnAdd(s1)
+
+	// the route's policy applies to services without their own
+	proxy1 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "simple",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{Fqdn: "www.example.com"},
+			Routes: []projcontour.Route{{
+				OutlierDetection: &projcontour.OutlierDetection{
+					Consecutive5xxErrors: 3,
+					BaseEjectionTime:     "10s",
+				},
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 80,
+				}, {
+					Name: s1.Name,
+					Port: 8080,
+					OutlierDetection: &projcontour.OutlierDetection{
+						ConsecutiveGatewayErrors: 2,
+						MaxEjectionPercent:       50,
+					},
+				}},
+			}},
+		},
+	}
+	rh.OnAdd(proxy1)
+
+	c1 := cluster("default/app/80/aba6f7cbde", "default/app", "default_app_80")
+	c1.OutlierDetection = &envoy_cluster.OutlierDetection{
+		Consecutive_5Xx:  protobuf.UInt32(3),
+		BaseEjectionTime: protobuf.Duration(10 * time.Second),
+	}
+	c2 := cluster("default/app/8080/fa51925c26", "default/app", "default_app_8080")
+	c2.OutlierDetection = &envoy_cluster.OutlierDetection{
+		ConsecutiveGatewayFailure:          protobuf.UInt32(2),
+		EnforcingConsecutiveGatewayFailure: protobuf.UInt32(100),
+		MaxEjectionPercent:                 protobuf.UInt32(50),
+	}
+
+	c.Request(clusterType).Equals(&v2.DiscoveryResponse{
+		Resources: resources(t, c1, c2),
+		TypeUrl:   clusterType,
+	})
+
+	// an invalid policy makes the proxy invalid
+	proxy2 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "simple",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{Fqdn: "www.example.com"},
+			Routes: []projcontour.Route{{
+				OutlierDetection: &projcontour.OutlierDetection{
+					MaxEjectionPercent: 101,
+				},
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 80,
+				}},
+			}},
+		},
+	}
+	rh.OnUpdate(proxy1, proxy2)
+
+	c.Request(clusterType).Equals(&v2.DiscoveryResponse{
+		TypeUrl: clusterType,
+	})
+}
@@ -30,7 +30,7 @@ import (
 
 func TestTimeoutPolicyRequestTimeout(t *testing.T) {
 	rh, c, done := setup(t, func(reh *contour.EventHandler) {
-		reh.Builder.Source.IngressClass = "linkerd"
+		reh.Builder.Source.IngressClasses = []string{"linkerd"}
 	})
 	defer done()
 
@@ -359,7 +359,7 @@ func TestTimeoutPolicyRequestTimeout(t *testing.T) {
 
 func TestTimeoutPolicyIdleTimeout(t *testing.T) {
 	rh, c, done := setup(t, func(reh *contour.EventHandler) {
-		reh.Builder.Source.IngressClass = "linkerd"
+		reh.Builder.Source.IngressClasses = []string{"linkerd"}
 	})
 	defer done()
 
@@ -0,0 +1,92 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Authorizer restricts the xDS API to clients whose certificate
+// carries an allowed identity.
+type Authorizer struct {
+	// Identities is the set of allowed client identities. An
+	// identity matches a URI, for example a SPIFFE ID, or DNS
+	// subject alternative name of the client's certificate.
+	Identities []string
+
+	// LogOnly, if true, logs unauthorized clients rather
+	// than rejecting them.
+	LogOnly bool
+}
+
+// permitted returns true if any of identities is allowed.
+func (a *Authorizer) permitted(identities []string) bool {
+	for _, id := range identities {
+		for _, allowed := range a.Identities {
+			if id == allowed {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// authorize returns an error if the client of ctx is not permitted
+// to use the xDS API. Unauthorized clients are recorded by identity.
+func (xh *xdsHandler) authorize(ctx context.Context) error {
+	if xh.auth == nil {
+		return nil
+	}
+	identities := peerIdentities(ctx)
+	if xh.auth.permitted(identities) {
+		return nil
+	}
+
+	identity := "unknown"
+	if len(identities) > 0 {
+		identity = identities[0]
+	}
+	if xh.Metrics != nil {
+		xh.IncXDSUnauthorizedStreams(identity)
+	}
+	if xh.auth.LogOnly {
+		xh.WithField("identities", identities).Warn("unauthorized xDS client")
+		return nil
+	}
+	return status.Errorf(codes.PermissionDenied, "client identity %q is not authorized", identity)
+}
+
+// peerIdentities returns the URI and DNS subject alternative names
+// of the certificate presented by the client of ctx.
+func peerIdentities(ctx context.Context) []string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.PeerCertificates) == 0 {
+		return nil
+	}
+	cert := info.State.PeerCertificates[0]
+	var identities []string
+	for _, u := range cert.URIs {
+		identities = append(identities, u.String())
+	}
+	return append(identities, cert.DNSNames...)
+}
@@ -0,0 +1,138 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/url"
+	"testing"
+
+	"github.com/projectcontour/contour/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestXDSHandlerAuthorize(t *testing.T) {
+	log := logrus.New()
+	log.SetOutput(ioutil.Discard)
+
+	spiffe, err := url.Parse("spiffe://cluster.local/ns/projectcontour/sa/envoy")
+	check(t, err)
+
+	envoy := peerContext(&x509.Certificate{
+		URIs: []*url.URL{spiffe},
+	})
+	other := peerContext(&x509.Certificate{
+		DNSNames: []string{"other"},
+	})
+
+	tests := map[string]struct {
+		auth     *Authorizer
+		ctx      context.Context
+		want     codes.Code
+		rejected string // identity recorded as unauthorized
+	}{
+		"no authorizer": {
+			auth: nil,
+			ctx:  other,
+			want: codes.OK,
+		},
+		"allowed spiffe id": {
+			auth: &Authorizer{Identities: []string{spiffe.String()}},
+			ctx:  envoy,
+			want: codes.OK,
+		},
+		"allowed dns name": {
+			auth: &Authorizer{Identities: []string{"other"}},
+			ctx:  other,
+			want: codes.OK,
+		},
+		"identity not allowed": {
+			auth:     &Authorizer{Identities: []string{spiffe.String()}},
+			ctx:      other,
+			want:     codes.PermissionDenied,
+			rejected: "other",
+		},
+		"no client certificate": {
+			auth:     &Authorizer{Identities: []string{spiffe.String()}},
+			ctx:      context.Background(),
+			want:     codes.PermissionDenied,
+			rejected: "unknown",
+		},
+		"log only": {
+			auth:     &Authorizer{Identities: []string{spiffe.String()}, LogOnly: true},
+			ctx:      other,
+			want:     codes.OK,
+			rejected: "other",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := prometheus.NewRegistry()
+			xh := xdsHandler{
+				FieldLogger: log,
+				auth:        tc.auth,
+				Metrics:     metrics.NewMetrics(r),
+			}
+			got := status.Code(xh.authorize(tc.ctx))
+			if got != tc.want {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+			if tc.rejected != "" {
+				if v := unauthorizedStreams(t, r, tc.rejected); v != 1 {
+					t.Fatalf("expected 1 unauthorized stream for %q, got %v", tc.rejected, v)
+				}
+			}
+		})
+	}
+}
+
+func peerContext(cert *x509.Certificate) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{
+			State: tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{cert},
+			},
+		},
+	})
+}
+
+// unauthorizedStreams returns the number of unauthorized streams
+// recorded for identity.
+func unauthorizedStreams(t *testing.T, r *prometheus.Registry, identity string) float64 {
+	t.Helper()
+	families, err := r.Gather()
+	check(t, err)
+	for _, mf := range families {
+		if mf.GetName() != metrics.XDSUnauthorizedStreamsCounter {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "identity" && l.GetValue() == identity {
+					return m.Counter.GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,85 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errDraining is returned from a stream terminated because the
+// server is shutting down.
+var errDraining = status.Error(codes.Unavailable, "server is shutting down")
+
+// drainer spreads the termination of open streams over a drain period
+// so that Envoys reconnect to other Contour replicas gradually, rather
+// than all at once.
+type drainer struct {
+	once   sync.Once
+	ch     chan struct{}
+	period time.Duration
+}
+
+func newDrainer() *drainer {
+	return &drainer{
+		ch: make(chan struct{}),
+	}
+}
+
+// start begins draining streams over period. Calls after the
+// first have no effect.
+func (d *drainer) start(period time.Duration) {
+	d.once.Do(func() {
+		d.period = period
+		close(d.ch)
+	})
+}
+
+// wait returns a channel which is closed at a random point in the drain
+// period once draining has started. The channel is never closed if ctx
+// is done first. A nil drainer never drains.
+func (d *drainer) wait(ctx context.Context) <-chan struct{} {
+	if d == nil {
+		return nil
+	}
+	ch := make(chan struct{})
+	go func() {
+		select {
+		case <-d.ch:
+		case <-ctx.Done():
+			return
+		}
+		t := time.NewTimer(jitter(d.period))
+		defer t.Stop()
+		select {
+		case <-t.C:
+			close(ch)
+		case <-ctx.Done():
+		}
+	}()
+	return ch
+}
+
+// jitter returns a random duration in the range [0, d).
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(d)))
+}
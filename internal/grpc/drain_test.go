@@ -0,0 +1,117 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+
+	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	"github.com/projectcontour/contour/internal/contour"
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDrainerWait(t *testing.T) {
+	var nilDrainer *drainer
+	if ch := nilDrainer.wait(context.Background()); ch != nil {
+		t.Fatal("nil drainer returned a non nil channel")
+	}
+
+	d := newDrainer()
+	ch := d.wait(context.Background())
+	select {
+	case <-ch:
+		t.Fatal("drained before start")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := d.wait(ctx)
+	cancel()
+
+	d.start(10 * time.Millisecond)
+	select {
+	case <-ch:
+	case <-time.After(time.Second):
+		t.Fatal("not drained after start")
+	}
+
+	select {
+	case <-canceled:
+		t.Fatal("drained after context was canceled")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestServerShutdownDrainsStreams(t *testing.T) {
+	log := logrus.New()
+	log.SetOutput(ioutil.Discard)
+
+	// publish an empty configuration so the stream is answered.
+	ch := contour.CacheHandler{
+		Metrics:     metrics.NewMetrics(prometheus.NewRegistry()),
+		FieldLogger: log,
+	}
+	ch.OnChange(new(dag.DAG))
+	cc := &ch.ClusterCache
+	srv := NewAPI(log, map[string]Resource{
+		cc.TypeURL(): cc,
+	}, prometheus.NewRegistry(), nil, nil)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	check(t, err)
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.Serve(l)
+	}()
+
+	conn, err := grpc.Dial(l.Addr().String(), grpc.WithInsecure())
+	check(t, err)
+	defer conn.Close()
+
+	cds := v2.NewClusterDiscoveryServiceClient(conn)
+	stream, err := cds.StreamClusters(context.Background())
+	check(t, err)
+	sendreq(t, stream, cc.TypeURL())
+	checkrecv(t, stream)
+
+	stopped := make(chan struct{})
+	go func() {
+		srv.Shutdown(50 * time.Millisecond)
+		close(stopped)
+	}()
+
+	// ACK the response, rather than a further response the
+	// stream should be terminated by the server.
+	sendreq(t, stream, cc.TypeURL())
+	_, err = stream.Recv()
+	if got := status.Code(err); got != codes.Unavailable {
+		t.Fatalf("expected %v, got %v", codes.Unavailable, err)
+	}
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Shutdown did not return")
+	}
+	check(t, <-done)
+}
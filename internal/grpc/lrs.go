@@ -0,0 +1,195 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	envoy_api_v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	loadstats "github.com/envoyproxy/go-control-plane/envoy/service/load_stats/v2"
+	"github.com/golang/protobuf/proto"
+	"github.com/projectcontour/contour/internal/metrics"
+	"github.com/projectcontour/contour/internal/protobuf"
+	"github.com/sirupsen/logrus"
+)
+
+// loadReportingInterval is the interval at which Envoy is asked
+// to send load reports.
+const loadReportingInterval = 10 * time.Second
+
+// loadReporter implements the Envoy Load Reporting Service.
+type loadReporter struct {
+	logrus.FieldLogger
+	*metrics.Metrics
+	connections counter
+
+	// clusters is the CDS resource. Envoy is asked to
+	// report load for each cluster it contains.
+	clusters Resource
+
+	drain *drainer
+}
+
+type loadStatsStream interface {
+	Context() context.Context
+	Send(*loadstats.LoadStatsResponse) error
+	Recv() (*loadstats.LoadStatsRequest, error)
+}
+
+// stream processes a stream of LoadStatsRequests.
+func (lr *loadReporter) stream(st loadStatsStream) (err error) {
+	log := lr.WithField("connection", lr.connections.next())
+
+	defer func() {
+		if err != nil {
+			log.WithError(err).Error("load reporting stream terminated")
+		} else {
+			log.Info("load reporting stream terminated")
+		}
+	}()
+
+	// the first request from Envoy identifies the node and
+	// carries no stats. Envoy waits for our response before
+	// reporting load.
+	req, err := st.Recv()
+	if err != nil {
+		return err
+	}
+	if req.Node != nil {
+		log = log.WithField("node_id", req.Node.Id)
+	}
+
+	ctx := st.Context()
+	drained := lr.drain.wait(ctx)
+
+	// reports are not read until Envoy has been sent the clusters
+	// to report on, so the namespace and service of each cluster
+	// reported are known. Until then reports is nil and blocks.
+	var reports chan *loadstats.LoadStatsRequest
+	errs := make(chan error, 1)
+	recv := func() {
+		for {
+			req, err := st.Recv()
+			if err != nil {
+				errs <- err
+				return
+			}
+			select {
+			case reports <- req:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+
+	// inprogress records the last number of requests in progress
+	// reported by this Envoy for each upstream.
+	inprogress := make(map[metrics.Upstream]uint64)
+	defer func() {
+		// this Envoy no longer contributes to the in progress gauge.
+		for u, n := range inprogress {
+			lr.AddUpstreamRequestsInProgress(u, -int64(n))
+		}
+	}()
+
+	var upstreams map[string]metrics.Upstream
+	ch := make(chan int, 1)
+	last := -1
+	lr.clusters.Register(ch, last)
+	for {
+		select {
+		case <-ch:
+			// the set of clusters has changed, ask Envoy to
+			// report on the new set.
+			var names []string
+			var clusters []proto.Message
+			last, clusters = lr.clusters.Snapshot(nil)
+			upstreams = make(map[string]metrics.Upstream)
+			for _, v := range clusters {
+				c := v.(*envoy_api_v2.Cluster)
+				names = append(names, c.Name)
+				upstreams[c.Name] = upstream(c)
+			}
+			resp := &loadstats.LoadStatsResponse{
+				Clusters:              names,
+				LoadReportingInterval: protobuf.Duration(loadReportingInterval),
+			}
+			if err := st.Send(resp); err != nil {
+				return err
+			}
+			log.WithField("count", len(names)).Info("load reporting clusters")
+			lr.clusters.Register(ch, last)
+
+			// Envoy no longer reports on clusters which have
+			// been removed, so their requests in progress
+			// would never be updated again.
+			for u, n := range inprogress {
+				if _, ok := upstreams[u.Cluster]; !ok {
+					lr.AddUpstreamRequestsInProgress(u, -int64(n))
+					delete(inprogress, u)
+				}
+			}
+
+			if reports == nil {
+				reports = make(chan *loadstats.LoadStatsRequest)
+				go recv()
+			}
+		case req := <-reports:
+			for _, cs := range req.ClusterStats {
+				u, ok := upstreams[cs.ClusterName]
+				if !ok {
+					// the cluster was removed after Envoy
+					// collected this report.
+					continue
+				}
+				load := metrics.UpstreamLoad{
+					Dropped: cs.TotalDroppedRequests,
+				}
+				var current uint64
+				for _, ls := range cs.UpstreamLocalityStats {
+					load.Successful += ls.TotalSuccessfulRequests
+					load.Errors += ls.TotalErrorRequests
+					load.Issued += ls.TotalIssuedRequests
+					current += ls.TotalRequestsInProgress
+				}
+				lr.AddUpstreamLoad(u, load)
+				lr.AddUpstreamRequestsInProgress(u, int64(current)-int64(inprogress[u]))
+				inprogress[u] = current
+			}
+		case err := <-errs:
+			return err
+		case <-drained:
+			return errDraining
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// upstream returns the metrics.Upstream for c. The namespace and
+// service are recovered from the cluster's alt stat name as the
+// cluster name may have been truncated.
+func upstream(c *envoy_api_v2.Cluster) metrics.Upstream {
+	u := metrics.Upstream{
+		Cluster: c.Name,
+	}
+	// alt stat names take the form namespace_name_port.
+	if parts := strings.SplitN(c.AltStatName, "_", 3); len(parts) == 3 {
+		u.Namespace = parts[0]
+		u.Service = parts[1]
+	}
+	return u
+}
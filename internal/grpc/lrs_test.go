@@ -0,0 +1,270 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"sync"
+	"testing"
+	"time"
+
+	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	envoy_api_v2_endpoint "github.com/envoyproxy/go-control-plane/envoy/api/v2/endpoint"
+	loadstats "github.com/envoyproxy/go-control-plane/envoy/service/load_stats/v2"
+	"github.com/golang/protobuf/proto"
+	"github.com/projectcontour/contour/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+func TestLoadReporterStream(t *testing.T) {
+	log := logrus.New()
+	log.SetOutput(ioutil.Discard)
+
+	r := prometheus.NewRegistry()
+	lr := &loadReporter{
+		FieldLogger: log,
+		Metrics:     metrics.NewMetrics(r),
+		clusters: &mockResource{
+			register: func(ch chan int, last int) {
+				if last < 0 {
+					ch <- 1
+				}
+			},
+			contents: func() []proto.Message {
+				return []proto.Message{&v2.Cluster{
+					Name:        "default/kuard/80/da39a3ee5e",
+					AltStatName: "default_kuard_80",
+				}}
+			},
+		},
+	}
+
+	requests := []*loadstats.LoadStatsRequest{{
+		Node: &envoy_api_v2_core.Node{Id: "envoy"},
+	}, {
+		ClusterStats: []*envoy_api_v2_endpoint.ClusterStats{{
+			ClusterName:          "default/kuard/80/da39a3ee5e",
+			TotalDroppedRequests: 1,
+			UpstreamLocalityStats: []*envoy_api_v2_endpoint.UpstreamLocalityStats{{
+				TotalSuccessfulRequests: 10,
+				TotalErrorRequests:      2,
+				TotalIssuedRequests:     15,
+				TotalRequestsInProgress: 3,
+			}},
+		}},
+	}}
+
+	sent := make(chan *loadstats.LoadStatsResponse, 1)
+	done := make(chan struct{})
+	st := &mockLoadStatsStream{
+		context: context.Background,
+		send: func(resp *loadstats.LoadStatsResponse) error {
+			sent <- resp
+			return nil
+		},
+		recv: func() (*loadstats.LoadStatsRequest, error) {
+			if len(requests) == 0 {
+				// wait until the test has observed the report.
+				<-done
+				return nil, io.EOF
+			}
+			req := requests[0]
+			if req.Node == nil {
+				// hold the first report until the initial
+				// response has been sent.
+				<-sent
+			}
+			requests = requests[1:]
+			return req, nil
+		},
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- lr.stream(st)
+	}()
+
+	u := metrics.Upstream{Cluster: "default/kuard/80/da39a3ee5e", Namespace: "default", Service: "kuard"}
+	waitFor(t, func() bool {
+		return counterValue(t, r, metrics.UpstreamSuccessfulRequestsCounter, u) == 10
+	})
+	assertValue(t, r, metrics.UpstreamErrorRequestsCounter, u, 2)
+	assertValue(t, r, metrics.UpstreamIssuedRequestsCounter, u, 15)
+	assertValue(t, r, metrics.UpstreamDroppedRequestsCounter, u, 1)
+	assertValue(t, r, metrics.UpstreamRequestsInProgressGauge, u, 3)
+
+	close(done)
+	if err := <-errs; err != io.EOF {
+		t.Fatalf("expected %v, got %v", io.EOF, err)
+	}
+
+	// the stream has terminated so its in progress requests are
+	// no longer counted.
+	assertValue(t, r, metrics.UpstreamRequestsInProgressGauge, u, 0)
+}
+
+func TestLoadReporterClusterRemoved(t *testing.T) {
+	log := logrus.New()
+	log.SetOutput(ioutil.Discard)
+
+	// registered receives the channel registered once Envoy has
+	// been sent the first set of clusters.
+	registered := make(chan chan int, 1)
+	var mu sync.Mutex
+	clusters := []proto.Message{&v2.Cluster{
+		Name:        "default/kuard/80/da39a3ee5e",
+		AltStatName: "default_kuard_80",
+	}}
+
+	r := prometheus.NewRegistry()
+	lr := &loadReporter{
+		FieldLogger: log,
+		Metrics:     metrics.NewMetrics(r),
+		clusters: &mockResource{
+			register: func(ch chan int, last int) {
+				if last < 0 {
+					ch <- 1
+					return
+				}
+				registered <- ch
+			},
+			contents: func() []proto.Message {
+				mu.Lock()
+				defer mu.Unlock()
+				return clusters
+			},
+		},
+	}
+
+	requests := []*loadstats.LoadStatsRequest{{
+		Node: &envoy_api_v2_core.Node{Id: "envoy"},
+	}, {
+		ClusterStats: []*envoy_api_v2_endpoint.ClusterStats{{
+			ClusterName: "default/kuard/80/da39a3ee5e",
+			UpstreamLocalityStats: []*envoy_api_v2_endpoint.UpstreamLocalityStats{{
+				TotalRequestsInProgress: 3,
+			}},
+		}},
+	}}
+
+	sent := make(chan *loadstats.LoadStatsResponse, 1)
+	done := make(chan struct{})
+	st := &mockLoadStatsStream{
+		context: context.Background,
+		send: func(resp *loadstats.LoadStatsResponse) error {
+			sent <- resp
+			return nil
+		},
+		recv: func() (*loadstats.LoadStatsRequest, error) {
+			if len(requests) == 0 {
+				<-done
+				return nil, io.EOF
+			}
+			req := requests[0]
+			if req.Node == nil {
+				<-sent
+			}
+			requests = requests[1:]
+			return req, nil
+		},
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- lr.stream(st)
+	}()
+
+	u := metrics.Upstream{Cluster: "default/kuard/80/da39a3ee5e", Namespace: "default", Service: "kuard"}
+	waitFor(t, func() bool {
+		return counterValue(t, r, metrics.UpstreamRequestsInProgressGauge, u) == 3
+	})
+
+	// remove the cluster; Envoy will no longer report on it so its
+	// requests in progress are no longer counted.
+	mu.Lock()
+	clusters = nil
+	mu.Unlock()
+	(<-registered) <- 2
+
+	resp := <-sent
+	if len(resp.Clusters) != 0 {
+		t.Fatalf("expected no clusters, got %v", resp.Clusters)
+	}
+	waitFor(t, func() bool {
+		return counterValue(t, r, metrics.UpstreamRequestsInProgressGauge, u) == 0
+	})
+
+	close(done)
+	if err := <-errs; err != io.EOF {
+		t.Fatalf("expected %v, got %v", io.EOF, err)
+	}
+}
+
+type mockLoadStatsStream struct {
+	context func() context.Context
+	send    func(*loadstats.LoadStatsResponse) error
+	recv    func() (*loadstats.LoadStatsRequest, error)
+}
+
+func (m *mockLoadStatsStream) Context() context.Context                     { return m.context() }
+func (m *mockLoadStatsStream) Send(resp *loadstats.LoadStatsResponse) error { return m.send(resp) }
+func (m *mockLoadStatsStream) Recv() (*loadstats.LoadStatsRequest, error)   { return m.recv() }
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	for i := 0; i < 1000; i++ {
+		if cond() {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("timed out waiting for condition")
+}
+
+func assertValue(t *testing.T, r *prometheus.Registry, name string, u metrics.Upstream, want float64) {
+	t.Helper()
+	if got := counterValue(t, r, name, u); got != want {
+		t.Fatalf("%s: expected %v, got %v", name, want, got)
+	}
+}
+
+// counterValue returns the value of the named counter or gauge for u.
+func counterValue(t *testing.T, r *prometheus.Registry, name string, u metrics.Upstream) float64 {
+	t.Helper()
+	families, err := r.Gather()
+	check(t, err)
+	for _, mf := range families {
+		if mf.GetName() != name {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			labels := make(map[string]string)
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["cluster"] != u.Cluster || labels["namespace"] != u.Namespace || labels["service"] != u.Service {
+				continue
+			}
+			if m.Counter != nil {
+				return m.Counter.GetValue()
+			}
+			return m.Gauge.GetValue()
+		}
+	}
+	return 0
+}
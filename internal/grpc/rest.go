@@ -0,0 +1,79 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"fmt"
+	"net/http"
+
+	envoy_api_v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	"github.com/envoyproxy/go-control-plane/pkg/cache"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/sirupsen/logrus"
+)
+
+// RegisterREST registers handlers for the Envoy v2 REST-JSON xDS API
+// on mux. Each handler answers a JSON encoded DiscoveryRequest, or an
+// empty body, with the current contents of the matching resource.
+//
+// SDS is deliberately not served as the debug listener is unauthenticated
+// and secrets carry TLS private keys.
+func RegisterREST(mux *http.ServeMux, log logrus.FieldLogger, resources map[string]Resource) {
+	xh := &xdsHandler{
+		FieldLogger: log,
+		resources:   resources,
+	}
+	mux.Handle("/v2/discovery:clusters", xh.restHandler(cache.ClusterType))
+	mux.Handle("/v2/discovery:endpoints", xh.restHandler(cache.EndpointType))
+	mux.Handle("/v2/discovery:listeners", xh.restHandler(cache.ListenerType))
+	mux.Handle("/v2/discovery:routes", xh.restHandler(cache.RouteType))
+}
+
+func (xh *xdsHandler) restHandler(typeURL string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := xh.resources[typeURL]; !ok {
+			http.Error(w, fmt.Sprintf("no resource registered for typeURL %q", typeURL), http.StatusNotFound)
+			return
+		}
+
+		var req envoy_api_v2.DiscoveryRequest
+		switch r.Method {
+		case http.MethodGet:
+			// no request body, return the full contents.
+		case http.MethodPost:
+			if r.ContentLength != 0 {
+				if err := jsonpb.Unmarshal(r.Body, &req); err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+			}
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		resp, err := xh.fetch(r.Context(), typeURL, &req)
+		if err != nil {
+			xh.WithError(err).WithField("type_url", typeURL).Error("fetch failed")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		m := &jsonpb.Marshaler{OrigName: true}
+		if err := m.Marshal(w, resp); err != nil {
+			xh.WithError(err).WithField("type_url", typeURL).Error("failed to write response")
+		}
+	})
+}
@@ -0,0 +1,130 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	"github.com/envoyproxy/go-control-plane/pkg/cache"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/sirupsen/logrus"
+)
+
+func TestRESTHandler(t *testing.T) {
+	log := logrus.New()
+	log.SetOutput(ioutil.Discard)
+
+	clusters := &mockResource{
+		version: 7,
+		contents: func() []proto.Message {
+			return []proto.Message{&v2.Cluster{Name: "default/kuard/80"}}
+		},
+		query: func(names []string) []proto.Message {
+			var values []proto.Message
+			for _, n := range names {
+				values = append(values, &v2.Cluster{Name: n})
+			}
+			return values
+		},
+		typeurl: func() string { return cache.ClusterType },
+	}
+
+	var mux http.ServeMux
+	RegisterREST(&mux, log, map[string]Resource{
+		cache.ClusterType: clusters,
+	})
+
+	tests := map[string]struct {
+		method string
+		path   string
+		body   string
+		code   int
+		want   []string
+	}{
+		"get all clusters": {
+			method: http.MethodGet,
+			path:   "/v2/discovery:clusters",
+			code:   http.StatusOK,
+			want:   []string{"default/kuard/80"},
+		},
+		"post named clusters": {
+			method: http.MethodPost,
+			path:   "/v2/discovery:clusters",
+			body:   `{"resource_names": ["default/httpbin/8080"]}`,
+			code:   http.StatusOK,
+			want:   []string{"default/httpbin/8080"},
+		},
+		"malformed request": {
+			method: http.MethodPost,
+			path:   "/v2/discovery:clusters",
+			body:   `{"resource_names": 7}`,
+			code:   http.StatusBadRequest,
+		},
+		"unsupported method": {
+			method: http.MethodDelete,
+			path:   "/v2/discovery:clusters",
+			code:   http.StatusMethodNotAllowed,
+		},
+		"unregistered resource": {
+			method: http.MethodGet,
+			path:   "/v2/discovery:routes",
+			code:   http.StatusNotFound,
+		},
+		"secrets are not served": {
+			method: http.MethodGet,
+			path:   "/v2/discovery:secrets",
+			code:   http.StatusNotFound,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tc.code {
+				t.Fatalf("expected status %d, got %d: %s", tc.code, rec.Code, rec.Body.String())
+			}
+			if tc.code != http.StatusOK {
+				return
+			}
+
+			var resp v2.DiscoveryResponse
+			if err := jsonpb.Unmarshal(rec.Body, &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.VersionInfo != "7" {
+				t.Fatalf("expected version %q, got %q", "7", resp.VersionInfo)
+			}
+			var got []string
+			for _, a := range resp.Resources {
+				var c v2.Cluster
+				if err := proto.Unmarshal(a.Value, &c); err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, c.Name)
+			}
+			if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
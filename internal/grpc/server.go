@@ -16,6 +16,7 @@ package grpc
 
 import (
 	"context"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -27,17 +28,66 @@ import (
 	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
 	discovery "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v2"
 	loadstats "github.com/envoyproxy/go-control-plane/envoy/service/load_stats/v2"
+	"github.com/envoyproxy/go-control-plane/pkg/cache"
+	"github.com/projectcontour/contour/internal/metrics"
 	"github.com/sirupsen/logrus"
 )
 
-// NewAPI returns a *grpc.Server which responds to the Envoy v2 xDS gRPC API.
-func NewAPI(log logrus.FieldLogger, resources map[string]Resource, registry *prometheus.Registry, opts ...grpc.ServerOption) *grpc.Server {
+// shutdownGrace is the time, beyond the drain period, Shutdown waits
+// for streams to terminate before closing their connections.
+const shutdownGrace = 5 * time.Second
+
+// Server is a *grpc.Server which responds to the Envoy v2 xDS gRPC API.
+type Server struct {
+	*grpc.Server
+	drain *drainer
+}
+
+// Shutdown stops the server accepting new connections, then terminates
+// each open stream at a random point in the drain period so that Envoys
+// reconnect to other Contour replicas gradually. Connections which
+// remain open after the drain period are closed. Shutdown returns once
+// all connections are closed.
+func (s *Server) Shutdown(drain time.Duration) {
+	s.drain.start(drain)
+
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+
+	t := time.NewTimer(drain + shutdownGrace)
+	defer t.Stop()
+	select {
+	case <-stopped:
+	case <-t.C:
+		s.Stop()
+	}
+}
+
+// NewAPI returns a *Server which responds to the Envoy v2 xDS gRPC API.
+// If m is not nil, load reports received from Envoy are recorded in m.
+// If auth is not nil, only clients it permits are served.
+func NewAPI(log logrus.FieldLogger, resources map[string]Resource, registry *prometheus.Registry, m *metrics.Metrics, auth *Authorizer, opts ...grpc.ServerOption) *Server {
+	drain := newDrainer()
 	s := &grpcServer{
-		xdsHandler{
+		xdsHandler: xdsHandler{
 			FieldLogger: log,
 			resources:   resources,
+			drain:       drain,
+			auth:        auth,
+			Metrics:     m,
 		},
-		grpc_prometheus.NewServerMetrics(),
+		metrics: grpc_prometheus.NewServerMetrics(),
+	}
+	if clusters, ok := resources[cache.ClusterType]; ok && m != nil {
+		s.lrs = &loadReporter{
+			FieldLogger: log,
+			Metrics:     m,
+			clusters:    clusters,
+			drain:       drain,
+		}
 	}
 	registry.MustRegister(s.metrics)
 	opts = append(opts, grpc.StreamInterceptor(s.metrics.StreamServerInterceptor()),
@@ -48,42 +98,47 @@ func NewAPI(log logrus.FieldLogger, resources map[string]Resource, registry *pro
 	v2.RegisterListenerDiscoveryServiceServer(g, s)
 	v2.RegisterRouteDiscoveryServiceServer(g, s)
 	discovery.RegisterSecretDiscoveryServiceServer(g, s)
+	loadstats.RegisterLoadReportingServiceServer(g, s)
 	s.metrics.InitializeMetrics(g)
-	return g
+	return &Server{
+		Server: g,
+		drain:  drain,
+	}
 }
 
-// grpcServer implements the LDS, RDS, CDS, and EDS, gRPC endpoints.
+// grpcServer implements the LDS, RDS, CDS, EDS, SDS, and LRS gRPC endpoints.
 type grpcServer struct {
 	xdsHandler
 	metrics *grpc_prometheus.ServerMetrics
+	lrs     *loadReporter
 }
 
-func (s *grpcServer) FetchClusters(_ context.Context, req *v2.DiscoveryRequest) (*v2.DiscoveryResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "FetchClusters unimplemented")
+func (s *grpcServer) FetchClusters(ctx context.Context, req *v2.DiscoveryRequest) (*v2.DiscoveryResponse, error) {
+	return s.fetch(ctx, cache.ClusterType, req)
 }
 
-func (s *grpcServer) FetchEndpoints(_ context.Context, req *v2.DiscoveryRequest) (*v2.DiscoveryResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "FetchEndpoints unimplemented")
+func (s *grpcServer) FetchEndpoints(ctx context.Context, req *v2.DiscoveryRequest) (*v2.DiscoveryResponse, error) {
+	return s.fetch(ctx, cache.EndpointType, req)
 }
 
 func (s *grpcServer) DeltaEndpoints(v2.EndpointDiscoveryService_DeltaEndpointsServer) error {
 	return status.Errorf(codes.Unimplemented, "DeltaEndpoints unimplemented")
 }
 
-func (s *grpcServer) FetchListeners(_ context.Context, req *v2.DiscoveryRequest) (*v2.DiscoveryResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "FetchListeners unimplemented")
+func (s *grpcServer) FetchListeners(ctx context.Context, req *v2.DiscoveryRequest) (*v2.DiscoveryResponse, error) {
+	return s.fetch(ctx, cache.ListenerType, req)
 }
 
 func (s *grpcServer) DeltaListeners(v2.ListenerDiscoveryService_DeltaListenersServer) error {
 	return status.Errorf(codes.Unimplemented, "DeltaListeners unimplemented")
 }
 
-func (s *grpcServer) FetchRoutes(_ context.Context, req *v2.DiscoveryRequest) (*v2.DiscoveryResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "FetchRoutes unimplemented")
+func (s *grpcServer) FetchRoutes(ctx context.Context, req *v2.DiscoveryRequest) (*v2.DiscoveryResponse, error) {
+	return s.fetch(ctx, cache.RouteType, req)
 }
 
-func (s *grpcServer) FetchSecrets(_ context.Context, req *v2.DiscoveryRequest) (*v2.DiscoveryResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "FetchSecrets unimplemented")
+func (s *grpcServer) FetchSecrets(ctx context.Context, req *v2.DiscoveryRequest) (*v2.DiscoveryResponse, error) {
+	return s.fetch(ctx, cache.SecretType, req)
 }
 
 func (s *grpcServer) DeltaSecrets(discovery.SecretDiscoveryService_DeltaSecretsServer) error {
@@ -99,7 +154,13 @@ func (s *grpcServer) StreamEndpoints(srv v2.EndpointDiscoveryService_StreamEndpo
 }
 
 func (s *grpcServer) StreamLoadStats(srv loadstats.LoadReportingService_StreamLoadStatsServer) error {
-	return status.Errorf(codes.Unimplemented, "StreamLoadStats unimplemented")
+	if s.lrs == nil {
+		return status.Errorf(codes.Unimplemented, "StreamLoadStats unimplemented")
+	}
+	if err := s.authorize(srv.Context()); err != nil {
+		return err
+	}
+	return s.lrs.stream(srv)
 }
 
 func (s *grpcServer) DeltaClusters(v2.ClusterDiscoveryService_DeltaClustersServer) error {
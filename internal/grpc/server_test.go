@@ -182,6 +182,84 @@ func TestGRPC(t *testing.T) {
 			checkrecv(t, stream)                 // check we receive one notification
 			checktimeout(t, stream)              // check that the second receive times out
 		},
+		"FetchClusters": func(t *testing.T, cc *grpc.ClientConn) {
+			eh.OnAdd(&v1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "simple",
+					Namespace: "default",
+				},
+				Spec: v1.ServiceSpec{
+					Selector: map[string]string{
+						"app": "simple",
+					},
+					Ports: []v1.ServicePort{{
+						Protocol:   "TCP",
+						Port:       80,
+						TargetPort: intstr.FromInt(6502),
+					}},
+				},
+			})
+
+			cds := v2.NewClusterDiscoveryServiceClient(cc)
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			resp, err := cds.FetchClusters(ctx, &v2.DiscoveryRequest{})
+			check(t, err)
+			checktypeurl(t, resp, cache.ClusterType)
+		},
+		"FetchEndpoints": func(t *testing.T, cc *grpc.ClientConn) {
+			et.OnAdd(&v1.Endpoints{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "kube-scheduler",
+					Namespace: "kube-system",
+				},
+				Subsets: []v1.EndpointSubset{{
+					Addresses: []v1.EndpointAddress{{
+						IP: "130.211.139.167",
+					}},
+					Ports: []v1.EndpointPort{{
+						Port:     80,
+						Protocol: "TCP",
+					}},
+				}},
+			})
+
+			eds := v2.NewEndpointDiscoveryServiceClient(cc)
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			resp, err := eds.FetchEndpoints(ctx, &v2.DiscoveryRequest{
+				ResourceNames: []string{"kube-system/kube-scheduler"},
+			})
+			check(t, err)
+			checktypeurl(t, resp, cache.EndpointType)
+			if len(resp.Resources) != 1 {
+				t.Fatalf("expected 1 resource, got %d", len(resp.Resources))
+			}
+		},
+		"FetchListeners": func(t *testing.T, cc *grpc.ClientConn) {
+			lds := v2.NewListenerDiscoveryServiceClient(cc)
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			resp, err := lds.FetchListeners(ctx, &v2.DiscoveryRequest{})
+			check(t, err)
+			checktypeurl(t, resp, cache.ListenerType)
+		},
+		"FetchRoutes": func(t *testing.T, cc *grpc.ClientConn) {
+			rds := v2.NewRouteDiscoveryServiceClient(cc)
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			resp, err := rds.FetchRoutes(ctx, &v2.DiscoveryRequest{})
+			check(t, err)
+			checktypeurl(t, resp, cache.RouteType)
+		},
+		"FetchSecrets": func(t *testing.T, cc *grpc.ClientConn) {
+			sds := discovery.NewSecretDiscoveryServiceClient(cc)
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			resp, err := sds.FetchSecrets(ctx, &v2.DiscoveryRequest{})
+			check(t, err)
+			checktypeurl(t, resp, cache.SecretType)
+		},
 	}
 
 	log := logrus.New()
@@ -192,7 +270,8 @@ func TestGRPC(t *testing.T) {
 				FieldLogger: log,
 			}
 			ch := contour.CacheHandler{
-				Metrics: metrics.NewMetrics(prometheus.NewRegistry()),
+				Metrics:     metrics.NewMetrics(prometheus.NewRegistry()),
+				FieldLogger: log,
 			}
 			eh = &contour.EventHandler{
 				CacheHandler: &ch,
@@ -206,7 +285,7 @@ func TestGRPC(t *testing.T) {
 				ch.ListenerCache.TypeURL(): &ch.ListenerCache,
 				ch.SecretCache.TypeURL():   &ch.SecretCache,
 				et.TypeURL():               et,
-			}, r)
+			}, r, ch.Metrics, nil)
 			l, err := net.Listen("tcp", "127.0.0.1:0")
 			check(t, err)
 			done := make(chan error, 1)
@@ -254,6 +333,13 @@ func checkrecv(t *testing.T, stream interface {
 	check(t, err)
 }
 
+func checktypeurl(t *testing.T, resp *v2.DiscoveryResponse, typeurl string) {
+	t.Helper()
+	if resp.TypeUrl != typeurl {
+		t.Fatalf("expected type url %q, got %q", typeurl, resp.TypeUrl)
+	}
+}
+
 func checktimeout(t *testing.T, stream interface {
 	Recv() (*v2.DiscoveryResponse, error)
 }) {
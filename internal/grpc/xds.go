@@ -22,17 +22,19 @@ import (
 	envoy_api_v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes/any"
+	"github.com/projectcontour/contour/internal/metrics"
 	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Resource represents a source of proto.Messages that can be registered
 // for interest.
 type Resource interface {
-	// Contents returns the contents of this resource.
-	Contents() []proto.Message
-
-	// Query returns an entry for each resource name supplied.
-	Query(names []string) []proto.Message
+	// Snapshot returns the current version of this resource and its
+	// contents at that version. If names are supplied, Snapshot returns
+	// an entry for each name rather than the full contents.
+	Snapshot(names []string) (int, []proto.Message)
 
 	// Register registers ch to receive a value when Notify is called.
 	Register(chan int, int, ...string)
@@ -46,6 +48,9 @@ type xdsHandler struct {
 	logrus.FieldLogger
 	connections counter
 	resources   map[string]Resource // registered resource types
+	drain       *drainer
+	auth        *Authorizer // if nil, all clients are permitted
+	*metrics.Metrics
 }
 
 type grpcStream interface {
@@ -69,6 +74,10 @@ func (xh *xdsHandler) stream(st grpcStream) (err error) {
 		}
 	}()
 
+	if err := xh.authorize(st.Context()); err != nil {
+		return err
+	}
+
 	ch := make(chan int, 1)
 
 	// internally all registration values start at zero so sending
@@ -76,6 +85,7 @@ func (xh *xdsHandler) stream(st grpcStream) (err error) {
 	// will generate a response immediately, then wait.
 	last := -1
 	ctx := st.Context()
+	drained := xh.drain.wait(ctx)
 
 	// now stick in this loop until the client disconnects.
 	for {
@@ -111,21 +121,15 @@ func (xh *xdsHandler) stream(st grpcStream) (err error) {
 		// connection last will be less than zero and that will trigger a response immediately.
 		r.Register(ch, last, req.ResourceNames...)
 		select {
-		case last = <-ch:
+		case <-ch:
 			// boom, something in the cache has changed.
 			// TODO(dfc) the thing that has changed may not be in the scope of the filter
 			// so we're going to be sending an update that is a no-op. See #426
 
+			// the snapshot may be newer than the notification; its
+			// version, not the notified one, describes what we send.
 			var resources []proto.Message
-			switch len(req.ResourceNames) {
-			case 0:
-				// no resource hints supplied, return the full
-				// contents of the resource
-				resources = r.Contents()
-			default:
-				// resource hints supplied, return exactly those
-				resources = r.Query(req.ResourceNames)
-			}
+			last, resources = r.Snapshot(req.ResourceNames)
 
 			any, err := toAny(r.TypeURL(), resources)
 			if err != nil {
@@ -142,12 +146,42 @@ func (xh *xdsHandler) stream(st grpcStream) (err error) {
 				return err
 			}
 			log.WithField("count", len(resources)).Info("response")
+		case <-drained:
+			return errDraining
 		case <-ctx.Done():
 			return ctx.Err()
 		}
 	}
 }
 
+// fetch returns a DiscoveryResponse containing the current contents of
+// the resource registered for typeURL. If req supplies resource names,
+// only those entries are returned.
+func (xh *xdsHandler) fetch(ctx context.Context, typeURL string, req *envoy_api_v2.DiscoveryRequest) (*envoy_api_v2.DiscoveryResponse, error) {
+	if err := xh.authorize(ctx); err != nil {
+		return nil, err
+	}
+
+	r, ok := xh.resources[typeURL]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no resource registered for typeURL %q", typeURL)
+	}
+
+	last, resources := r.Snapshot(req.ResourceNames)
+
+	any, err := toAny(r.TypeURL(), resources)
+	if err != nil {
+		return nil, err
+	}
+
+	return &envoy_api_v2.DiscoveryResponse{
+		VersionInfo: strconv.Itoa(last),
+		Resources:   any,
+		TypeUrl:     r.TypeURL(),
+		Nonce:       strconv.Itoa(last),
+	}, nil
+}
+
 // toAny converts the contents of a resourcer's Values to the
 // respective slice of *any.Any.
 func toAny(typeURL string, values []proto.Message) ([]*any.Any, error) {
@@ -23,6 +23,8 @@ import (
 	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
 	"github.com/golang/protobuf/proto"
 	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestXDSHandlerStream(t *testing.T) {
@@ -150,6 +152,16 @@ func TestXDSHandlerStream(t *testing.T) {
 	}
 }
 
+func TestXDSHandlerFetchUnregisteredType(t *testing.T) {
+	log := logrus.New()
+	log.SetOutput(ioutil.Discard)
+	xh := xdsHandler{FieldLogger: log}
+	_, err := xh.fetch(context.Background(), "com.heptio.potato", &v2.DiscoveryRequest{})
+	if got := status.Code(err); got != codes.NotFound {
+		t.Fatalf("expected %v, got %v", codes.NotFound, got)
+	}
+}
+
 type mockStream struct {
 	context func() context.Context
 	send    func(*v2.DiscoveryResponse) error
@@ -161,14 +173,19 @@ func (m *mockStream) Send(resp *v2.DiscoveryResponse) error { return m.send(resp
 func (m *mockStream) Recv() (*v2.DiscoveryRequest, error)   { return m.recv() }
 
 type mockResource struct {
+	version  int
 	contents func() []proto.Message
 	query    func([]string) []proto.Message
 	register func(chan int, int)
 	typeurl  func() string
 }
 
-func (m *mockResource) Contents() []proto.Message                       { return m.contents() }
-func (m *mockResource) Query(names []string) []proto.Message            { return m.query(names) }
+func (m *mockResource) Snapshot(names []string) (int, []proto.Message) {
+	if len(names) == 0 {
+		return m.version, m.contents()
+	}
+	return m.version, m.query(names)
+}
 func (m *mockResource) Register(ch chan int, last int, hints ...string) { m.register(ch, last) }
 func (m *mockResource) TypeURL() string                                 { return m.typeurl() }
 
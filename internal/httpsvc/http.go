@@ -29,6 +29,10 @@ type Service struct {
 	Addr string
 	Port int
 
+	// CertFile and KeyFile, if set, are the certificate and
+	// key used to serve HTTPS.
+	CertFile, KeyFile string
+
 	logrus.FieldLogger
 	http.ServeMux
 }
@@ -64,5 +68,8 @@ func (svc *Service) Start(stop <-chan struct{}) (err error) {
 	}()
 
 	svc.WithField("address", s.Addr).Info("started")
+	if svc.CertFile != "" || svc.KeyFile != "" {
+		return s.ListenAndServeTLS(svc.CertFile, svc.KeyFile)
+	}
 	return s.ListenAndServe()
 }
@@ -0,0 +1,80 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// EndpointSlicesResource is the discovery.k8s.io/v1 EndpointSlice resource.
+var EndpointSlicesResource = schema.GroupVersionResource{
+	Group:    "discovery.k8s.io",
+	Version:  "v1",
+	Resource: "endpointslices",
+}
+
+// ServiceNameLabel is the label on an EndpointSlice naming the
+// Service it belongs to.
+const ServiceNameLabel = "kubernetes.io/service-name"
+
+// EndpointSlice holds the fields Contour uses of a discovery.k8s.io/v1
+// EndpointSlice. The vendored k8s.io/api predates the EndpointSlice
+// API, so slices are watched as unstructured objects and converted.
+type EndpointSlice struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// AddressType is one of IPv4, IPv6 or FQDN.
+	AddressType string         `json:"addressType"`
+	Endpoints   []Endpoint     `json:"endpoints"`
+	Ports       []EndpointPort `json:"ports"`
+}
+
+// Endpoint is a single backend of an EndpointSlice.
+type Endpoint struct {
+	// Addresses are fungible, consumers use the first.
+	Addresses  []string           `json:"addresses"`
+	Conditions EndpointConditions `json:"conditions,omitempty"`
+	NodeName   *string            `json:"nodeName,omitempty"`
+	Zone       *string            `json:"zone,omitempty"`
+}
+
+// EndpointConditions are the current state of an Endpoint.
+// A nil condition is unknown.
+type EndpointConditions struct {
+	Ready       *bool `json:"ready,omitempty"`
+	Serving     *bool `json:"serving,omitempty"`
+	Terminating *bool `json:"terminating,omitempty"`
+}
+
+// EndpointPort is a port served by every Endpoint of an EndpointSlice.
+type EndpointPort struct {
+	Name     *string      `json:"name,omitempty"`
+	Protocol *v1.Protocol `json:"protocol,omitempty"`
+	Port     *int32       `json:"port,omitempty"`
+}
+
+// ToEndpointSlice converts an unstructured discovery.k8s.io/v1
+// EndpointSlice into an EndpointSlice.
+func ToEndpointSlice(u *unstructured.Unstructured) (*EndpointSlice, error) {
+	slice := new(EndpointSlice)
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, slice); err != nil {
+		return nil, err
+	}
+	return slice, nil
+}
@@ -15,6 +15,7 @@ package k8s
 
 import (
 	ingressroutev1 "github.com/projectcontour/contour/apis/contour/v1beta1"
+	gatewayapi "github.com/projectcontour/contour/apis/networking/v1alpha1"
 	projectcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
 	v1 "k8s.io/api/core/v1"
 	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
@@ -44,6 +45,16 @@ func KindOf(obj interface{}) string {
 		return "TLSCertificateDelegation"
 	case *projectcontour.TLSCertificateDelegation:
 		return "TLSCertificateDelegation"
+	case *projectcontour.ServiceDelegation:
+		return "ServiceDelegation"
+	case *projectcontour.Upstream:
+		return "Upstream"
+	case *gatewayapi.GatewayClass:
+		return "GatewayClass"
+	case *gatewayapi.Gateway:
+		return "Gateway"
+	case *gatewayapi.HTTPRoute:
+		return "HTTPRoute"
 	default:
 		return ""
 	}
@@ -0,0 +1,115 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// ReadManifests returns the objects decoded from the YAML or JSON
+// documents in r. Only objects of a kind returned by KindOf are
+// returned, documents of other kinds are skipped.
+func ReadManifests(r io.Reader) ([]interface{}, error) {
+	decoder := serializer.NewCodecFactory(NewScheme()).UniversalDeserializer()
+	reader := yaml.NewYAMLReader(bufio.NewReader(r))
+
+	var objs []interface{}
+	for {
+		doc, err := reader.Read()
+		if err == io.EOF {
+			return objs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := yaml.ToJSON(doc)
+		if err != nil {
+			return nil, err
+		}
+		if data = bytes.TrimSpace(data); len(data) == 0 || string(data) == "null" {
+			// an empty document.
+			continue
+		}
+
+		obj, _, err := decoder.Decode(data, nil, nil)
+		switch {
+		case runtime.IsNotRegisteredError(err):
+			// not a kind Contour knows about.
+			continue
+		case err != nil:
+			return nil, err
+		}
+		if KindOf(obj) != "" {
+			objs = append(objs, obj)
+		}
+	}
+}
+
+// LoadManifests returns the objects decoded from each of paths. Each
+// directory in paths is searched recursively for .yaml, .yml, and
+// .json files.
+func LoadManifests(paths ...string) ([]interface{}, error) {
+	var objs []interface{}
+	for _, path := range paths {
+		err := filepath.Walk(path, func(file string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+			if file != path && !isManifest(file) {
+				// files named explicitly are always read.
+				return nil
+			}
+
+			f, err := os.Open(file)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			o, err := ReadManifests(f)
+			if err != nil {
+				return fmt.Errorf("%s: %v", file, err)
+			}
+			objs = append(objs, o...)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return objs, nil
+}
+
+func isManifest(file string) bool {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	default:
+		return false
+	}
+}
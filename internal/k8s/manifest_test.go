@@ -0,0 +1,86 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"strings"
+	"testing"
+
+	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestReadManifests(t *testing.T) {
+	const manifests = `
+# a leading comment
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: kuard
+  namespace: default
+spec:
+  ports:
+  - port: 80
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: kuard
+---
+apiVersion: example.com/v1
+kind: Unknown
+metadata:
+  name: unknown
+---
+{"apiVersion": "projectcontour.io/v1", "kind": "HTTPProxy", "metadata": {"name": "root"}, "spec": {"virtualhost": {"fqdn": "example.com"}}}
+`
+
+	objs, err := ReadManifests(strings.NewReader(manifests))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []interface{}{
+		&v1.Service{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "kuard",
+				Namespace: "default",
+			},
+			Spec: v1.ServiceSpec{
+				Ports: []v1.ServicePort{{Port: 80}},
+			},
+		},
+		&projcontour.HTTPProxy{
+			TypeMeta: metav1.TypeMeta{APIVersion: "projectcontour.io/v1", Kind: "HTTPProxy"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "root",
+			},
+			Spec: projcontour.HTTPProxySpec{
+				VirtualHost: &projcontour.VirtualHost{Fqdn: "example.com"},
+			},
+		},
+	}
+	assert.Equal(t, want, objs)
+}
+
+func TestReadManifestsInvalid(t *testing.T) {
+	_, err := ReadManifests(strings.NewReader("apiVersion: v1\nkind: Service\nspec: [\n"))
+	if err == nil {
+		t.Fatal("expected an error decoding an invalid manifest")
+	}
+}
@@ -18,23 +18,32 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	jsonpatch "github.com/evanphx/json-patch"
 	ingressroutev1 "github.com/projectcontour/contour/apis/contour/v1beta1"
 	clientset "github.com/projectcontour/contour/apis/generated/clientset/versioned"
+	gatewayapi "github.com/projectcontour/contour/apis/networking/v1alpha1"
 	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
 // StatusClient updates the Status on a Kubernetes object.
 type StatusClient interface {
-	SetStatus(status string, desc string, obj interface{}) error
+	SetStatus(status projcontour.Status, obj interface{}) error
 	GetStatus(obj interface{}) (*projcontour.Status, error)
+
+	// SetConditions sets the conditions of a Gateway API object.
+	SetConditions(conditions []gatewayapi.Condition, obj interface{}) error
+	GetConditions(obj interface{}) ([]gatewayapi.Condition, error)
 }
 
 // StatusCacher keeps a cache of the latest status updates for Kubernetes objects.
 type StatusCacher struct {
-	objectStatus map[string]projcontour.Status
+	objectStatus     map[string]projcontour.Status
+	objectConditions map[string][]gatewayapi.Condition
 }
 
 func objectKey(obj interface{}) string {
@@ -49,6 +58,9 @@ func objectKey(obj interface{}) string {
 			KindOf(obj),
 			obj.GetObjectMeta().GetNamespace(),
 			obj.GetObjectMeta().GetName())
+	case *gatewayapi.GatewayClass, *gatewayapi.Gateway, *gatewayapi.HTTPRoute:
+		om := obj.(metav1.ObjectMetaAccessor).GetObjectMeta()
+		return fmt.Sprintf("%s/%s/%s", KindOf(obj), om.GetNamespace(), om.GetName())
 	default:
 		panic(fmt.Sprintf("status caching not supported for object type %T", obj))
 	}
@@ -59,6 +71,9 @@ func (c *StatusCacher) Delete(obj interface{}) {
 	if c.objectStatus != nil {
 		delete(c.objectStatus, objectKey(obj))
 	}
+	if c.objectConditions != nil {
+		delete(c.objectConditions, objectKey(obj))
+	}
 }
 
 // GetStatus returns the status (if any) for this given object.
@@ -75,23 +90,43 @@ func (c *StatusCacher) GetStatus(obj interface{}) (*projcontour.Status, error) {
 	return &s, nil
 }
 
-// SetStatus sets the IngressRoute status field to an Valid or Invalid status
-func (c *StatusCacher) SetStatus(status, desc string, obj interface{}) error {
+// SetStatus records the status for the given object.
+func (c *StatusCacher) SetStatus(status projcontour.Status, obj interface{}) error {
 	if c.objectStatus == nil {
 		c.objectStatus = make(map[string]projcontour.Status)
 	}
 
-	c.objectStatus[objectKey(obj)] = projcontour.Status{
-		CurrentStatus: status,
-		Description:   desc,
+	c.objectStatus[objectKey(obj)] = status
+
+	return nil
+}
+
+// GetConditions returns the conditions (if any) for this given object.
+func (c *StatusCacher) GetConditions(obj interface{}) ([]gatewayapi.Condition, error) {
+	conds, ok := c.objectConditions[objectKey(obj)]
+	if !ok {
+		return nil, fmt.Errorf("no conditions for key '%s'", objectKey(obj))
+	}
+	return conds, nil
+}
+
+// SetConditions records the conditions for the given object.
+func (c *StatusCacher) SetConditions(conditions []gatewayapi.Condition, obj interface{}) error {
+	if c.objectConditions == nil {
+		c.objectConditions = make(map[string][]gatewayapi.Condition)
 	}
 
+	c.objectConditions[objectKey(obj)] = conditions
+
 	return nil
 }
 
 // StatusWriter updates the object's Status field.
 type StatusWriter struct {
 	Client clientset.Interface
+
+	// now returns the current time, it defaults to time.Now.
+	now func() time.Time
 }
 
 // GetStatus is not implemented for StatusWriter.
@@ -99,38 +134,135 @@ func (irs *StatusWriter) GetStatus(obj interface{}) (*projcontour.Status, error)
 	return nil, errors.New("not implemented")
 }
 
-// SetStatus sets the IngressRoute status field to an Valid or Invalid status
-func (irs *StatusWriter) SetStatus(status, desc string, existing interface{}) error {
+// SetStatus sets the object's status field to status. Conditions
+// which have not changed status keep their existing transition time.
+func (irs *StatusWriter) SetStatus(status projcontour.Status, existing interface{}) error {
 	switch exist := existing.(type) {
 	case *ingressroutev1.IngressRoute:
+		// IngressRoute does not report conditions.
+		status.Conditions = nil
 		// Check if update needed by comparing status & desc
-		if irs.updateNeeded(status, desc, exist.Status) {
+		if irs.updateNeeded(status, exist.Status) {
 			updated := exist.DeepCopy()
-			updated.Status = projcontour.Status{
-				CurrentStatus: status,
-				Description:   desc,
-			}
+			updated.Status = status
 			return irs.setIngressRouteStatus(exist, updated)
 		}
 	case *projcontour.HTTPProxy:
-		// Check if update needed by comparing status & desc
-		if irs.updateNeeded(status, desc, exist.Status) {
+		status.Conditions = irs.conditions(status.Conditions, exist.Status.Conditions, exist.Generation)
+		if irs.updateNeeded(status, exist.Status) {
 			updated := exist.DeepCopy()
-			updated.Status = projcontour.Status{
-				CurrentStatus: status,
-				Description:   desc,
-			}
+			updated.Status = status
 			return irs.setHTTPProxyStatus(exist, updated)
 		}
 	}
 	return nil
 }
 
-func (irs *StatusWriter) updateNeeded(status, desc string, existing projcontour.Status) bool {
-	if existing.CurrentStatus != status || existing.Description != desc {
-		return true
+// GetConditions is not implemented for StatusWriter.
+func (irs *StatusWriter) GetConditions(obj interface{}) ([]gatewayapi.Condition, error) {
+	return nil, errors.New("not implemented")
+}
+
+// SetConditions sets the conditions of a Gateway API object's status.
+// Conditions which have not changed status keep their existing transition time.
+func (irs *StatusWriter) SetConditions(conditions []gatewayapi.Condition, existing interface{}) error {
+	switch exist := existing.(type) {
+	case *gatewayapi.GatewayClass:
+		updated := exist.DeepCopy()
+		updated.Status.Conditions = irs.gatewayConditions(conditions, exist.Status.Conditions, exist.Generation)
+		if irs.updateNeeded(updated.Status, exist.Status) {
+			patch, err := mergePatch(exist, updated)
+			if err != nil {
+				return err
+			}
+			_, err = irs.Client.NetworkingV1alpha1().GatewayClasses().Patch(exist.GetName(), types.MergePatchType, patch)
+			return err
+		}
+	case *gatewayapi.Gateway:
+		updated := exist.DeepCopy()
+		updated.Status.Conditions = irs.gatewayConditions(conditions, exist.Status.Conditions, exist.Generation)
+		if irs.updateNeeded(updated.Status, exist.Status) {
+			patch, err := mergePatch(exist, updated)
+			if err != nil {
+				return err
+			}
+			_, err = irs.Client.NetworkingV1alpha1().Gateways(exist.GetNamespace()).Patch(exist.GetName(), types.MergePatchType, patch)
+			return err
+		}
+	case *gatewayapi.HTTPRoute:
+		updated := exist.DeepCopy()
+		updated.Status.Conditions = irs.gatewayConditions(conditions, exist.Status.Conditions, exist.Generation)
+		if irs.updateNeeded(updated.Status, exist.Status) {
+			patch, err := mergePatch(exist, updated)
+			if err != nil {
+				return err
+			}
+			_, err = irs.Client.NetworkingV1alpha1().HTTPRoutes(exist.GetNamespace()).Patch(exist.GetName(), types.MergePatchType, patch)
+			return err
+		}
+	}
+	return nil
+}
+
+// gatewayConditions is the Gateway API equivalent of conditions.
+func (irs *StatusWriter) gatewayConditions(conds, existing []gatewayapi.Condition, generation int64) []gatewayapi.Condition {
+	now := time.Now
+	if irs.now != nil {
+		now = irs.now
+	}
+
+	var updated []gatewayapi.Condition
+	for _, cond := range conds {
+		cond.ObservedGeneration = generation
+		cond.LastTransitionTime = metav1.NewTime(now())
+		for _, prev := range existing {
+			if prev.Type == cond.Type && prev.Status == cond.Status {
+				cond.LastTransitionTime = prev.LastTransitionTime
+			}
+		}
+		updated = append(updated, cond)
+	}
+	return updated
+}
+
+// conditions returns a copy of conds observed at generation. Each
+// condition's transition time is carried over from the matching
+// condition in existing unless its status has changed.
+func (irs *StatusWriter) conditions(conds, existing []projcontour.DetailedCondition, generation int64) []projcontour.DetailedCondition {
+	now := time.Now
+	if irs.now != nil {
+		now = irs.now
+	}
+
+	var updated []projcontour.DetailedCondition
+	for _, cond := range conds {
+		cond.ObservedGeneration = generation
+		cond.LastTransitionTime = metav1.NewTime(now())
+		for _, prev := range existing {
+			if prev.Type == cond.Type && prev.Status == cond.Status {
+				cond.LastTransitionTime = prev.LastTransitionTime
+			}
+		}
+		updated = append(updated, cond)
+	}
+	return updated
+}
+
+func (irs *StatusWriter) updateNeeded(status, existing interface{}) bool {
+	return !equality.Semantic.DeepEqual(status, existing)
+}
+
+// mergePatch returns a merge patch which transforms existing into updated.
+func mergePatch(existing, updated interface{}) ([]byte, error) {
+	existingBytes, err := json.Marshal(existing)
+	if err != nil {
+		return nil, err
+	}
+	updatedBytes, err := json.Marshal(updated)
+	if err != nil {
+		return nil, err
 	}
-	return false
+	return jsonpatch.CreateMergePatch(existingBytes, updatedBytes)
 }
 
 func (irs *StatusWriter) setIngressRouteStatus(existing, updated *ingressroutev1.IngressRoute) error {
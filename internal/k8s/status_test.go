@@ -16,9 +16,11 @@ package k8s
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	ingressroutev1beta1 "github.com/projectcontour/contour/apis/contour/v1beta1"
 	"github.com/projectcontour/contour/apis/generated/clientset/versioned/fake"
+	gatewayapi "github.com/projectcontour/contour/apis/networking/v1alpha1"
 	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -27,15 +29,16 @@ import (
 
 func TestSetStatus(t *testing.T) {
 	tests := map[string]struct {
-		msg           string
-		desc          string
+		status        projcontour.Status
 		existing      *ingressroutev1beta1.IngressRoute
 		expectedPatch string
 		expectedVerbs []string
 	}{
 		"simple update": {
-			msg:  "valid",
-			desc: "this is a valid IR",
+			status: projcontour.Status{
+				CurrentStatus: "valid",
+				Description:   "this is a valid IR",
+			},
 			existing: &ingressroutev1beta1.IngressRoute{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "test",
@@ -50,8 +53,10 @@ func TestSetStatus(t *testing.T) {
 			expectedVerbs: []string{"patch"},
 		},
 		"no update": {
-			msg:  "valid",
-			desc: "this is a valid IR",
+			status: projcontour.Status{
+				CurrentStatus: "valid",
+				Description:   "this is a valid IR",
+			},
 			existing: &ingressroutev1beta1.IngressRoute{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "test",
@@ -66,8 +71,10 @@ func TestSetStatus(t *testing.T) {
 			expectedVerbs: []string{},
 		},
 		"replace existing status": {
-			msg:  "valid",
-			desc: "this is a valid IR",
+			status: projcontour.Status{
+				CurrentStatus: "valid",
+				Description:   "this is a valid IR",
+			},
 			existing: &ingressroutev1beta1.IngressRoute{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "test",
@@ -99,7 +106,301 @@ func TestSetStatus(t *testing.T) {
 			irs := StatusWriter{
 				Client: client,
 			}
-			if err := irs.SetStatus(tc.msg, tc.desc, tc.existing); err != nil {
+			if err := irs.SetStatus(tc.status, tc.existing); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(client.Actions()) != len(tc.expectedVerbs) {
+				t.Fatalf("Expected verbs mismatch: want: %d, got: %d", len(tc.expectedVerbs), len(client.Actions()))
+			}
+
+			if tc.expectedPatch != string(gotPatchBytes) {
+				t.Fatalf("expected patch: %s, got: %s", tc.expectedPatch, string(gotPatchBytes))
+			}
+		})
+	}
+}
+
+func TestSetHTTPProxyStatus(t *testing.T) {
+	then := metav1.NewTime(time.Date(2019, 11, 1, 12, 0, 0, 0, time.UTC))
+	now := time.Date(2019, 11, 2, 12, 0, 0, 0, time.UTC)
+
+	valid := projcontour.Status{
+		CurrentStatus: "valid",
+		Description:   "valid HTTPProxy",
+		Conditions: []projcontour.DetailedCondition{{
+			Type:    projcontour.ValidCondition,
+			Status:  projcontour.ConditionTrue,
+			Reason:  "Valid",
+			Message: "valid HTTPProxy",
+		}},
+	}
+
+	tests := map[string]struct {
+		status        projcontour.Status
+		existing      *projcontour.HTTPProxy
+		expectedPatch string
+		expectedVerbs []string
+	}{
+		"new condition": {
+			status: valid,
+			existing: &projcontour.HTTPProxy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test",
+					Namespace:  "default",
+					Generation: 3,
+				},
+			},
+			expectedPatch: `{"status":{"conditions":[{"lastTransitionTime":"2019-11-02T12:00:00Z","message":"valid HTTPProxy","observedGeneration":3,"reason":"Valid","status":"True","type":"Valid"}],"currentStatus":"valid","description":"valid HTTPProxy"}}`,
+			expectedVerbs: []string{"patch"},
+		},
+		"no update": {
+			status: valid,
+			existing: &projcontour.HTTPProxy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test",
+					Namespace:  "default",
+					Generation: 3,
+				},
+				Status: projcontour.Status{
+					CurrentStatus: "valid",
+					Description:   "valid HTTPProxy",
+					Conditions: []projcontour.DetailedCondition{{
+						Type:               projcontour.ValidCondition,
+						Status:             projcontour.ConditionTrue,
+						ObservedGeneration: 3,
+						LastTransitionTime: then,
+						Reason:             "Valid",
+						Message:            "valid HTTPProxy",
+					}},
+				},
+			},
+			expectedPatch: ``,
+			expectedVerbs: []string{},
+		},
+		"new generation keeps transition time": {
+			status: valid,
+			existing: &projcontour.HTTPProxy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test",
+					Namespace:  "default",
+					Generation: 4,
+				},
+				Status: projcontour.Status{
+					CurrentStatus: "valid",
+					Description:   "valid HTTPProxy",
+					Conditions: []projcontour.DetailedCondition{{
+						Type:               projcontour.ValidCondition,
+						Status:             projcontour.ConditionTrue,
+						ObservedGeneration: 3,
+						LastTransitionTime: then,
+						Reason:             "Valid",
+						Message:            "valid HTTPProxy",
+					}},
+				},
+			},
+			expectedPatch: `{"status":{"conditions":[{"lastTransitionTime":"2019-11-01T12:00:00Z","message":"valid HTTPProxy","observedGeneration":4,"reason":"Valid","status":"True","type":"Valid"}]}}`,
+			expectedVerbs: []string{"patch"},
+		},
+		"status change updates transition time": {
+			status: projcontour.Status{
+				CurrentStatus: "invalid",
+				Description:   "Service [missing:80] is invalid or missing",
+				Conditions: []projcontour.DetailedCondition{{
+					Type:    projcontour.ValidCondition,
+					Status:  projcontour.ConditionFalse,
+					Reason:  "Invalid",
+					Message: "Service [missing:80] is invalid or missing",
+					Errors: []string{
+						"Service [missing:80] is invalid or missing",
+						"route: Prefix conditions must start with /, api was supplied",
+					},
+				}},
+			},
+			existing: &projcontour.HTTPProxy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test",
+					Namespace:  "default",
+					Generation: 3,
+				},
+				Status: projcontour.Status{
+					CurrentStatus: "valid",
+					Description:   "valid HTTPProxy",
+					Conditions: []projcontour.DetailedCondition{{
+						Type:               projcontour.ValidCondition,
+						Status:             projcontour.ConditionTrue,
+						ObservedGeneration: 3,
+						LastTransitionTime: then,
+						Reason:             "Valid",
+						Message:            "valid HTTPProxy",
+					}},
+				},
+			},
+			expectedPatch: `{"status":{"conditions":[{"errors":["Service [missing:80] is invalid or missing","route: Prefix conditions must start with /, api was supplied"],"lastTransitionTime":"2019-11-02T12:00:00Z","message":"Service [missing:80] is invalid or missing","observedGeneration":3,"reason":"Invalid","status":"False","type":"Valid"}],"currentStatus":"invalid","description":"Service [missing:80] is invalid or missing"}}`,
+			expectedVerbs: []string{"patch"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var gotPatchBytes []byte
+			client := fake.NewSimpleClientset(tc.existing)
+			client.PrependReactor("patch", "httpproxies", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				switch patchAction := action.(type) {
+				default:
+					return true, nil, fmt.Errorf("got unexpected action of type: %T", action)
+				case k8stesting.PatchActionImpl:
+					gotPatchBytes = patchAction.GetPatch()
+					return true, tc.existing, nil
+				}
+			})
+			sw := StatusWriter{
+				Client: client,
+				now:    func() time.Time { return now },
+			}
+			if err := sw.SetStatus(tc.status, tc.existing); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(client.Actions()) != len(tc.expectedVerbs) {
+				t.Fatalf("Expected verbs mismatch: want: %d, got: %d", len(tc.expectedVerbs), len(client.Actions()))
+			}
+
+			if tc.expectedPatch != string(gotPatchBytes) {
+				t.Fatalf("expected patch: %s, got: %s", tc.expectedPatch, string(gotPatchBytes))
+			}
+		})
+	}
+}
+
+func TestSetGatewayConditions(t *testing.T) {
+	then := metav1.NewTime(time.Date(2019, 11, 1, 12, 0, 0, 0, time.UTC))
+	now := time.Date(2019, 11, 2, 12, 0, 0, 0, time.UTC)
+
+	ready := []gatewayapi.Condition{{
+		Type:    gatewayapi.ReadyCondition,
+		Status:  gatewayapi.ConditionTrue,
+		Reason:  "Valid",
+		Message: "valid Gateway",
+	}}
+
+	tests := map[string]struct {
+		conditions    []gatewayapi.Condition
+		existing      runtime.Object
+		resource      string
+		expectedPatch string
+		expectedVerbs []string
+	}{
+		"gateway without conditions": {
+			conditions: ready,
+			existing: &gatewayapi.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test",
+					Namespace:  "default",
+					Generation: 2,
+				},
+			},
+			resource:      "gateways",
+			expectedPatch: `{"status":{"conditions":[{"lastTransitionTime":"2019-11-02T12:00:00Z","message":"valid Gateway","observedGeneration":2,"reason":"Valid","status":"True","type":"Ready"}]}}`,
+			expectedVerbs: []string{"patch"},
+		},
+		"gateway unchanged": {
+			conditions: ready,
+			existing: &gatewayapi.Gateway{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test",
+					Namespace:  "default",
+					Generation: 2,
+				},
+				Status: gatewayapi.GatewayStatus{
+					Conditions: []gatewayapi.Condition{{
+						Type:               gatewayapi.ReadyCondition,
+						Status:             gatewayapi.ConditionTrue,
+						ObservedGeneration: 2,
+						LastTransitionTime: then,
+						Reason:             "Valid",
+						Message:            "valid Gateway",
+					}},
+				},
+			},
+			resource:      "gateways",
+			expectedVerbs: []string{},
+		},
+		"httproute status change": {
+			conditions: []gatewayapi.Condition{{
+				Type:    gatewayapi.AdmittedCondition,
+				Status:  gatewayapi.ConditionFalse,
+				Reason:  "Invalid",
+				Message: "Service [missing:80] is invalid or missing",
+			}},
+			existing: &gatewayapi.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test",
+					Namespace:  "default",
+					Generation: 3,
+				},
+				Status: gatewayapi.HTTPRouteStatus{
+					Conditions: []gatewayapi.Condition{{
+						Type:               gatewayapi.AdmittedCondition,
+						Status:             gatewayapi.ConditionTrue,
+						ObservedGeneration: 2,
+						LastTransitionTime: then,
+						Reason:             "Valid",
+						Message:            "valid HTTPRoute",
+					}},
+				},
+			},
+			resource:      "httproutes",
+			expectedPatch: `{"status":{"conditions":[{"lastTransitionTime":"2019-11-02T12:00:00Z","message":"Service [missing:80] is invalid or missing","observedGeneration":3,"reason":"Invalid","status":"False","type":"Admitted"}]}}`,
+			expectedVerbs: []string{"patch"},
+		},
+		"gatewayclass generation change keeps transition time": {
+			conditions: []gatewayapi.Condition{{
+				Type:    gatewayapi.AdmittedCondition,
+				Status:  gatewayapi.ConditionTrue,
+				Reason:  "Valid",
+				Message: "valid GatewayClass",
+			}},
+			existing: &gatewayapi.GatewayClass{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "contour",
+					Generation: 2,
+				},
+				Status: gatewayapi.GatewayClassStatus{
+					Conditions: []gatewayapi.Condition{{
+						Type:               gatewayapi.AdmittedCondition,
+						Status:             gatewayapi.ConditionTrue,
+						ObservedGeneration: 1,
+						LastTransitionTime: then,
+						Reason:             "Valid",
+						Message:            "valid GatewayClass",
+					}},
+				},
+			},
+			resource:      "gatewayclasses",
+			expectedPatch: `{"status":{"conditions":[{"lastTransitionTime":"2019-11-01T12:00:00Z","message":"valid GatewayClass","observedGeneration":2,"reason":"Valid","status":"True","type":"Admitted"}]}}`,
+			expectedVerbs: []string{"patch"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var gotPatchBytes []byte
+			client := fake.NewSimpleClientset(tc.existing)
+			client.PrependReactor("patch", tc.resource, func(action k8stesting.Action) (bool, runtime.Object, error) {
+				switch patchAction := action.(type) {
+				default:
+					return true, nil, fmt.Errorf("got unexpected action of type: %T", action)
+				case k8stesting.PatchActionImpl:
+					gotPatchBytes = patchAction.GetPatch()
+					return true, tc.existing, nil
+				}
+			})
+			sw := StatusWriter{
+				Client: client,
+				now:    func() time.Time { return now },
+			}
+			if err := sw.SetConditions(tc.conditions, tc.existing); err != nil {
 				t.Fatal(err)
 			}
 
@@ -42,6 +42,15 @@ type Metrics struct {
 
 	dagRebuildGauge             *prometheus.GaugeVec
 	CacheHandlerOnUpdateSummary prometheus.Summary
+
+	upstreamSuccessfulRequestsCounter *prometheus.CounterVec
+	upstreamErrorRequestsCounter      *prometheus.CounterVec
+	upstreamIssuedRequestsCounter     *prometheus.CounterVec
+	upstreamDroppedRequestsCounter    *prometheus.CounterVec
+	upstreamRequestsInProgressGauge   *prometheus.GaugeVec
+
+	xdsUnauthorizedStreamsCounter *prometheus.CounterVec
+
 	ResourceEventHandlerSummary *prometheus.SummaryVec
 
 	// Keep a local cache of metrics for comparison on updates
@@ -63,6 +72,18 @@ type Meta struct {
 	VHost, Namespace string
 }
 
+// Upstream identifies the Envoy cluster, and the Kubernetes
+// service backing it, that a load report refers to.
+type Upstream struct {
+	Cluster, Namespace, Service string
+}
+
+// UpstreamLoad holds the load reported by Envoy for an Upstream
+// over a single reporting interval.
+type UpstreamLoad struct {
+	Successful, Errors, Issued, Dropped uint64
+}
+
 const (
 	IngressRouteTotalGauge     = "contour_ingressroute_total"
 	IngressRouteRootTotalGauge = "contour_ingressroute_root_total"
@@ -78,6 +99,15 @@ const (
 
 	DAGRebuildGauge             = "contour_dagrebuild_timestamp"
 	cacheHandlerOnUpdateSummary = "contour_cachehandler_onupdate_duration_seconds"
+
+	UpstreamSuccessfulRequestsCounter = "contour_upstream_successful_requests_total"
+	UpstreamErrorRequestsCounter      = "contour_upstream_error_requests_total"
+	UpstreamIssuedRequestsCounter     = "contour_upstream_issued_requests_total"
+	UpstreamDroppedRequestsCounter    = "contour_upstream_dropped_requests_total"
+	UpstreamRequestsInProgressGauge   = "contour_upstream_requests_in_progress"
+
+	XDSUnauthorizedStreamsCounter = "contour_xds_unauthorized_streams_total"
+
 	resourceEventHandlerSummary = "contour_resourceeventhandler_duration_seconds"
 )
 
@@ -180,6 +210,48 @@ func NewMetrics(registry *prometheus.Registry) *Metrics {
 		},
 			[]string{"op"},
 		),
+		upstreamSuccessfulRequestsCounter: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: UpstreamSuccessfulRequestsCounter,
+				Help: "Total number of requests successfully completed by an upstream cluster, as reported by Envoy.",
+			},
+			[]string{"cluster", "namespace", "service"},
+		),
+		upstreamErrorRequestsCounter: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: UpstreamErrorRequestsCounter,
+				Help: "Total number of requests to an upstream cluster that completed with an error, as reported by Envoy.",
+			},
+			[]string{"cluster", "namespace", "service"},
+		),
+		upstreamIssuedRequestsCounter: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: UpstreamIssuedRequestsCounter,
+				Help: "Total number of requests issued to an upstream cluster, as reported by Envoy.",
+			},
+			[]string{"cluster", "namespace", "service"},
+		),
+		upstreamDroppedRequestsCounter: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: UpstreamDroppedRequestsCounter,
+				Help: "Total number of requests to an upstream cluster dropped by Envoy.",
+			},
+			[]string{"cluster", "namespace", "service"},
+		),
+		upstreamRequestsInProgressGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: UpstreamRequestsInProgressGauge,
+				Help: "Number of requests in progress to an upstream cluster across all reporting Envoys.",
+			},
+			[]string{"cluster", "namespace", "service"},
+		),
+		xdsUnauthorizedStreamsCounter: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: XDSUnauthorizedStreamsCounter,
+				Help: "Total number of xDS streams opened by clients whose certificate identity is not allowed.",
+			},
+			[]string{"identity"},
+		),
 	}
 	m.register(registry)
 	return &m
@@ -201,6 +273,12 @@ func (m *Metrics) register(registry *prometheus.Registry) {
 		m.dagRebuildGauge,
 		m.CacheHandlerOnUpdateSummary,
 		m.ResourceEventHandlerSummary,
+		m.upstreamSuccessfulRequestsCounter,
+		m.upstreamErrorRequestsCounter,
+		m.upstreamIssuedRequestsCounter,
+		m.upstreamDroppedRequestsCounter,
+		m.upstreamRequestsInProgressGauge,
+		m.xdsUnauthorizedStreamsCounter,
 	)
 }
 
@@ -225,6 +303,10 @@ func (m *Metrics) Zero() {
 	m.SetIngressRouteMetric(zeroes)
 	m.SetHTTPProxyMetric(zeroes)
 
+	m.AddUpstreamLoad(Upstream{}, UpstreamLoad{})
+	m.AddUpstreamRequestsInProgress(Upstream{}, 0)
+	m.xdsUnauthorizedStreamsCounter.WithLabelValues("").Add(0)
+
 	defer prometheus.NewTimer(m.CacheHandlerOnUpdateSummary).ObserveDuration()
 
 	// TODO(jpeach) add ResourceEventHandlerSummary when it gets used
@@ -235,6 +317,27 @@ func (m *Metrics) SetDAGLastRebuilt(ts time.Time) {
 	m.dagRebuildGauge.WithLabelValues().Set(float64(ts.Unix()))
 }
 
+// AddUpstreamLoad adds the load reported for u to the upstream counters.
+func (m *Metrics) AddUpstreamLoad(u Upstream, load UpstreamLoad) {
+	m.upstreamSuccessfulRequestsCounter.WithLabelValues(u.Cluster, u.Namespace, u.Service).Add(float64(load.Successful))
+	m.upstreamErrorRequestsCounter.WithLabelValues(u.Cluster, u.Namespace, u.Service).Add(float64(load.Errors))
+	m.upstreamIssuedRequestsCounter.WithLabelValues(u.Cluster, u.Namespace, u.Service).Add(float64(load.Issued))
+	m.upstreamDroppedRequestsCounter.WithLabelValues(u.Cluster, u.Namespace, u.Service).Add(float64(load.Dropped))
+}
+
+// AddUpstreamRequestsInProgress adjusts the number of requests in
+// progress to u by delta. Each reporting Envoy contributes the change
+// in its own count so the gauge holds the sum across all Envoys.
+func (m *Metrics) AddUpstreamRequestsInProgress(u Upstream, delta int64) {
+	m.upstreamRequestsInProgressGauge.WithLabelValues(u.Cluster, u.Namespace, u.Service).Add(float64(delta))
+}
+
+// IncXDSUnauthorizedStreams records an xDS stream opened by a client
+// presenting identity, which is not permitted to use the xDS API.
+func (m *Metrics) IncXDSUnauthorizedStreams(identity string) {
+	m.xdsUnauthorizedStreamsCounter.WithLabelValues(identity).Inc()
+}
+
 // SetIngressRouteMetric sets metric values for a set of IngressRoutes
 func (m *Metrics) SetIngressRouteMetric(metrics RouteMetric) {
 	// Process metrics
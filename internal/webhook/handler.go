@@ -0,0 +1,127 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ServeHTTP answers an AdmissionReview for an HTTPProxy,
+// TLSCertificateDelegation, ServiceDelegation, or Upstream, denying the
+// request if Validate finds any problems.
+func (v *Validator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var review admissionv1beta1.AdmissionReview
+	if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(w, "admission review has no request", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = v.admit(review.Request)
+	review.Request = nil
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&review); err != nil {
+		v.WithError(err).Error("failed to write admission response")
+	}
+}
+
+// admit returns the response to req.
+func (v *Validator) admit(req *admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse {
+	resp := &admissionv1beta1.AdmissionResponse{
+		UID:     req.UID,
+		Allowed: true,
+	}
+
+	obj, err := decode(req)
+	if err != nil {
+		resp.Allowed = false
+		resp.Result = &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Reason:  metav1.StatusReasonBadRequest,
+			Message: err.Error(),
+		}
+		return resp
+	}
+	if obj == nil {
+		// not a kind we validate.
+		return resp
+	}
+
+	problems := v.Validate(obj)
+	log := v.WithField("kind", req.Kind.Kind).
+		WithField("namespace", req.Namespace).
+		WithField("name", req.Name).
+		WithField("operation", req.Operation)
+	if len(problems) == 0 {
+		log.Debug("admitted")
+		return resp
+	}
+
+	log.WithField("problems", problems).Info("denied")
+	resp.Allowed = false
+	resp.Result = &metav1.Status{
+		Status:  metav1.StatusFailure,
+		Reason:  metav1.StatusReasonInvalid,
+		Message: strings.Join(problems, "; "),
+	}
+	return resp
+}
+
+// decode returns the object in req, or nil if it is not
+// a kind which is validated.
+func decode(req *admissionv1beta1.AdmissionRequest) (interface{}, error) {
+	if req.Operation != admissionv1beta1.Create && req.Operation != admissionv1beta1.Update {
+		return nil, nil
+	}
+	if req.Kind.Group != projcontour.GroupName {
+		return nil, nil
+	}
+
+	var obj interface{}
+	switch req.Kind.Kind {
+	case "HTTPProxy":
+		obj = new(projcontour.HTTPProxy)
+	case "TLSCertificateDelegation":
+		obj = new(projcontour.TLSCertificateDelegation)
+	case "ServiceDelegation":
+		obj = new(projcontour.ServiceDelegation)
+	case "Upstream":
+		obj = new(projcontour.Upstream)
+	default:
+		return nil, nil
+	}
+	if err := json.Unmarshal(req.Object.Raw, obj); err != nil {
+		return nil, fmt.Errorf("decoding %s: %v", req.Kind.Kind, err)
+	}
+	if om := obj.(metav1.Object); om.GetNamespace() == "" {
+		om.SetNamespace(req.Namespace)
+	}
+	return obj, nil
+}
@@ -0,0 +1,196 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhook implements a Kubernetes validating admission webhook
+// which rejects HTTPProxy, TLSCertificateDelegation, ServiceDelegation,
+// and Upstream objects that Contour would report as invalid.
+package webhook
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+
+	ingressroutev1 "github.com/projectcontour/contour/apis/contour/v1beta1"
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/k8s"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Validator implements cache.ResourceEventHandler, keeping a copy of
+// each object Contour builds its configuration from so that an object
+// can be validated against the rest of the cluster before it is admitted.
+type Validator struct {
+	// RootNamespaces, IngressClasses, ListenerClasses, and
+	// DisablePermitInsecure must match the configuration of
+	// Contour's dag.Builder.
+	RootNamespaces        []string
+	IngressClasses        []string
+	ListenerClasses       []string
+	DisablePermitInsecure bool
+
+	logrus.FieldLogger
+
+	mu      sync.Mutex
+	objects map[string]interface{}
+
+	// generation counts the changes to objects.
+	generation int
+
+	// statuses holds the status of each of objects. It is nil if
+	// objects has changed since the statuses were last built.
+	statuses map[string]dag.Status
+}
+
+func (v *Validator) OnAdd(obj interface{}) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.objects == nil {
+		v.objects = make(map[string]interface{})
+	}
+	if key, ok := objectKey(obj); ok {
+		v.objects[key] = obj
+		v.generation++
+		v.statuses = nil
+	}
+}
+
+func (v *Validator) OnUpdate(oldObj, newObj interface{}) {
+	v.OnDelete(oldObj)
+	v.OnAdd(newObj)
+}
+
+func (v *Validator) OnDelete(obj interface{}) {
+	if d, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = d.Obj
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if key, ok := objectKey(obj); ok {
+		delete(v.objects, key)
+		v.generation++
+		v.statuses = nil
+	}
+}
+
+// Validate returns a description of each problem found if obj were
+// admitted to the cluster. Validate reports the problems with obj itself,
+// and with any other object which is valid now but would not be if obj
+// were admitted.
+func (v *Validator) Validate(obj interface{}) []string {
+	key, ok := objectKey(obj)
+	if !ok {
+		return nil
+	}
+
+	objects, before := v.snapshot()
+	objects[key] = obj
+	after := v.build(objects)
+
+	var problems []string
+	if st, ok := after[key]; ok && st.Status == dag.StatusInvalid {
+		problems = append(problems, st.Errors...)
+	}
+
+	var invalidated []string
+	for k, st := range after {
+		if k == key || st.Status != dag.StatusInvalid {
+			continue
+		}
+		if prev, ok := before[k]; ok && prev.Status == dag.StatusValid {
+			invalidated = append(invalidated, fmt.Sprintf("%s would become invalid: %s", k, strings.Join(st.Errors, ", ")))
+		}
+	}
+	sort.Strings(invalidated)
+	return append(problems, invalidated...)
+}
+
+// snapshot returns a copy of the current set of objects, and the
+// status of each. The statuses are only rebuilt when the set of objects
+// has changed, so an admission does not wait while a DAG is built for
+// each of the objects already in the cluster. They are built without
+// holding v.mu, so the informers are not blocked while they are.
+func (v *Validator) snapshot() (map[string]interface{}, map[string]dag.Status) {
+	v.mu.Lock()
+	objects := make(map[string]interface{}, len(v.objects)+1)
+	for k, o := range v.objects {
+		objects[k] = o
+	}
+	generation, statuses := v.generation, v.statuses
+	v.mu.Unlock()
+
+	if statuses != nil {
+		return objects, statuses
+	}
+	statuses = v.build(objects)
+
+	// keep the statuses unless objects changed while they were built.
+	v.mu.Lock()
+	if v.generation == generation {
+		v.statuses = statuses
+	}
+	v.mu.Unlock()
+	return objects, statuses
+}
+
+// build builds a DAG from objects and returns the status of each
+// object keyed by kind, namespace, and name.
+func (v *Validator) build(objects map[string]interface{}) map[string]dag.Status {
+	// the cache logs problems with each object as it is inserted,
+	// which has been done already by Contour.
+	log := logrus.New()
+	log.SetOutput(ioutil.Discard)
+
+	builder := dag.Builder{
+		Source: dag.KubernetesCache{
+			RootNamespaces: v.RootNamespaces,
+			IngressClasses: v.IngressClasses,
+			FieldLogger:    log,
+		},
+		DisablePermitInsecure: v.DisablePermitInsecure,
+		ListenerClasses:       v.ListenerClasses,
+	}
+	for _, o := range objects {
+		builder.Source.Insert(o)
+	}
+
+	statuses := make(map[string]dag.Status)
+	for _, st := range builder.Build().Statuses() {
+		if k, ok := objectKey(st.Object); ok {
+			statuses[k] = st
+		}
+	}
+	return statuses
+}
+
+// objectKey returns the kind, namespace, and name of obj.
+func objectKey(obj interface{}) (string, bool) {
+	o, ok := obj.(dag.Object)
+	if !ok {
+		return "", false
+	}
+	kind := k8s.KindOf(obj)
+	switch obj.(type) {
+	case *ingressroutev1.TLSCertificateDelegation:
+		// distinguish from projectcontour.io TLSCertificateDelegations.
+		kind += ".contour.heptio.com"
+	}
+	if kind == "" {
+		return "", false
+	}
+	om := o.GetObjectMeta()
+	return kind + " " + om.GetNamespace() + "/" + om.GetName(), true
+}
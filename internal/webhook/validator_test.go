@@ -0,0 +1,288 @@
+// Copyright © 2019 VMware
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/assert"
+	"github.com/sirupsen/logrus"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestValidatorValidate(t *testing.T) {
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Protocol:   "TCP",
+				Port:       8080,
+				TargetPort: intstr.FromInt(8080),
+			}},
+		},
+	}
+
+	proxy := func(name, fqdn string, routes ...projcontour.Route) *projcontour.HTTPProxy {
+		p := &projcontour.HTTPProxy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: projcontour.HTTPProxySpec{
+				Routes: routes,
+			},
+		}
+		if fqdn != "" {
+			p.Spec.VirtualHost = &projcontour.VirtualHost{Fqdn: fqdn}
+		}
+		return p
+	}
+	route := func(service string, port int) projcontour.Route {
+		return projcontour.Route{
+			Services: []projcontour.Service{{Name: service, Port: port}},
+		}
+	}
+
+	root := proxy("root", "example.com", route("kuard", 8080))
+
+	tests := map[string]struct {
+		objs []interface{}
+		obj  interface{}
+		want []string
+	}{
+		"valid httpproxy": {
+			objs: []interface{}{svc},
+			obj:  root,
+			want: nil,
+		},
+		"missing service": {
+			objs: []interface{}{svc},
+			obj:  proxy("root", "example.com", route("missing", 8080)),
+			want: []string{"Service [missing:8080] is invalid or missing"},
+		},
+		"every invalid route is reported": {
+			objs: []interface{}{svc},
+			obj: proxy("root", "example.com", route("missing", 8080), projcontour.Route{
+				Conditions: []projcontour.Condition{{Prefix: "api"}},
+				Services:   []projcontour.Service{{Name: "kuard", Port: 8080}},
+			}),
+			want: []string{
+				"Service [missing:8080] is invalid or missing",
+				"route: Prefix conditions must start with /, api was supplied",
+			},
+		},
+		"duplicate fqdn": {
+			objs: []interface{}{svc, root},
+			obj:  proxy("other", "example.com", route("kuard", 8080)),
+			want: []string{
+				`fqdn "example.com" is used in multiple HTTPProxies: default/other, default/root`,
+				`HTTPProxy default/root would become invalid: fqdn "example.com" is used in multiple HTTPProxies: default/other, default/root`,
+			},
+		},
+		"updating an object replaces the existing version": {
+			objs: []interface{}{svc, proxy("root", "example.com", route("missing", 8080))},
+			obj:  root,
+			want: nil,
+		},
+		"orphaned httpproxy is admitted": {
+			objs: []interface{}{svc},
+			obj:  proxy("child", "", route("kuard", 8080)),
+			want: nil,
+		},
+		"included httpproxy is validated through its root": {
+			objs: []interface{}{svc, &projcontour.HTTPProxy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "root",
+					Namespace: "default",
+				},
+				Spec: projcontour.HTTPProxySpec{
+					VirtualHost: &projcontour.VirtualHost{Fqdn: "example.com"},
+					Includes: []projcontour.Include{{
+						Name: "child",
+					}},
+				},
+			}, proxy("child", "", route("kuard", 8080))},
+			obj:  proxy("child", "", route("kuard", 9999)),
+			want: []string{"Service [kuard:9999] is invalid or missing"},
+		},
+		"include cycle": {
+			objs: []interface{}{svc, &projcontour.HTTPProxy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "root",
+					Namespace: "default",
+				},
+				Spec: projcontour.HTTPProxySpec{
+					VirtualHost: &projcontour.VirtualHost{Fqdn: "example.com"},
+					Includes: []projcontour.Include{{
+						Name: "child",
+					}},
+				},
+			}, proxy("child", "", route("kuard", 8080))},
+			obj: &projcontour.HTTPProxy{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "child",
+					Namespace: "default",
+				},
+				Spec: projcontour.HTTPProxySpec{
+					Includes: []projcontour.Include{{
+						Name: "child",
+					}},
+				},
+			},
+			want: []string{"include creates a delegation cycle: default/root -> default/child -> default/child"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			v := &Validator{
+				FieldLogger: testLogger(),
+			}
+			for _, o := range tc.objs {
+				v.OnAdd(o)
+			}
+			assert.Equal(t, tc.want, v.Validate(tc.obj))
+		})
+	}
+}
+
+func TestValidatorFollowsInformer(t *testing.T) {
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Protocol:   "TCP",
+				Port:       8080,
+				TargetPort: intstr.FromInt(8080),
+			}},
+		},
+	}
+	proxy := func(name string) *projcontour.HTTPProxy {
+		return &projcontour.HTTPProxy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: projcontour.HTTPProxySpec{
+				VirtualHost: &projcontour.VirtualHost{Fqdn: "example.com"},
+				Routes: []projcontour.Route{{
+					Services: []projcontour.Service{{Name: "kuard", Port: 8080}},
+				}},
+			},
+		}
+	}
+
+	v := &Validator{
+		FieldLogger: testLogger(),
+	}
+	v.OnAdd(svc)
+	assert.Equal(t, []string(nil), v.Validate(proxy("other")))
+
+	// the statuses of the objects already admitted must be rebuilt
+	// once the informer adds to them.
+	v.OnAdd(proxy("root"))
+	assert.Equal(t, []string{
+		`fqdn "example.com" is used in multiple HTTPProxies: default/other, default/root`,
+		`HTTPProxy default/root would become invalid: fqdn "example.com" is used in multiple HTTPProxies: default/other, default/root`,
+	}, v.Validate(proxy("other")))
+
+	v.OnDelete(proxy("root"))
+	assert.Equal(t, []string(nil), v.Validate(proxy("other")))
+}
+
+func TestValidatorServeHTTP(t *testing.T) {
+	v := &Validator{
+		FieldLogger: testLogger(),
+	}
+
+	review := func(obj runtime.Object) *bytes.Buffer {
+		raw, err := json.Marshal(obj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		err = json.NewEncoder(&buf).Encode(&admissionv1beta1.AdmissionReview{
+			Request: &admissionv1beta1.AdmissionRequest{
+				UID: "0f3e2c6a",
+				Kind: metav1.GroupVersionKind{
+					Group:   projcontour.GroupName,
+					Version: "v1",
+					Kind:    "HTTPProxy",
+				},
+				Namespace: "default",
+				Name:      "root",
+				Operation: admissionv1beta1.Create,
+				Object:    runtime.RawExtension{Raw: raw},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &buf
+	}
+
+	obj := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "root",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{Fqdn: "example.com"},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{Name: "missing", Port: 80}},
+			}},
+		},
+	}
+
+	rec := httptest.NewRecorder()
+	v.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/validate", review(obj)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, rec.Code, rec.Body)
+	}
+
+	var got admissionv1beta1.AdmissionReview
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, &admissionv1beta1.AdmissionResponse{
+		UID:     "0f3e2c6a",
+		Allowed: false,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Reason:  metav1.StatusReasonInvalid,
+			Message: "Service [missing:80] is invalid or missing",
+		},
+	}, got.Response)
+}
+
+func testLogger() logrus.FieldLogger {
+	log := logrus.New()
+	log.SetOutput(ioutil.Discard)
+	return log
+}